@@ -0,0 +1,192 @@
+package sqrl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgconn"
+	"strings"
+)
+
+// AlterTableBuilder builds SQL ALTER TABLE statements.
+//
+// Multiple actions are combined into a single statement. RENAME and
+// ATTACH/DETACH PARTITION actions can not be combined with any other action
+// and must be issued on their own.
+type AlterTableBuilder struct {
+	StatementBuilderType
+
+	table    string
+	ifExists bool
+	actions  []Sqlizer
+	single   []Sqlizer
+}
+
+// NewAlterTableBuilder creates new instance of AlterTableBuilder
+func NewAlterTableBuilder(b StatementBuilderType) *AlterTableBuilder {
+	return &AlterTableBuilder{StatementBuilderType: b}
+}
+
+// ExecContext builds and Execs the statement using given context.
+func (b *AlterTableBuilder) ExecContext(ctx context.Context, pool instapgxpool.Pool) (pgconn.CommandTag, error) {
+	return b.execContext(ctx, pool, b)
+}
+
+// ExecAttached is like ExecContext, using the pool set with RunWithPool.
+func (b *AlterTableBuilder) ExecAttached(ctx context.Context) (pgconn.CommandTag, error) {
+	return b.ExecContext(ctx, nil)
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// statement.
+func (b *AlterTableBuilder) PlaceholderFormat(f PlaceholderFormat) *AlterTableBuilder {
+	b.placeholderFormat = f
+	return b
+}
+
+// ToSql builds the statement into a SQL string and bound args.
+func (b *AlterTableBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
+	if len(b.table) == 0 {
+		err = fmt.Errorf("alter table statements must specify a table")
+		return
+	}
+	if len(b.actions) == 0 && len(b.single) == 0 {
+		err = fmt.Errorf("alter table statements must have at least one action")
+		return
+	}
+	if len(b.single) > 1 || (len(b.single) > 0 && len(b.actions) > 0) {
+		err = fmt.Errorf("alter table rename and partition actions can not be combined with other actions")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	sql.WriteString("ALTER TABLE ")
+	if b.ifExists {
+		sql.WriteString("IF EXISTS ")
+	}
+	sql.WriteString(b.table)
+	sql.WriteString(" ")
+
+	if len(b.single) > 0 {
+		args, err = appendToSql(b.single, sql, "", args)
+	} else {
+		args, err = appendToSql(b.actions, sql, ", ", args)
+	}
+	if err != nil {
+		return
+	}
+
+	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
+	return
+}
+
+// Table sets the name of the table to be altered.
+func (b *AlterTableBuilder) Table(table string) *AlterTableBuilder {
+	b.table = table
+	return b
+}
+
+// IfExists adds IF EXISTS to the statement.
+func (b *AlterTableBuilder) IfExists() *AlterTableBuilder {
+	b.ifExists = true
+	return b
+}
+
+// Action adds a raw action to the statement, e.g. "ENABLE ROW LEVEL SECURITY".
+func (b *AlterTableBuilder) Action(action string) *AlterTableBuilder {
+	b.actions = append(b.actions, newPart(action))
+	return b
+}
+
+// AddColumn adds an ADD COLUMN action. Column constraints are appended in the
+// given order, see CreateTableBuilder.Column.
+func (b *AlterTableBuilder) AddColumn(name, dataType string, constraints ...string) *AlterTableBuilder {
+	action := fmt.Sprintf("ADD COLUMN %s %s", name, dataType)
+	if len(constraints) > 0 {
+		action += " " + strings.Join(constraints, " ")
+	}
+	return b.Action(action)
+}
+
+// DropColumn adds a DROP COLUMN action.
+func (b *AlterTableBuilder) DropColumn(name string) *AlterTableBuilder {
+	return b.Action("DROP COLUMN " + name)
+}
+
+// AlterColumnType adds an ALTER COLUMN ... TYPE action. The optional using
+// expression converts existing values to the new type.
+func (b *AlterTableBuilder) AlterColumnType(name, dataType string, using ...string) *AlterTableBuilder {
+	action := fmt.Sprintf("ALTER COLUMN %s TYPE %s", name, dataType)
+	if len(using) > 0 {
+		action += " USING " + using[0]
+	}
+	return b.Action(action)
+}
+
+// SetDefault adds an ALTER COLUMN ... SET DEFAULT action.
+func (b *AlterTableBuilder) SetDefault(column, expr string) *AlterTableBuilder {
+	return b.Action(fmt.Sprintf("ALTER COLUMN %s SET DEFAULT %s", column, expr))
+}
+
+// DropDefault adds an ALTER COLUMN ... DROP DEFAULT action.
+func (b *AlterTableBuilder) DropDefault(column string) *AlterTableBuilder {
+	return b.Action(fmt.Sprintf("ALTER COLUMN %s DROP DEFAULT", column))
+}
+
+// SetNotNull adds an ALTER COLUMN ... SET NOT NULL action.
+func (b *AlterTableBuilder) SetNotNull(column string) *AlterTableBuilder {
+	return b.Action(fmt.Sprintf("ALTER COLUMN %s SET NOT NULL", column))
+}
+
+// DropNotNull adds an ALTER COLUMN ... DROP NOT NULL action.
+func (b *AlterTableBuilder) DropNotNull(column string) *AlterTableBuilder {
+	return b.Action(fmt.Sprintf("ALTER COLUMN %s DROP NOT NULL", column))
+}
+
+// AddConstraint adds an ADD CONSTRAINT action, for example:
+//   AddConstraint("orders_user_fk", "FOREIGN KEY (user_id) REFERENCES users (id)")
+func (b *AlterTableBuilder) AddConstraint(name, definition string) *AlterTableBuilder {
+	return b.Action(fmt.Sprintf("ADD CONSTRAINT %s %s", name, definition))
+}
+
+// DropConstraint adds a DROP CONSTRAINT action.
+func (b *AlterTableBuilder) DropConstraint(name string) *AlterTableBuilder {
+	return b.Action("DROP CONSTRAINT " + name)
+}
+
+// RenameTo renames the table.
+func (b *AlterTableBuilder) RenameTo(name string) *AlterTableBuilder {
+	b.single = append(b.single, newPart("RENAME TO "+name))
+	return b
+}
+
+// RenameColumn renames a column of the table.
+func (b *AlterTableBuilder) RenameColumn(from, to string) *AlterTableBuilder {
+	b.single = append(b.single, newPart(fmt.Sprintf("RENAME COLUMN %s TO %s", from, to)))
+	return b
+}
+
+// RenameConstraint renames a constraint of the table.
+func (b *AlterTableBuilder) RenameConstraint(from, to string) *AlterTableBuilder {
+	b.single = append(b.single, newPart(fmt.Sprintf("RENAME CONSTRAINT %s TO %s", from, to)))
+	return b
+}
+
+// AttachPartition attaches an existing table as a partition with the given
+// bound, see RangeBound, ListBound, HashBound and DefaultBound.
+//
+// ALTER TABLE ... ATTACH PARTITION is PostgreSQL specific extension
+func (b *AlterTableBuilder) AttachPartition(partition string, bound Sqlizer) *AlterTableBuilder {
+	b.single = append(b.single, ConcatExpr("ATTACH PARTITION "+partition+" ", bound))
+	return b
+}
+
+// DetachPartition detaches a partition, turning it into a standalone table.
+//
+// ALTER TABLE ... DETACH PARTITION is PostgreSQL specific extension
+func (b *AlterTableBuilder) DetachPartition(partition string) *AlterTableBuilder {
+	b.single = append(b.single, newPart("DETACH PARTITION "+partition))
+	return b
+}
@@ -0,0 +1,80 @@
+package sqrl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlterTableBuilderToSql(t *testing.T) {
+	b := AlterTable("orders").
+		IfExists().
+		AddColumn("note", "text", "NOT NULL", "DEFAULT ''").
+		DropColumn("legacy").
+		AlterColumnType("total", "numeric(14,2)").
+		AlterColumnType("code", "int", "code::int").
+		SetDefault("status", "'new'").
+		DropDefault("note").
+		SetNotNull("user_id").
+		DropNotNull("total").
+		AddConstraint("orders_user_fk", "FOREIGN KEY (user_id) REFERENCES users (id)").
+		DropConstraint("orders_old_check")
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql :=
+		"ALTER TABLE IF EXISTS orders " +
+			"ADD COLUMN note text NOT NULL DEFAULT '', DROP COLUMN legacy, " +
+			"ALTER COLUMN total TYPE numeric(14,2), ALTER COLUMN code TYPE int USING code::int, " +
+			"ALTER COLUMN status SET DEFAULT 'new', ALTER COLUMN note DROP DEFAULT, " +
+			"ALTER COLUMN user_id SET NOT NULL, ALTER COLUMN total DROP NOT NULL, " +
+			"ADD CONSTRAINT orders_user_fk FOREIGN KEY (user_id) REFERENCES users (id), " +
+			"DROP CONSTRAINT orders_old_check"
+	assert.Equal(t, expectedSql, sql)
+	assert.Empty(t, args)
+}
+
+func TestAlterTableBuilderRename(t *testing.T) {
+	sql, _, err := AlterTable("a").RenameTo("b").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "ALTER TABLE a RENAME TO b", sql)
+
+	sql, _, err = AlterTable("a").RenameColumn("x", "y").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "ALTER TABLE a RENAME COLUMN x TO y", sql)
+
+	sql, _, err = AlterTable("a").RenameConstraint("c1", "c2").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "ALTER TABLE a RENAME CONSTRAINT c1 TO c2", sql)
+}
+
+func TestAlterTableBuilderToSqlErr(t *testing.T) {
+	_, _, err := AlterTable("").DropColumn("a").ToSql()
+	assert.Error(t, err)
+
+	_, _, err = AlterTable("a").ToSql()
+	assert.Error(t, err)
+
+	_, _, err = AlterTable("a").DropColumn("x").RenameTo("b").ToSql()
+	assert.Error(t, err)
+}
+
+func TestAlterTableBuilderPartitions(t *testing.T) {
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	sql, args, err := AlterTable("events").
+		AttachPartition("events_2020_01", RangeBound(from, from.AddDate(0, 1, 0))).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "ALTER TABLE events ATTACH PARTITION events_2020_01 "+
+		"FOR VALUES FROM ('2020-01-01T00:00:00Z') TO ('2020-02-01T00:00:00Z')", sql)
+	assert.Empty(t, args)
+
+	sql, _, err = AlterTable("events").DetachPartition("events_2019_12").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "ALTER TABLE events DETACH PARTITION events_2019_12", sql)
+
+	_, _, err = AlterTable("events").DetachPartition("a").DetachPartition("b").ToSql()
+	assert.Error(t, err)
+}
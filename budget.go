@@ -0,0 +1,68 @@
+package sqrl
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// QueryBudgetError is returned by the execution helpers for statements run
+// with a context whose query budget is exhausted, see WithQueryBudget.
+type QueryBudgetError struct {
+	Limit int
+	SQL   string
+}
+
+func (e *QueryBudgetError) Error() string {
+	return fmt.Sprintf("query budget of %d statements exceeded: %s", e.Limit, e.SQL)
+}
+
+type queryBudgetKey struct{}
+
+type queryBudget struct {
+	limit  int
+	logger Logger
+	count  int64
+}
+
+// WithQueryBudget returns a copy of ctx which allows limit statements to be
+// run through the execution helpers, e.g. per HTTP request, to find N+1
+// patterns created by queries in loops. Statements beyond the limit fail
+// with a *QueryBudgetError without being sent to the database, or, if
+// logger is not nil, are run and reported to it once.
+//
+// Ex:
+//     ctx = sqrl.WithQueryBudget(ctx, 50, logger)
+//     ...
+//     log.Printf("request ran %d statements", sqrl.QueryCount(ctx))
+func WithQueryBudget(ctx context.Context, limit int, logger Logger) context.Context {
+	return context.WithValue(ctx, queryBudgetKey{}, &queryBudget{limit: limit, logger: logger})
+}
+
+// QueryCount returns the number of statements run with ctx, or a context
+// derived from it, since WithQueryBudget. It is 0 if ctx carries no budget.
+func QueryCount(ctx context.Context) int {
+	if budget, ok := ctx.Value(queryBudgetKey{}).(*queryBudget); ok {
+		return int(atomic.LoadInt64(&budget.count))
+	}
+	return 0
+}
+
+// spendQueryBudget counts query against the budget of ctx, if any.
+func spendQueryBudget(ctx context.Context, query string) error {
+	budget, ok := ctx.Value(queryBudgetKey{}).(*queryBudget)
+	if !ok {
+		return nil
+	}
+	count := atomic.AddInt64(&budget.count, 1)
+	if count <= int64(budget.limit) {
+		return nil
+	}
+	if budget.logger == nil {
+		return &QueryBudgetError{Limit: budget.limit, SQL: query}
+	}
+	if count == int64(budget.limit)+1 {
+		budget.logger.Warnw("query budget exceeded", "limit", budget.limit, "sql", query)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package sqrl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryBudget(t *testing.T) {
+	pool := newPoolStub()
+	ctx := WithQueryBudget(context.Background(), 2, nil)
+
+	for i := 0; i < 2; i++ {
+		_, err := ExecWithContext(ctx, pool, Update("users").Set("active", true))
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, QueryCount(ctx))
+
+	_, err := QueryWithContext(ctx, pool, Select("id").From("users"))
+	var budgetErr *QueryBudgetError
+	if assert.True(t, errors.As(err, &budgetErr)) {
+		assert.Equal(t, 2, budgetErr.Limit)
+	}
+	assert.EqualError(t, err, "query budget of 2 statements exceeded: SELECT id FROM users")
+	assert.Len(t, pool.stub.sqls, 2)
+
+	err = QueryRowWithContext(ctx, pool, Select("id").From("users")).Scan()
+	assert.True(t, errors.As(err, &budgetErr))
+	assert.Equal(t, 4, QueryCount(ctx))
+}
+
+func TestQueryBudgetLogger(t *testing.T) {
+	pool := newPoolStub()
+	logger := &loggerStub{}
+	ctx := WithQueryBudget(context.Background(), 1, logger)
+
+	for i := 0; i < 3; i++ {
+		_, err := ExecWithContext(ctx, pool, Update("users").Set("active", true))
+		assert.NoError(t, err)
+	}
+	assert.Len(t, pool.stub.sqls, 3)
+	if assert.Len(t, logger.entries, 1) {
+		assert.Equal(t, "query budget exceeded", logger.entries[0][0])
+		assert.Equal(t, 1, logger.value(0, "limit"))
+	}
+}
+
+func TestQueryCountWithoutBudget(t *testing.T) {
+	pool := newPoolStub()
+	_, err := ExecWithContext(context.Background(), pool, Update("users").Set("active", true))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, QueryCount(context.Background()))
+}
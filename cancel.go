@@ -0,0 +1,45 @@
+package sqrl
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// CancelledQuery describes a query aborted by the cancellation of its
+// context, see SetCancellationHook.
+type CancelledQuery struct {
+	StmtType string
+	Table    string
+	SQL      string
+	Duration time.Duration
+	// Err is the error of the context, context.Canceled or
+	// context.DeadlineExceeded.
+	Err error
+}
+
+type cancellationHookHolder struct {
+	hook func(ctx context.Context, q CancelledQuery)
+}
+
+var cancellationHook atomic.Value
+
+// SetCancellationHook sets a function called for queries run through the
+// execution helpers which fail because their context was cancelled or its
+// deadline exceeded, as opposed to failing on the server, e.g. to monitor
+// how often deadlines kill statements or to clean up resources tied to the
+// request. The hook is called before the error is returned. Passing nil
+// removes the hook.
+//
+// Ex:
+//     sqrl.SetCancellationHook(func(ctx context.Context, q sqrl.CancelledQuery) {
+//         cancelled.WithLabelValues(q.StmtType, q.Table).Inc()
+//     })
+func SetCancellationHook(hook func(ctx context.Context, q CancelledQuery)) {
+	cancellationHook.Store(cancellationHookHolder{hook: hook})
+}
+
+func loadCancellationHook() func(ctx context.Context, q CancelledQuery) {
+	holder, _ := cancellationHook.Load().(cancellationHookHolder)
+	return holder.hook
+}
@@ -0,0 +1,57 @@
+package sqrl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func withCancellationHook(t *testing.T) *[]CancelledQuery {
+	var cancelled []CancelledQuery
+	SetCancellationHook(func(ctx context.Context, q CancelledQuery) {
+		cancelled = append(cancelled, q)
+	})
+	t.Cleanup(func() { SetCancellationHook(nil) })
+	return &cancelled
+}
+
+func TestCancellationHook(t *testing.T) {
+	cancelled := withCancellationHook(t)
+	pool := newPoolStub()
+	pool.stub.err = context.Canceled
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := ExecWithContext(ctx, pool, Update("users").Set("active", true))
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	err = QueryRowWithContext(ctx, pool, Select("id").From("posts")).Scan()
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	if assert.Len(t, *cancelled, 2) {
+		q := (*cancelled)[0]
+		assert.Equal(t, "update", q.StmtType)
+		assert.Equal(t, "users", q.Table)
+		assert.Equal(t, "UPDATE users SET active = ?", q.SQL)
+		assert.Equal(t, context.Canceled, q.Err)
+		assert.Equal(t, "posts", (*cancelled)[1].Table)
+	}
+}
+
+func TestCancellationHookServerError(t *testing.T) {
+	cancelled := withCancellationHook(t)
+	pool := newPoolStub()
+	pool.stub.err = errors.New("relation does not exist")
+
+	_, err := ExecWithContext(context.Background(), pool, Update("users").Set("active", true))
+	assert.Error(t, err)
+
+	pool.stub.err = nil
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ExecWithContext(ctx, pool, Update("users").Set("active", true))
+	assert.NoError(t, err)
+	assert.Empty(t, *cancelled)
+}
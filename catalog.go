@@ -0,0 +1,115 @@
+package sqrl
+
+import (
+	"context"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgx/v4"
+)
+
+// TableInfo describes a table or view as listed by TablesInSchema.
+type TableInfo struct {
+	Schema string
+	Name   string
+	// Type is "BASE TABLE", "VIEW", "FOREIGN" or "LOCAL TEMPORARY".
+	Type string
+}
+
+// ColumnInfo describes a column as listed by ColumnsOf.
+type ColumnInfo struct {
+	Name string
+	// DataType is the formatted type including modifiers, e.g.
+	// "character varying(255)".
+	DataType string
+	Nullable bool
+	// Default is the default expression, or nil if the column has none.
+	Default  *string
+	Position int
+}
+
+// IndexInfo describes an index as listed by IndexesOf.
+type IndexInfo struct {
+	Name    string
+	Unique  bool
+	Primary bool
+	// Definition is the CREATE INDEX statement of the index.
+	Definition string
+}
+
+// CatalogQuery is a ready-made query against information_schema or
+// pg_catalog whose rows are loaded into values of type T. The embedded
+// SelectBuilder can be used to refine the query.
+//
+// Ex:
+//     columns, err := ColumnsOf("public.users").Load(ctx, pool)
+type CatalogQuery[T interface{}] struct {
+	*SelectBuilder
+
+	scan func(rows pgx.Rows) (T, error)
+}
+
+// Load runs the query and returns its rows.
+func (q *CatalogQuery[T]) Load(ctx context.Context, pool instapgxpool.Pool) ([]T, error) {
+	values := make([]T, 0)
+	err := QueryEach(ctx, pool, q.SelectBuilder, func(rows pgx.Rows) error {
+		value, err := q.scan(rows)
+		values = append(values, value)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// TablesInSchema lists the tables and views of schema, ordered by name.
+func TablesInSchema(schema string) *CatalogQuery[TableInfo] {
+	return &CatalogQuery[TableInfo]{
+		SelectBuilder: Select("table_schema", "table_name", "table_type").
+			From("information_schema.tables").
+			Where(Eq{"table_schema": schema}).
+			OrderBy("table_name"),
+		scan: func(rows pgx.Rows) (t TableInfo, err error) {
+			err = rows.Scan(&t.Schema, &t.Name, &t.Type)
+			return
+		},
+	}
+}
+
+// ColumnsOf lists the columns of table, ordered by position. table may be
+// schema qualified and is otherwise looked up in the search path.
+func ColumnsOf(table string) *CatalogQuery[ColumnInfo] {
+	return &CatalogQuery[ColumnInfo]{
+		SelectBuilder: Select(
+			"a.attname",
+			"format_type(a.atttypid, a.atttypmod)",
+			"NOT a.attnotnull",
+			"pg_get_expr(d.adbin, d.adrelid)",
+			"a.attnum",
+		).
+			From("pg_catalog.pg_attribute a").
+			LeftJoin("pg_catalog.pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum").
+			Where("a.attrelid = ?::regclass", table).
+			Where("a.attnum > 0 AND NOT a.attisdropped").
+			OrderBy("a.attnum"),
+		scan: func(rows pgx.Rows) (c ColumnInfo, err error) {
+			err = rows.Scan(&c.Name, &c.DataType, &c.Nullable, &c.Default, &c.Position)
+			return
+		},
+	}
+}
+
+// IndexesOf lists the indexes of table, ordered by name. table may be
+// schema qualified and is otherwise looked up in the search path.
+func IndexesOf(table string) *CatalogQuery[IndexInfo] {
+	return &CatalogQuery[IndexInfo]{
+		SelectBuilder: Select("i.relname", "x.indisunique", "x.indisprimary", "pg_get_indexdef(x.indexrelid)").
+			From("pg_catalog.pg_index x").
+			Join("pg_catalog.pg_class i ON i.oid = x.indexrelid").
+			Where("x.indrelid = ?::regclass", table).
+			OrderBy("i.relname"),
+		scan: func(rows pgx.Rows) (i IndexInfo, err error) {
+			err = rows.Scan(&i.Name, &i.Unique, &i.Primary, &i.Definition)
+			return
+		},
+	}
+}
@@ -0,0 +1,62 @@
+package sqrl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTablesInSchema(t *testing.T) {
+	sql, args, err := TablesInSchema("public").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT table_schema, table_name, table_type FROM information_schema.tables WHERE table_schema = ? ORDER BY table_name", sql)
+	assert.Equal(t, []interface{}{"public"}, args)
+
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{"public", "users", "BASE TABLE"}, {"public", "active_users", "VIEW"}}}
+
+	tables, err := TablesInSchema("public").Load(context.Background(), pool)
+	assert.NoError(t, err)
+	assert.Equal(t, []TableInfo{
+		{Schema: "public", Name: "users", Type: "BASE TABLE"},
+		{Schema: "public", Name: "active_users", Type: "VIEW"},
+	}, tables)
+}
+
+func TestColumnsOf(t *testing.T) {
+	sql, args, err := ColumnsOf("public.users").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a.attname, format_type(a.atttypid, a.atttypmod), NOT a.attnotnull, pg_get_expr(d.adbin, d.adrelid), a.attnum "+
+		"FROM pg_catalog.pg_attribute a LEFT JOIN pg_catalog.pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum "+
+		"WHERE a.attrelid = ?::regclass AND a.attnum > 0 AND NOT a.attisdropped ORDER BY a.attnum", sql)
+	assert.Equal(t, []interface{}{"public.users"}, args)
+
+	def := "now()"
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{"id", "bigint", false, nil, 1}, {"created_at", "timestamp with time zone", true, &def, 2}}}
+
+	columns, err := ColumnsOf("users").Load(context.Background(), pool)
+	assert.NoError(t, err)
+	assert.Equal(t, []ColumnInfo{
+		{Name: "id", DataType: "bigint", Position: 1},
+		{Name: "created_at", DataType: "timestamp with time zone", Nullable: true, Default: &def, Position: 2},
+	}, columns)
+}
+
+func TestIndexesOf(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{"users_pkey", true, true, "CREATE UNIQUE INDEX users_pkey ON public.users USING btree (id)"}}}
+
+	q := IndexesOf("users")
+	q.Where("NOT x.indisprimary")
+
+	indexes, err := q.Load(context.Background(), pool)
+	assert.NoError(t, err)
+	assert.Equal(t, []IndexInfo{
+		{Name: "users_pkey", Unique: true, Primary: true, Definition: "CREATE UNIQUE INDEX users_pkey ON public.users USING btree (id)"},
+	}, indexes)
+	assert.Equal(t, "SELECT i.relname, x.indisunique, x.indisprimary, pg_get_indexdef(x.indexrelid) "+
+		"FROM pg_catalog.pg_index x JOIN pg_catalog.pg_class i ON i.oid = x.indexrelid "+
+		"WHERE x.indrelid = ?::regclass AND NOT x.indisprimary ORDER BY i.relname", pool.stub.sqls[0])
+}
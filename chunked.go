@@ -0,0 +1,89 @@
+package sqrl
+
+import (
+	"context"
+	"fmt"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgx/v4"
+	"reflect"
+)
+
+// ChunkedSelect runs a SelectBuilder once per chunk of a large list of keys,
+// returned by SelectBuilder.WhereInChunked.
+type ChunkedSelect struct {
+	b         *SelectBuilder
+	column    string
+	keys      interface{}
+	chunkSize int
+}
+
+// WhereInChunked splits keys into chunks of at most chunkSize keys and
+// builds one query per chunk, restricted to rows with column IN the keys of
+// the chunk. It is meant for key sets too large for a single statement
+// which can not be matched with column = ANY(?), e.g. because the type of
+// the keys does not match the column type in an array.
+//
+// The results of the queries are handed out in order of the chunks, so
+// ORDER BY only orders the rows within a chunk. Queries with LIMIT, OFFSET,
+// DISTINCT, GROUP BY, HAVING or UNION can not be chunked, as their results
+// would depend on the chunk size.
+//
+// Ex:
+//     err := sqrl.Select("id", "name").From("users").
+//         WhereInChunked("id", ids, 1000).
+//         QueryEach(ctx, pool, func(rows pgx.Rows) error {
+//             ...
+//         })
+func (b *SelectBuilder) WhereInChunked(column string, keys interface{}, chunkSize int) *ChunkedSelect {
+	return &ChunkedSelect{b: b, column: column, keys: keys, chunkSize: chunkSize}
+}
+
+// Statements returns the queries of the chunks, none if there are no keys.
+func (c *ChunkedSelect) Statements() ([]*SelectBuilder, error) {
+	if c.chunkSize < 1 {
+		return nil, fmt.Errorf("chunk size must be positive, got %d", c.chunkSize)
+	}
+	if !isListType(c.keys) {
+		return nil, fmt.Errorf("keys for %s must be a slice or array, got %T", c.column, c.keys)
+	}
+	if (c.b.limitValid && c.b.limit != 0) || (c.b.offsetValid && c.b.offset != 0) {
+		return nil, fmt.Errorf("chunked queries must not have LIMIT or OFFSET")
+	}
+	if c.b.distinct || len(c.b.groupBys) > 0 || len(c.b.havingParts) > 0 {
+		return nil, fmt.Errorf("chunked queries must not have DISTINCT, GROUP BY or HAVING")
+	}
+	if len(c.b.union) > 0 || len(c.b.unionAll) > 0 {
+		return nil, fmt.Errorf("chunked queries must not have UNION")
+	}
+
+	keys := reflect.ValueOf(c.keys)
+	var stmts []*SelectBuilder
+	for start := 0; start < keys.Len(); start += c.chunkSize {
+		end := start + c.chunkSize
+		if end > keys.Len() {
+			end = keys.Len()
+		}
+		chunk := make([]interface{}, end-start)
+		for i := range chunk {
+			chunk[i] = keys.Index(start + i).Interface()
+		}
+		stmts = append(stmts, c.b.Clone().Where(Eq{c.column: chunk}))
+	}
+	return stmts, nil
+}
+
+// QueryEach runs the queries of the chunks in order and calls fn for every
+// row of their results, like the package level QueryEach. Iteration stops
+// at the first error.
+func (c *ChunkedSelect) QueryEach(ctx context.Context, pool instapgxpool.Pool, fn func(rows pgx.Rows) error) error {
+	stmts, err := c.Statements()
+	if err != nil {
+		return err
+	}
+	for _, stmt := range stmts {
+		if err := QueryEach(ctx, pool, stmt, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package sqrl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWhereInChunked(t *testing.T) {
+	stmts, err := Select("id").From("users").Where(Eq{"active": true}).WhereInChunked("id", []int64{1, 2, 3, 4, 5}, 2).Statements()
+	assert.NoError(t, err)
+	if assert.Len(t, stmts, 3) {
+		sql, args, err := stmts[0].ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT id FROM users WHERE active = ? AND id IN (?,?)", sql)
+		assert.Equal(t, []interface{}{true, int64(1), int64(2)}, args)
+
+		sql, args, err = stmts[2].ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT id FROM users WHERE active = ? AND id IN (?)", sql)
+		assert.Equal(t, []interface{}{true, int64(5)}, args)
+	}
+
+	stmts, err = Select("id").From("users").WhereInChunked("id", []string{}, 2).Statements()
+	assert.NoError(t, err)
+	assert.Empty(t, stmts)
+}
+
+func TestWhereInChunkedErrors(t *testing.T) {
+	_, err := Select("id").From("users").WhereInChunked("id", []int{1}, 0).Statements()
+	assert.EqualError(t, err, "chunk size must be positive, got 0")
+
+	_, err = Select("id").From("users").WhereInChunked("id", 1, 10).Statements()
+	assert.EqualError(t, err, "keys for id must be a slice or array, got int")
+
+	_, err = Select("id").From("users").Limit(10).WhereInChunked("id", []int{1}, 10).Statements()
+	assert.EqualError(t, err, "chunked queries must not have LIMIT or OFFSET")
+
+	for _, b := range []*SelectBuilder{
+		Select("team_id").Distinct().From("users"),
+		Select("team_id", "count(*)").From("users").GroupBy("team_id"),
+		Select("1").From("users").Having("count(*) > 1"),
+	} {
+		_, err = b.WhereInChunked("id", []int{1}, 10).Statements()
+		assert.EqualError(t, err, "chunked queries must not have DISTINCT, GROUP BY or HAVING")
+	}
+
+	_, err = Select("id").From("users").Union(Select("id").From("admins")).WhereInChunked("id", []int{1}, 10).Statements()
+	assert.EqualError(t, err, "chunked queries must not have UNION")
+}
+
+func TestWhereInChunkedQueryEach(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{1}, {2}}, {{3}}}
+
+	var ids []int
+	err := Select("id").From("users").WhereInChunked("id", []int{1, 2, 3}, 2).QueryEach(context.Background(), pool, func(rows pgx.Rows) error {
+		var id int
+		err := rows.Scan(&id)
+		ids = append(ids, id)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, ids)
+	assert.Equal(t, []string{"SELECT id FROM users WHERE id IN (?,?)", "SELECT id FROM users WHERE id IN (?)"}, pool.stub.sqls)
+
+	pool = newPoolStub()
+	pool.stub.err = errors.New("query failed")
+	err = Select("id").From("users").WhereInChunked("id", []int{1, 2, 3}, 2).QueryEach(context.Background(), pool, func(rows pgx.Rows) error {
+		return nil
+	})
+	assert.True(t, errors.Is(err, pool.stub.err))
+	assert.Len(t, pool.stub.sqls, 1)
+}
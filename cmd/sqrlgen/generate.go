@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+var tmpl = template.Must(template.New("sqrlgen").Parse(`// Code generated by sqrlgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import "github.com/clevabit/sqrl"
+{{range $t := .Tables}}
+// {{.Ident}} is the {{.Name}} table.
+const {{.Ident}} = "{{.Name}}"
+
+// Columns of the {{.Name}} table.
+var (
+{{- range .Columns}}
+	{{.Ident}} = sqrl.NewCol({{$t.Ident}}, "{{.Name}}")
+{{- end}}
+)
+
+// {{.Ident}}Columns lists all columns of the {{.Name}} table.
+var {{.Ident}}Columns = []sqrl.Col{ {{- range $i, $c := .Columns}}{{if $i}}, {{end}}{{$c.Ident}}{{end -}} }
+
+// Select{{.Ident}} returns a SelectBuilder selecting all columns of the {{.Name}} table.
+func Select{{.Ident}}() *sqrl.SelectBuilder {
+	return sqrl.Select().Cols({{.Ident}}Columns...).From({{.Ident}})
+}
+
+// Insert{{.Ident}} returns an InsertBuilder inserting into the {{.Name}} table.
+func Insert{{.Ident}}() *sqrl.InsertBuilder {
+	return sqrl.Insert({{.Ident}})
+}
+
+// Update{{.Ident}} returns an UpdateBuilder updating the {{.Name}} table.
+func Update{{.Ident}}() *sqrl.UpdateBuilder {
+	return sqrl.Update({{.Ident}})
+}
+
+// Delete{{.Ident}} returns a DeleteBuilder deleting from the {{.Name}} table.
+func Delete{{.Ident}}() *sqrl.DeleteBuilder {
+	return sqrl.Delete({{.Ident}})
+}
+{{end}}`))
+
+type tmplName struct {
+	Name  string
+	Ident string
+}
+
+type tmplTable struct {
+	tmplName
+	Columns []tmplName
+}
+
+// generate renders the Go source for tables.
+func generate(pkg string, tables []table) ([]byte, error) {
+	data := struct {
+		Package string
+		Tables  []tmplTable
+	}{Package: pkg}
+
+	for _, t := range tables {
+		tt := tmplTable{tmplName: tmplName{Name: t.name, Ident: identifier(t.name)}}
+		for _, c := range t.columns {
+			tt.Columns = append(tt.Columns, tmplName{Name: c, Ident: tt.Ident + identifier(c)})
+		}
+		data.Tables = append(data.Tables, tt)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+var initialisms = map[string]bool{
+	"API": true, "HTTP": true, "ID": true, "IP": true, "JSON": true,
+	"SQL": true, "URL": true, "UUID": true,
+}
+
+// identifier turns a SQL name like "user_accounts" or "public.api_keys" into
+// an exported Go identifier like "UserAccounts" or "PublicAPIKeys".
+func identifier(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	ident := &strings.Builder{}
+	for _, word := range words {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			ident.WriteString(upper)
+			continue
+		}
+		runes := []rune(strings.ToLower(word))
+		runes[0] = unicode.ToUpper(runes[0])
+		ident.WriteString(string(runes))
+	}
+
+	if ident.Len() == 0 || unicode.IsDigit([]rune(ident.String())[0]) {
+		return "T" + ident.String()
+	}
+	return ident.String()
+}
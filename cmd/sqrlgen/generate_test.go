@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentifier(t *testing.T) {
+	assert.Equal(t, "UserAccounts", identifier("user_accounts"))
+	assert.Equal(t, "PublicAPIKeys", identifier("public.api_keys"))
+	assert.Equal(t, "UserID", identifier("user_id"))
+	assert.Equal(t, "T2fa", identifier("2fa"))
+}
+
+func TestLoadStructs(t *testing.T) {
+	tables, err := loadStructs("testdata")
+	assert.NoError(t, err)
+	assert.Equal(t, []table{{name: "user_accounts", columns: []string{"id", "email"}}}, tables)
+}
+
+func TestGenerate(t *testing.T) {
+	src, err := generate("models", []table{{name: "user_accounts", columns: []string{"id", "email"}}})
+	assert.NoError(t, err)
+
+	expected := `// Code generated by sqrlgen. DO NOT EDIT.
+
+package models
+
+import "github.com/clevabit/sqrl"
+
+// UserAccounts is the user_accounts table.
+const UserAccounts = "user_accounts"
+
+// Columns of the user_accounts table.
+var (
+	UserAccountsID    = sqrl.NewCol(UserAccounts, "id")
+	UserAccountsEmail = sqrl.NewCol(UserAccounts, "email")
+)
+
+// UserAccountsColumns lists all columns of the user_accounts table.
+var UserAccountsColumns = []sqrl.Col{UserAccountsID, UserAccountsEmail}
+
+// SelectUserAccounts returns a SelectBuilder selecting all columns of the user_accounts table.
+func SelectUserAccounts() *sqrl.SelectBuilder {
+	return sqrl.Select().Cols(UserAccountsColumns...).From(UserAccounts)
+}
+
+// InsertUserAccounts returns an InsertBuilder inserting into the user_accounts table.
+func InsertUserAccounts() *sqrl.InsertBuilder {
+	return sqrl.Insert(UserAccounts)
+}
+
+// UpdateUserAccounts returns an UpdateBuilder updating the user_accounts table.
+func UpdateUserAccounts() *sqrl.UpdateBuilder {
+	return sqrl.Update(UserAccounts)
+}
+
+// DeleteUserAccounts returns a DeleteBuilder deleting from the user_accounts table.
+func DeleteUserAccounts() *sqrl.DeleteBuilder {
+	return sqrl.Delete(UserAccounts)
+}
+`
+	assert.Equal(t, expected, string(src))
+}
@@ -0,0 +1,76 @@
+// Command sqrlgen generates typed table constants and column references plus basic
+// builder constructors for every table of a database schema or for every
+// tagged struct of a Go package.
+//
+// Tables are either read from information_schema:
+//     sqrlgen -dsn postgres://localhost/app -schema public -package models -out tables_gen.go
+//
+// or from Go structs marked with a sqrl:table directive, using the db struct
+// tags as column names:
+//     //sqrl:table users
+//     type User struct {
+//         ID    int64  `db:"id"`
+//         Email string `db:"email"`
+//     }
+//
+//     sqrlgen -structs ./models -package models -out tables_gen.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+func main() {
+	var (
+		dsn     = flag.String("dsn", "", "PostgreSQL connection string to introspect")
+		schema  = flag.String("schema", "public", "database schema to introspect")
+		structs = flag.String("structs", "", "directory of Go structs to parse instead of a database")
+		pkg     = flag.String("package", "", "package name of the generated file")
+		out     = flag.String("out", "", "output file, defaults to stdout")
+	)
+	flag.Parse()
+
+	if err := run(*dsn, *schema, *structs, *pkg, *out); err != nil {
+		fmt.Fprintf(os.Stderr, "sqrlgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(dsn, schema, structs, pkg, out string) error {
+	if len(pkg) == 0 {
+		return fmt.Errorf("-package is required")
+	}
+
+	var (
+		tables []table
+		err    error
+	)
+	switch {
+	case len(dsn) > 0 && len(structs) > 0:
+		return fmt.Errorf("-dsn and -structs are mutually exclusive")
+	case len(dsn) > 0:
+		tables, err = loadSchema(context.Background(), dsn, schema)
+	case len(structs) > 0:
+		tables, err = loadStructs(structs)
+	default:
+		return fmt.Errorf("either -dsn or -structs is required")
+	}
+	if err != nil {
+		return err
+	}
+
+	src, err := generate(pkg, tables)
+	if err != nil {
+		return err
+	}
+
+	if len(out) == 0 {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return ioutil.WriteFile(out, src, 0644)
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/clevabit/sqrl"
+	"github.com/jackc/pgx/v4"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// table is a table and its columns in definition order.
+type table struct {
+	name    string
+	columns []string
+}
+
+// loadSchema reads all tables of schema from information_schema.
+func loadSchema(ctx context.Context, dsn, schema string) ([]table, error) {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close(ctx)
+
+	query, args, err := sqrl.Select("c.table_name", "c.column_name").
+		From("information_schema.columns c").
+		Join("information_schema.tables t ON t.table_schema = c.table_schema AND t.table_name = c.table_name").
+		Where(sqrl.Eq{"c.table_schema": schema, "t.table_type": "BASE TABLE"}).
+		OrderBy("c.table_name", "c.ordinal_position").
+		PlaceholderFormat(sqrl.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []table
+	for rows.Next() {
+		var name, column string
+		if err := rows.Scan(&name, &column); err != nil {
+			return nil, err
+		}
+		if len(tables) == 0 || tables[len(tables)-1].name != name {
+			tables = append(tables, table{name: name})
+		}
+		t := &tables[len(tables)-1]
+		t.columns = append(t.columns, column)
+	}
+	return tables, rows.Err()
+}
+
+// tableDirective marks structs to generate code for, followed by the table name.
+const tableDirective = "sqrl:table"
+
+// isSourceFile reports whether fi is a non-test Go file, as test files are
+// not part of the package code is generated for.
+func isSourceFile(fi fs.FileInfo) bool {
+	return !strings.HasSuffix(fi.Name(), "_test.go")
+}
+
+// loadStructs parses the Go files in dir, except test files, and returns a
+// table for every struct marked with the sqrl:table directive. Columns are
+// taken from the db tags of the struct fields in field order; fields tagged
+// with "-" are skipped.
+func loadStructs(dir string) ([]table, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, isSourceFile, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []table
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					ts := spec.(*ast.TypeSpec)
+					st, ok := ts.Type.(*ast.StructType)
+					if !ok {
+						continue
+					}
+					doc := ts.Doc
+					if doc == nil {
+						doc = gen.Doc
+					}
+					name := directiveTable(doc)
+					if len(name) == 0 {
+						continue
+					}
+					t, err := structTable(name, st)
+					if err != nil {
+						return nil, fmt.Errorf("%s: %v", fset.Position(ts.Pos()), err)
+					}
+					tables = append(tables, t)
+				}
+			}
+		}
+	}
+
+	sort.Slice(tables, func(i, j int) bool { return tables[i].name < tables[j].name })
+	return tables, nil
+}
+
+func directiveTable(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	for _, c := range doc.List {
+		text := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+		if strings.HasPrefix(text, tableDirective) {
+			return strings.TrimSpace(strings.TrimPrefix(text, tableDirective))
+		}
+	}
+	return ""
+}
+
+func structTable(name string, st *ast.StructType) (table, error) {
+	t := table{name: name}
+	for _, field := range st.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			return t, err
+		}
+		column := strings.Split(reflect.StructTag(tag).Get("db"), ",")[0]
+		if len(column) == 0 || column == "-" {
+			continue
+		}
+		t.columns = append(t.columns, column)
+	}
+	if len(t.columns) == 0 {
+		return t, fmt.Errorf("table %s has no db tagged fields", name)
+	}
+	return t, nil
+}
@@ -0,0 +1,14 @@
+package models
+
+//sqrl:table user_accounts
+type UserAccount struct {
+	ID       int64  `db:"id"`
+	Email    string `db:"email,omitempty"`
+	Password string `db:"-"`
+	Internal bool
+}
+
+// Ignored has no sqrl:table directive.
+type Ignored struct {
+	Name string `db:"name"`
+}
@@ -0,0 +1,6 @@
+package models
+
+//sqrl:table test_accounts
+type testAccount struct {
+	ID int64 `db:"id"`
+}
@@ -0,0 +1,127 @@
+package sqrl
+
+// Col is a reference to a column, optionally qualified by its table and
+// aliased in result columns. Col is a Sqlizer, so it can be passed to
+// SelectBuilder.Column, and provides predicate and ordering helpers. Cols
+// are accepted by SelectBuilder.Cols, JoinOn, LeftJoinOn and OrderByCols:
+//
+// Ex:
+//     id, email := NewCol("users", "id"), NewCol("users", "email")
+//     Select().Cols(id, email).From("users").
+//         JoinOn("emails", NewCol("emails", "user_id"), id).
+//         Where(email.Eq("moe@example.com")).
+//         OrderBy(email.Desc())
+type Col struct {
+	Table string
+	Name  string
+	alias string
+}
+
+// NewCol returns a reference to column name of table. table may be empty for
+// unqualified columns.
+func NewCol(table, name string) Col {
+	return Col{Table: table, Name: name}
+}
+
+// As returns a copy of the column which is aliased as alias when used as a
+// result column.
+func (c Col) As(alias string) Col {
+	c.alias = alias
+	return c
+}
+
+// Alias returns the alias of the column, if any.
+func (c Col) Alias() string {
+	return c.alias
+}
+
+// String returns the qualified name of the column.
+func (c Col) String() string {
+	if len(c.Table) == 0 {
+		return c.Name
+	}
+	return c.Table + "." + c.Name
+}
+
+// ToSql renders the column as a result column, including its alias.
+func (c Col) ToSql() (string, []interface{}, error) {
+	if len(c.alias) == 0 {
+		return c.String(), nil, nil
+	}
+	return c.String() + " AS " + c.alias, nil, nil
+}
+
+// Eq returns a predicate comparing the column to v, see Eq.
+func (c Col) Eq(v interface{}) Sqlizer {
+	return Eq{c.String(): v}
+}
+
+// NotEq returns a predicate checking the column differs from v, see NotEq.
+func (c Col) NotEq(v interface{}) Sqlizer {
+	return NotEq{c.String(): v}
+}
+
+// In returns a predicate checking the column is one of the elements of the
+// slice or array vs.
+func (c Col) In(vs interface{}) Sqlizer {
+	if !isListType(vs) {
+		vs = []interface{}{vs}
+	}
+	return Eq{c.String(): vs}
+}
+
+// NotIn returns a predicate checking the column is none of the elements of
+// the slice or array vs.
+func (c Col) NotIn(vs interface{}) Sqlizer {
+	if !isListType(vs) {
+		vs = []interface{}{vs}
+	}
+	return NotEq{c.String(): vs}
+}
+
+// Lt returns a predicate checking the column is less than v.
+func (c Col) Lt(v interface{}) Sqlizer {
+	return Lt{c.String(): v}
+}
+
+// LtOrEq returns a predicate checking the column is less than or equal to v.
+func (c Col) LtOrEq(v interface{}) Sqlizer {
+	return LtOrEq{c.String(): v}
+}
+
+// Gt returns a predicate checking the column is greater than v.
+func (c Col) Gt(v interface{}) Sqlizer {
+	return Gt{c.String(): v}
+}
+
+// GtOrEq returns a predicate checking the column is greater than or equal to v.
+func (c Col) GtOrEq(v interface{}) Sqlizer {
+	return GtOrEq{c.String(): v}
+}
+
+// IsNull returns a predicate checking the column is NULL.
+func (c Col) IsNull() Sqlizer {
+	return Eq{c.String(): nil}
+}
+
+// IsNotNull returns a predicate checking the column is not NULL.
+func (c Col) IsNotNull() Sqlizer {
+	return NotEq{c.String(): nil}
+}
+
+// EqCol returns a condition comparing the column to other, e.g. for use in
+// JOIN clauses:
+//     Join("emails e ON " + NewCol("e", "user_id").EqCol(usersID))
+func (c Col) EqCol(other Col) string {
+	return c.String() + " = " + other.String()
+}
+
+// Asc returns an ascending ORDER BY expression for the column.
+func (c Col) Asc() string {
+	return c.String() + " ASC"
+}
+
+// Desc returns a descending ORDER BY expression for the column.
+func (c Col) Desc() string {
+	return c.String() + " DESC"
+}
@@ -0,0 +1,69 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColToSql(t *testing.T) {
+	users := struct{ ID, Email Col }{NewCol("users", "id"), NewCol("users", "email")}
+	emailID := NewCol("emails", "user_id")
+
+	sql, args, err := Select().
+		Cols(users.ID, users.Email.As("mail")).
+		Column(NewCol("", "now()").As("ts")).
+		From("users").
+		Join("emails ON "+emailID.EqCol(users.ID)).
+		Where(users.Email.Eq("moe@example.com")).
+		Where(users.ID.In([]int{1, 2})).
+		Where(users.ID.Gt(0)).
+		Where(users.Email.IsNotNull()).
+		OrderBy(users.Email.Asc(), users.ID.Desc()).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT users.id, users.email AS mail, now() AS ts FROM users " +
+		"JOIN emails ON emails.user_id = users.id " +
+		"WHERE users.email = ? AND users.id IN (?,?) AND users.id > ? AND users.email IS NOT NULL " +
+		"ORDER BY users.email ASC, users.id DESC"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"moe@example.com", 1, 2, 0}, args)
+}
+
+func TestColJoinOrderBy(t *testing.T) {
+	usersID, usersEmail := NewCol("users", "id"), NewCol("users", "email")
+	emailsUserID, teamsID := NewCol("emails", "user_id"), NewCol("teams", "id")
+
+	sql, _, err := Select().
+		Cols(usersID).
+		From("users").
+		JoinOn("emails", emailsUserID, usersID).
+		LeftJoinOn("teams", teamsID, NewCol("users", "team_id")).
+		OrderByCols(usersEmail.As("mail"), usersID).
+		OrderBy(teamsID.Desc()).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT users.id FROM users " +
+		"JOIN emails ON emails.user_id = users.id LEFT JOIN teams ON teams.id = users.team_id " +
+		"ORDER BY users.email, users.id, teams.id DESC"
+	assert.Equal(t, expectedSql, sql)
+}
+
+func TestColPredicates(t *testing.T) {
+	id := NewCol("", "id")
+
+	sql, args, _ := id.In(3).ToSql()
+	assert.Equal(t, "id IN (?)", sql)
+	assert.Equal(t, []interface{}{3}, args)
+
+	sql, _, _ = id.NotIn([]int{}).ToSql()
+	assert.Equal(t, "(1=1)", sql)
+
+	sql, _, _ = id.IsNull().ToSql()
+	assert.Equal(t, "id IS NULL", sql)
+
+	sql, _, _ = And{id.NotEq(1), id.Lt(10), id.LtOrEq(9), id.GtOrEq(2)}.ToSql()
+	assert.Equal(t, "(id <> ? AND id < ? AND id <= ? AND id >= ?)", sql)
+}
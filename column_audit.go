@@ -0,0 +1,95 @@
+package sqrl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AuditColumns makes child SelectBuilders check their result columns for
+// duplicate names in ToSql, e.g. two columns aliased "name" or "u.id" and
+// "p.id", which otherwise only surface when scanning the rows.
+func (b StatementBuilderType) AuditColumns() StatementBuilderType {
+	b.auditColumns = true
+	return b
+}
+
+// AuditColumns makes ToSql check the result columns for duplicate names,
+// see StatementBuilderType.AuditColumns.
+func (b *SelectBuilder) AuditColumns() *SelectBuilder {
+	b.auditColumns = true
+	return b
+}
+
+var (
+	columnAliasRegexp = regexp.MustCompile(`(?i)\s+AS\s+("[^"]+"|[A-Za-z_][A-Za-z0-9_$]*)$`)
+	columnNameRegexp  = regexp.MustCompile(`^(?:[A-Za-z_][A-Za-z0-9_$]*\.)*("[^"]+"|[A-Za-z_][A-Za-z0-9_$]*)$`)
+)
+
+// resultColumnName returns the name of the result column of the select
+// list entry column, or "" if it can not be determined, e.g. for
+// expressions without alias or "*".
+func resultColumnName(column string) string {
+	column = strings.TrimSpace(column)
+	m := columnAliasRegexp.FindStringSubmatch(column)
+	if m == nil {
+		m = columnNameRegexp.FindStringSubmatch(column)
+	}
+	if m == nil {
+		return ""
+	}
+	if name := m[1]; strings.HasPrefix(name, `"`) {
+		return strings.Trim(name, `"`)
+	}
+	return strings.ToLower(m[1])
+}
+
+// splitColumns splits a select list at commas outside of parentheses and
+// quotes.
+func splitColumns(list string) []string {
+	var columns []string
+	depth, start := 0, 0
+	var quote rune
+	for i, r := range list {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			columns = append(columns, list[start:i])
+			start = i + 1
+		}
+	}
+	return append(columns, list[start:])
+}
+
+// checkResultColumns returns an error if two result columns of b have the
+// same name.
+func (b *SelectBuilder) checkResultColumns() error {
+	seen := make(map[string]string)
+	for _, c := range b.columns {
+		sql, _, err := c.ToSql()
+		if err != nil {
+			return err
+		}
+		for _, column := range splitColumns(sql) {
+			name := resultColumnName(column)
+			if len(name) == 0 {
+				continue
+			}
+			column = strings.TrimSpace(column)
+			if prev, ok := seen[name]; ok {
+				return fmt.Errorf("duplicate result column %q: %s and %s", name, prev, column)
+			}
+			seen[name] = column
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditColumns(t *testing.T) {
+	sb := StatementBuilder.AuditColumns()
+
+	_, _, err := sb.Select("u.id", "p.id AS post_id", "count(*)", "count(*) AS n", "coalesce(a, b, c) AS \"Name\"", "u.*").
+		Column("name").
+		From("users u").
+		ToSql()
+	assert.NoError(t, err)
+
+	_, _, err = sb.Select("u.id", "p.id").From("users u").ToSql()
+	assert.EqualError(t, err, `duplicate result column "id": u.id and p.id`)
+
+	_, _, err = sb.Select("u.name, coalesce(p.title, 'a, b') AS title", "p.name AS NAME").From("users u").ToSql()
+	assert.EqualError(t, err, `duplicate result column "name": u.name and p.name AS NAME`)
+
+	_, _, err = Select("id").Column(Select("max(id)").From("posts"), "id").From("users").AuditColumns().ToSql()
+	assert.EqualError(t, err, `duplicate result column "id": id and (SELECT max(id) FROM posts) AS id`)
+
+	_, _, err = Select("u.id", "p.id").From("users u").ToSql()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,224 @@
+package sqrl
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// The types and methods in this file provide the database/sql based API of
+// squirrel, so code written against Masterminds/squirrel or elgris/sqrl keeps
+// working after switching imports. Exec, Query and QueryRow have value
+// receivers, so builder values satisfy the same interfaces as squirrel's.
+
+// Execer is the interface that wraps the Exec method.
+//
+// Exec executes the given query as implemented by database/sql.Exec.
+type Execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// Queryer is the interface that wraps the Query method.
+//
+// Query executes the given query as implemented by database/sql.Query.
+type Queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// QueryRower is the interface that wraps the QueryRow method.
+//
+// QueryRow executes the given query as implemented by database/sql.QueryRow.
+type QueryRower interface {
+	QueryRow(query string, args ...interface{}) RowScanner
+}
+
+// BaseRunner groups the Execer and Queryer interfaces.
+type BaseRunner interface {
+	Execer
+	Queryer
+}
+
+// Runner groups the Execer, Queryer, and QueryRower interfaces.
+type Runner interface {
+	Execer
+	Queryer
+	QueryRower
+}
+
+// StdSql encompasses the standard methods of the *sql.DB and *sql.Tx types.
+type StdSql interface {
+	Query(string, ...interface{}) (*sql.Rows, error)
+	QueryRow(string, ...interface{}) *sql.Row
+	Exec(string, ...interface{}) (sql.Result, error)
+}
+
+// ErrRunnerNotSet is returned by methods that need a Runner if it isn't set.
+var ErrRunnerNotSet = fmt.Errorf("cannot run; no Runner set (RunWith)")
+
+// ErrRunnerNotQueryRunner is returned by QueryRow if the RunWith value doesn't
+// implement QueryRower.
+var ErrRunnerNotQueryRunner = fmt.Errorf("cannot QueryRow; Runner is not a QueryRower")
+
+// stdsqlRunner adapts *sql.DB and *sql.Tx to Runner.
+type stdsqlRunner struct {
+	StdSql
+}
+
+func (r *stdsqlRunner) QueryRow(query string, args ...interface{}) RowScanner {
+	return r.StdSql.QueryRow(query, args...)
+}
+
+func wrapRunner(runner BaseRunner) BaseRunner {
+	if std, ok := runner.(StdSql); ok {
+		return &stdsqlRunner{std}
+	}
+	return runner
+}
+
+// ExecWith Execs the SQL returned by s with db.
+func ExecWith(db Execer, s Sqlizer) (res sql.Result, err error) {
+	if err = checkDryRun(context.Background(), nil, s); err != nil {
+		return
+	}
+	query, args, err := statementSql(s)
+	if err != nil {
+		return
+	}
+	return db.Exec(query, args...)
+}
+
+// QueryWith Querys the SQL returned by s with db.
+func QueryWith(db Queryer, s Sqlizer) (rows *sql.Rows, err error) {
+	if err = checkDryRun(context.Background(), nil, s); err != nil {
+		return
+	}
+	query, args, err := statementSql(s)
+	if err != nil {
+		return
+	}
+	return db.Query(query, args...)
+}
+
+// QueryRowWith QueryRows the SQL returned by s with db.
+func QueryRowWith(db QueryRower, s Sqlizer) RowScanner {
+	if err := checkDryRun(context.Background(), nil, s); err != nil {
+		return &Row{err: err}
+	}
+	query, args, err := statementSql(s)
+	return &Row{RowScanner: db.QueryRow(query, args...), err: err}
+}
+
+func execWithRunner(runner BaseRunner, s Sqlizer) (sql.Result, error) {
+	if runner == nil {
+		return nil, ErrRunnerNotSet
+	}
+	return ExecWith(runner, s)
+}
+
+func queryWithRunner(runner BaseRunner, s Sqlizer) (*sql.Rows, error) {
+	if runner == nil {
+		return nil, ErrRunnerNotSet
+	}
+	return QueryWith(runner, s)
+}
+
+func queryRowWithRunner(runner BaseRunner, s Sqlizer) RowScanner {
+	if runner == nil {
+		return &Row{err: ErrRunnerNotSet}
+	}
+	queryRower, ok := runner.(QueryRower)
+	if !ok {
+		return &Row{err: ErrRunnerNotQueryRunner}
+	}
+	return QueryRowWith(queryRower, s)
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b StatementBuilderType) RunWith(runner BaseRunner) StatementBuilderType {
+	b.runWith = wrapRunner(runner)
+	return b
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b *SelectBuilder) RunWith(runner BaseRunner) *SelectBuilder {
+	b.runWith = wrapRunner(runner)
+	return b
+}
+
+// Exec builds and Execs the query with the Runner set by RunWith.
+func (b SelectBuilder) Exec() (sql.Result, error) {
+	return execWithRunner(b.runWith, &b)
+}
+
+// Query builds and Querys the query with the Runner set by RunWith.
+func (b SelectBuilder) Query() (*sql.Rows, error) {
+	return queryWithRunner(b.runWith, &b)
+}
+
+// QueryRow builds and QueryRows the query with the Runner set by RunWith.
+func (b SelectBuilder) QueryRow() RowScanner {
+	return queryRowWithRunner(b.runWith, &b)
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b *InsertBuilder) RunWith(runner BaseRunner) *InsertBuilder {
+	b.runWith = wrapRunner(runner)
+	return b
+}
+
+// Exec builds and Execs the query with the Runner set by RunWith.
+func (b InsertBuilder) Exec() (sql.Result, error) {
+	return execWithRunner(b.runWith, &b)
+}
+
+// Query builds and Querys the query with the Runner set by RunWith.
+func (b InsertBuilder) Query() (*sql.Rows, error) {
+	return queryWithRunner(b.runWith, &b)
+}
+
+// QueryRow builds and QueryRows the query with the Runner set by RunWith.
+func (b InsertBuilder) QueryRow() RowScanner {
+	return queryRowWithRunner(b.runWith, &b)
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b *UpdateBuilder) RunWith(runner BaseRunner) *UpdateBuilder {
+	b.runWith = wrapRunner(runner)
+	return b
+}
+
+// Exec builds and Execs the query with the Runner set by RunWith.
+func (b UpdateBuilder) Exec() (sql.Result, error) {
+	return execWithRunner(b.runWith, &b)
+}
+
+// Query builds and Querys the query with the Runner set by RunWith.
+func (b UpdateBuilder) Query() (*sql.Rows, error) {
+	return queryWithRunner(b.runWith, &b)
+}
+
+// QueryRow builds and QueryRows the query with the Runner set by RunWith.
+func (b UpdateBuilder) QueryRow() RowScanner {
+	return queryRowWithRunner(b.runWith, &b)
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b *DeleteBuilder) RunWith(runner BaseRunner) *DeleteBuilder {
+	b.runWith = wrapRunner(runner)
+	return b
+}
+
+// Exec builds and Execs the query with the Runner set by RunWith.
+func (b DeleteBuilder) Exec() (sql.Result, error) {
+	return execWithRunner(b.runWith, &b)
+}
+
+// Query builds and Querys the query with the Runner set by RunWith.
+func (b DeleteBuilder) Query() (*sql.Rows, error) {
+	return queryWithRunner(b.runWith, &b)
+}
+
+// QueryRow builds and QueryRows the query with the Runner set by RunWith.
+func (b DeleteBuilder) QueryRow() RowScanner {
+	return queryRowWithRunner(b.runWith, &b)
+}
@@ -0,0 +1,138 @@
+package sqrl
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// WhereConflictError is returned by ToSql if a query built with conflict
+// detection has contradictory equality conditions on a column, e.g.
+// Where(Eq{"id": 1}).Where(Eq{"id": 2}), which match no rows.
+type WhereConflictError struct {
+	Column string
+	Values []interface{}
+}
+
+func (e *WhereConflictError) Error() string {
+	return fmt.Sprintf("conflicting where conditions on column %s: %v", e.Column, e.Values)
+}
+
+// whereConflictDetector checks the WHERE clauses of builders for
+// contradictory conditions.
+type whereConflictDetector struct {
+	logger Logger
+}
+
+// DetectWhereConflicts makes child builders check their WHERE clauses for
+// contradictory equality conditions on the same column, as added by Eq,
+// OrderedEq and maps, which happen easily when combining base builders with
+// request filters. Conditions in Or and in lists for IN are not considered.
+//
+// If logger is nil, ToSql fails with a *WhereConflictError, otherwise the
+// conflict is logged as a warning and the query is built.
+//
+// Ex:
+//     sb := sqrl.StatementBuilder.DetectWhereConflicts(nil)
+//     _, _, err := sb.Select("*").From("users").Where(sqrl.Eq{"id": 1}).Where(sqrl.Eq{"id": 2}).ToSql()
+//     // err: conflicting where conditions on column id: [1 2]
+func (b StatementBuilderType) DetectWhereConflicts(logger Logger) StatementBuilderType {
+	b.whereConflicts = &whereConflictDetector{logger: logger}
+	return b
+}
+
+// check returns a *WhereConflictError for the first conflict in parts, or
+// logs it.
+func (d *whereConflictDetector) check(parts []Sqlizer) error {
+	if d == nil {
+		return nil
+	}
+
+	c := &conflictCollector{values: map[string][]interface{}{}}
+	for _, p := range parts {
+		c.collect(p)
+	}
+	for _, column := range c.columns {
+		values := c.values[column]
+		for _, v := range values[1:] {
+			if reflect.DeepEqual(v, values[0]) {
+				continue
+			}
+			err := &WhereConflictError{Column: column, Values: values}
+			if d.logger == nil {
+				return err
+			}
+			d.logger.Warnw("conflicting where conditions", "column", column, "values", values)
+			break
+		}
+	}
+	return nil
+}
+
+// conflictCollector collects the values of the equality conditions of a
+// WHERE clause by column, in order of appearance.
+type conflictCollector struct {
+	columns []string
+	values  map[string][]interface{}
+}
+
+func (c *conflictCollector) add(column string, value interface{}) {
+	value = encodeArg(value)
+	if _, ok := value.(Sqlizer); ok || isListType(value) {
+		return
+	}
+	if valuer, ok := value.(driver.Valuer); ok {
+		if v, err := valuer.Value(); err == nil {
+			value = v
+		}
+	}
+	if _, ok := c.values[column]; !ok {
+		c.columns = append(c.columns, column)
+	}
+	c.values[column] = append(c.values[column], value)
+}
+
+func (c *conflictCollector) addMap(m map[string]interface{}) {
+	for _, column := range sortedKeys(m) {
+		c.add(column, m[column])
+	}
+}
+
+func (c *conflictCollector) collect(p interface{}) {
+	switch p := p.(type) {
+	case *wherePart:
+		c.collect(p.pred)
+	case *Group:
+		if !p.or {
+			for _, part := range p.parts {
+				c.collect(part)
+			}
+		}
+	case And:
+		for _, part := range p {
+			c.collect(part)
+		}
+	case map[string]interface{}:
+		c.addMap(p)
+	case Eq:
+		c.addMap(p)
+	case EqNotZero:
+		c.addMap(p.filter())
+	case EqNotNil:
+		c.addMap(p.filter())
+	case OrderedEq:
+		for _, pair := range p {
+			c.add(pair.Column, pair.Value)
+		}
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,42 @@
+package sqrl
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectWhereConflicts(t *testing.T) {
+	sb := StatementBuilder.DetectWhereConflicts(nil)
+
+	id := 1
+	_, _, err := sb.Select("*").From("users").
+		Where(Eq{"id": 1}).
+		Where(OrderedEq{{"id", &id}, {"name", "moe"}}).
+		Where(Or{Eq{"id": 2}, Eq{"id": 3}}).
+		Where(Eq{"team_id": []int{4, 5}}).
+		Where(Eq{"team_id": 6}).
+		ToSql()
+	assert.NoError(t, err)
+
+	_, _, err = sb.Select("*").From("users").Where(Eq{"id": 1}).Where("active").Where(And{Eq{"name": "moe"}, Eq{"id": 2}}).ToSql()
+	assert.EqualError(t, err, "conflicting where conditions on column id: [1 2]")
+	var conflict *WhereConflictError
+	assert.True(t, errors.As(err, &conflict))
+	assert.Equal(t, "id", conflict.Column)
+
+	_, _, err = sb.Update("users").Set("a", 1).Where(Eq{"deleted_at": nil}).Where(map[string]interface{}{"deleted_at": "2020-01-01"}).ToSql()
+	assert.EqualError(t, err, "conflicting where conditions on column deleted_at: [<nil> 2020-01-01]")
+
+	_, _, err = Select("*").From("users").Where(Eq{"id": 1}).Where(Eq{"id": 2}).ToSql()
+	assert.NoError(t, err)
+}
+
+func TestDetectWhereConflictsLogged(t *testing.T) {
+	logger := &loggerStub{}
+	sql, _, err := StatementBuilder.DetectWhereConflicts(logger).Delete("users").Where(Eq{"id": 1}).Where(Eq{"id": 2}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users WHERE id = ? AND id = ?", sql)
+	assert.Equal(t, [][]interface{}{{"conflicting where conditions", "column", "id", "values", []interface{}{1, 2}}}, logger.entries)
+}
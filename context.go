@@ -0,0 +1,67 @@
+package sqrl
+
+import (
+	"context"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgx/v4"
+)
+
+type statementBuilderKey struct{}
+
+// WithStatementBuilder returns a copy of ctx carrying b, so code deep down
+// the call chain can build statements with the settings of b, e.g. its
+// placeholder format, pool and plan capture, without passing it along.
+//
+// Ex:
+//     ctx = sqrl.WithStatementBuilder(ctx, sqrl.StatementBuilder.PlaceholderFormat(sqrl.Dollar).RunWithPool(pool))
+//     ...
+//     err := sqrl.FromContext(ctx).Select("name").From("users").Where(sqrl.Eq{"id": id}).ScanContext(ctx, &name)
+func WithStatementBuilder(ctx context.Context, b StatementBuilderType) context.Context {
+	return context.WithValue(ctx, statementBuilderKey{}, b)
+}
+
+// FromContext returns the StatementBuilderType set with
+// WithStatementBuilder, or StatementBuilder if ctx carries none.
+func FromContext(ctx context.Context) StatementBuilderType {
+	if b, ok := ctx.Value(statementBuilderKey{}).(StatementBuilderType); ok {
+		return b
+	}
+	return StatementBuilder
+}
+
+type txKey struct{}
+
+// ContextWithTx returns a copy of ctx carrying tx. The execution helpers
+// (ExecWithContext, QueryWithContext, QueryRowWithContext and the builder
+// methods based on them) run statements with the returned context in tx
+// instead of the pool passed to them, and RunInTx runs within a savepoint
+// of tx, so service layers can be made transactional by a decorator.
+//
+// Ex:
+//     tx, err := pool.Begin(ctx)
+//     ...
+//     err = svc.CreateUser(sqrl.ContextWithTx(ctx, tx), user)
+func ContextWithTx(ctx context.Context, tx pgx.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFromContext returns the transaction set with ContextWithTx, or nil if
+// ctx carries none.
+func TxFromContext(ctx context.Context) pgx.Tx {
+	tx, _ := ctx.Value(txKey{}).(pgx.Tx)
+	return tx
+}
+
+// contextPool returns a pool running statements in the transaction carried
+// by ctx, if any, and pool otherwise. Pools already running statements in a
+// transaction, like the pool passed to the fn of RunInTx, are kept.
+func contextPool(ctx context.Context, pool instapgxpool.Pool) instapgxpool.Pool {
+	tx := TxFromContext(ctx)
+	if tx == nil {
+		return pool
+	}
+	if _, ok := pool.(*txPool); ok {
+		return pool
+	}
+	return &txPool{Pool: pool, tx: tx}
+}
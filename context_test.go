@@ -0,0 +1,68 @@
+package sqrl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	ctx := context.Background()
+
+	sql, _, err := FromContext(ctx).Select("id").From("users").Where("id = ?", 1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE id = ?", sql)
+
+	ctx = WithStatementBuilder(ctx, StatementBuilder.PlaceholderFormat(Dollar))
+
+	sql, _, err = FromContext(ctx).Select("id").From("users").Where("id = ?", 1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE id = $1", sql)
+}
+
+func TestFromContextRunWithPool(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.tag = nil
+	ctx := WithStatementBuilder(context.Background(), StatementBuilder.RunWithPool(pool))
+
+	_, err := FromContext(ctx).Delete("users").Where("id = ?", 1).ExecAttached(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"DELETE FROM users WHERE id = ?"}, pool.stub.sqls)
+}
+
+func TestContextWithTx(t *testing.T) {
+	pool := newPoolStub()
+	stub := &pgxStub{}
+	ctx := ContextWithTx(context.Background(), &txStub{stub: stub})
+	assert.NotNil(t, TxFromContext(ctx))
+	assert.Nil(t, TxFromContext(context.Background()))
+
+	_, err := ExecWithContext(ctx, pool, Update("users").Set("active", true))
+	assert.NoError(t, err)
+	_, err = Pluck[int](ctx, pool, Select("id").From("users"))
+	assert.NoError(t, err)
+	err = QueryRowWithContext(ctx, pool, Select("id").From("users")).Scan()
+	assert.Equal(t, pgx.ErrNoRows, err)
+
+	assert.Empty(t, pool.stub.sqls)
+	assert.Equal(t, []string{"UPDATE users SET active = ?", "SELECT id FROM users", "SELECT id FROM users"}, stub.sqls)
+}
+
+func TestContextWithTxRunInTx(t *testing.T) {
+	pool := newPoolStub()
+	tx := &txStub{stub: pool.stub}
+	ctx := ContextWithTx(context.Background(), tx)
+
+	err := RunInTx(ctx, pool, TxOptions{}, func(pool instapgxpool.Pool) error {
+		_, err := ExecWithContext(ctx, pool, Insert("items").Values(1))
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"INSERT INTO items VALUES (?)"}, pool.stub.sqls)
+	assert.Equal(t, 1, pool.stub.begun)
+	assert.Equal(t, 1, pool.stub.committed)
+	assert.False(t, tx.closed)
+}
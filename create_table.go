@@ -0,0 +1,192 @@
+package sqrl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgconn"
+	"strings"
+)
+
+// CreateTableBuilder builds SQL CREATE TABLE statements.
+type CreateTableBuilder struct {
+	StatementBuilderType
+
+	table       string
+	ifNotExists bool
+	columns     []string
+	constraints []string
+	partitionOf string
+	bound       Sqlizer
+	partitionBy string
+
+	suffixes exprs
+}
+
+// NewCreateTableBuilder creates new instance of CreateTableBuilder
+func NewCreateTableBuilder(b StatementBuilderType) *CreateTableBuilder {
+	return &CreateTableBuilder{StatementBuilderType: b}
+}
+
+// ExecContext builds and Execs the statement using given context.
+func (b *CreateTableBuilder) ExecContext(ctx context.Context, pool instapgxpool.Pool) (pgconn.CommandTag, error) {
+	return b.execContext(ctx, pool, b)
+}
+
+// ExecAttached is like ExecContext, using the pool set with RunWithPool.
+func (b *CreateTableBuilder) ExecAttached(ctx context.Context) (pgconn.CommandTag, error) {
+	return b.ExecContext(ctx, nil)
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// statement.
+func (b *CreateTableBuilder) PlaceholderFormat(f PlaceholderFormat) *CreateTableBuilder {
+	b.placeholderFormat = f
+	return b
+}
+
+// ToSql builds the statement into a SQL string and bound args.
+func (b *CreateTableBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
+	if len(b.table) == 0 {
+		err = fmt.Errorf("create table statements must specify a table")
+		return
+	}
+	if len(b.partitionOf) > 0 {
+		if len(b.columns) > 0 {
+			err = fmt.Errorf("partitions can not define columns, they are inherited from the parent table")
+			return
+		}
+		if b.bound == nil {
+			err = fmt.Errorf("partitions must specify a partition bound")
+			return
+		}
+	} else if len(b.columns) == 0 {
+		err = fmt.Errorf("create table statements must have at least one column")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	sql.WriteString("CREATE TABLE ")
+	if b.ifNotExists {
+		sql.WriteString("IF NOT EXISTS ")
+	}
+	sql.WriteString(b.table)
+
+	if len(b.partitionOf) > 0 {
+		sql.WriteString(" PARTITION OF ")
+		sql.WriteString(b.partitionOf)
+	}
+
+	if len(b.columns) > 0 || len(b.constraints) > 0 {
+		sql.WriteString(" (")
+		sql.WriteString(strings.Join(append(append([]string{}, b.columns...), b.constraints...), ", "))
+		sql.WriteString(")")
+	}
+
+	if b.bound != nil {
+		sql.WriteString(" ")
+		args, err = appendToSql([]Sqlizer{b.bound}, sql, "", args)
+		if err != nil {
+			return
+		}
+	}
+
+	if len(b.partitionBy) > 0 {
+		sql.WriteString(" PARTITION BY ")
+		sql.WriteString(b.partitionBy)
+	}
+
+	if len(b.suffixes) > 0 {
+		sql.WriteString(" ")
+		if args, err = b.suffixes.AppendToSql(sql, " ", args); err != nil {
+			return
+		}
+	}
+
+	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
+	return
+}
+
+// Table sets the name of the table to be created.
+func (b *CreateTableBuilder) Table(table string) *CreateTableBuilder {
+	b.table = table
+	return b
+}
+
+// IfNotExists adds IF NOT EXISTS to the statement.
+func (b *CreateTableBuilder) IfNotExists() *CreateTableBuilder {
+	b.ifNotExists = true
+	return b
+}
+
+// Column adds a column definition to the table. Column constraints like
+// NOT NULL or DEFAULT are appended in the given order, for example:
+//   Column("status", "text", "NOT NULL", "DEFAULT 'new'")
+func (b *CreateTableBuilder) Column(name, dataType string, constraints ...string) *CreateTableBuilder {
+	def := name + " " + dataType
+	if len(constraints) > 0 {
+		def += " " + strings.Join(constraints, " ")
+	}
+	b.columns = append(b.columns, def)
+	return b
+}
+
+// Constraint adds a named table constraint, for example:
+//   Constraint("users_age_check", "CHECK (age >= 0)")
+func (b *CreateTableBuilder) Constraint(name, definition string) *CreateTableBuilder {
+	b.constraints = append(b.constraints, fmt.Sprintf("CONSTRAINT %s %s", name, definition))
+	return b
+}
+
+// PrimaryKey adds a PRIMARY KEY table constraint on given columns.
+func (b *CreateTableBuilder) PrimaryKey(columns ...string) *CreateTableBuilder {
+	b.constraints = append(b.constraints, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(columns, ", ")))
+	return b
+}
+
+// Unique adds a UNIQUE table constraint on given columns.
+func (b *CreateTableBuilder) Unique(columns ...string) *CreateTableBuilder {
+	b.constraints = append(b.constraints, fmt.Sprintf("UNIQUE (%s)", strings.Join(columns, ", ")))
+	return b
+}
+
+// ForeignKey adds a FOREIGN KEY table constraint. references holds the
+// referenced table and columns and optional actions, for example:
+//   ForeignKey("users (id) ON DELETE CASCADE", "user_id")
+func (b *CreateTableBuilder) ForeignKey(references string, columns ...string) *CreateTableBuilder {
+	b.constraints = append(b.constraints, fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s", strings.Join(columns, ", "), references))
+	return b
+}
+
+// Check adds a CHECK table constraint.
+func (b *CreateTableBuilder) Check(expr string) *CreateTableBuilder {
+	b.constraints = append(b.constraints, fmt.Sprintf("CHECK (%s)", expr))
+	return b
+}
+
+// PartitionOf creates the table as a partition of parent with the given
+// bound, see RangeBound, ListBound, HashBound and DefaultBound.
+//
+// CREATE TABLE ... PARTITION OF is PostgreSQL specific extension
+func (b *CreateTableBuilder) PartitionOf(parent string, bound Sqlizer) *CreateTableBuilder {
+	b.partitionOf = parent
+	b.bound = bound
+	return b
+}
+
+// PartitionBy sets the PARTITION BY clause of the statement, for example:
+//   PartitionBy("RANGE (created_at)")
+//
+// CREATE TABLE ... PARTITION BY is PostgreSQL specific extension
+func (b *CreateTableBuilder) PartitionBy(partitionBy string) *CreateTableBuilder {
+	b.partitionBy = partitionBy
+	return b
+}
+
+// Suffix adds an expression to the end of the statement
+func (b *CreateTableBuilder) Suffix(sql string, args ...interface{}) *CreateTableBuilder {
+	b.suffixes = append(b.suffixes, Expr(sql, args...))
+	return b
+}
@@ -0,0 +1,84 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTableBuilderToSql(t *testing.T) {
+	b := CreateTable("orders").
+		IfNotExists().
+		Column("id", "bigserial").
+		Column("user_id", "bigint", "NOT NULL").
+		Column("status", "text", "NOT NULL", "DEFAULT 'new'").
+		Column("total", "numeric(12,2)").
+		Column("created_at", "timestamptz", "NOT NULL", "DEFAULT now()").
+		PrimaryKey("id", "created_at").
+		Unique("user_id", "created_at").
+		ForeignKey("users (id) ON DELETE CASCADE", "user_id").
+		Check("total >= 0").
+		Constraint("orders_status_check", "CHECK (status <> '')").
+		PartitionBy("RANGE (created_at)")
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql :=
+		"CREATE TABLE IF NOT EXISTS orders (" +
+			"id bigserial, user_id bigint NOT NULL, status text NOT NULL DEFAULT 'new', " +
+			"total numeric(12,2), created_at timestamptz NOT NULL DEFAULT now(), " +
+			"PRIMARY KEY (id, created_at), UNIQUE (user_id, created_at), " +
+			"FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE, " +
+			"CHECK (total >= 0), CONSTRAINT orders_status_check CHECK (status <> '')" +
+			") PARTITION BY RANGE (created_at)"
+	assert.Equal(t, expectedSql, sql)
+	assert.Empty(t, args)
+}
+
+func TestCreateTableBuilderSuffix(t *testing.T) {
+	sql, _, err := CreateTable("t").Column("a", "int").Suffix("WITH (fillfactor = 70)").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE t (a int) WITH (fillfactor = 70)", sql)
+}
+
+func TestCreateTableBuilderToSqlErr(t *testing.T) {
+	_, _, err := CreateTable("").Column("a", "int").ToSql()
+	assert.Error(t, err)
+
+	_, _, err = CreateTable("t").ToSql()
+	assert.Error(t, err)
+}
+
+func TestCreateTableBuilderPartitionOf(t *testing.T) {
+	sql, args, err := CreateTable("events_2020_01").
+		IfNotExists().
+		PartitionOf("events", RangeBound("2020-01-01", "2020-02-01")).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE IF NOT EXISTS events_2020_01 PARTITION OF events "+
+		"FOR VALUES FROM ('2020-01-01') TO ('2020-02-01')", sql)
+	assert.Empty(t, args)
+
+	sql, _, err = CreateTable("events_eu").
+		PartitionOf("events", ListBound("de", "fr")).
+		Check("id > 0").
+		PartitionBy("RANGE (created_at)").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE events_eu PARTITION OF events (CHECK (id > 0)) "+
+		"FOR VALUES IN ('de', 'fr') PARTITION BY RANGE (created_at)", sql)
+
+	sql, _, err = CreateTable("events_q").
+		PartitionOf("events", ListBound("a?b")).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE events_q PARTITION OF events FOR VALUES IN ('a?b')", sql)
+
+	_, _, err = CreateTable("events_x").PartitionOf("events", nil).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = CreateTable("events_x").PartitionOf("events", DefaultBound()).Column("a", "int").ToSql()
+	assert.Error(t, err)
+}
@@ -0,0 +1,202 @@
+package sqrl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgx/v4"
+	"regexp"
+	"sync/atomic"
+)
+
+var cursorSeq uint64
+
+var cursorNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// appendCurrentOf writes the WHERE CURRENT OF clause of an update or delete
+// positioned on cursor. Postgres does not allow other conditions with it.
+func appendCurrentOf(sql *bytes.Buffer, cursor string, whereParts []Sqlizer) error {
+	if !cursorNameRegexp.MatchString(cursor) {
+		return fmt.Errorf("invalid cursor name %q", cursor)
+	}
+	if len(whereParts) > 0 {
+		return fmt.Errorf("WHERE CURRENT OF can not be combined with other WHERE conditions")
+	}
+	sql.WriteString(" WHERE CURRENT OF ")
+	sql.WriteString(cursor)
+	return nil
+}
+
+// Stream runs the query through a server-side cursor and calls fn for every
+// row of the result. Rows are fetched in batches of batchSize, so arbitrarily
+// large results can be processed without holding them in memory.
+//
+// The cursor lives inside a transaction which is committed once all rows have
+// been consumed. An error returned by fn stops the iteration and rolls the
+// transaction back. Use StreamCursor to update or delete the streamed rows.
+func (b *SelectBuilder) Stream(ctx context.Context, pool instapgxpool.Pool, batchSize int, fn func(rows pgx.Rows) error) error {
+	if batchSize < 1 {
+		return fmt.Errorf("stream batch size must be positive, got %d", batchSize)
+	}
+
+	if err := checkDryRun(ctx, pool, b); err != nil {
+		return err
+	}
+	query, args, err := buildQuery(ctx, b)
+	if err != nil {
+		return err
+	}
+
+	tx, err := beginTx(ctx, contextPool(ctx, pool))
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	name, err := declareCursor(ctx, tx, query, args)
+	if err != nil {
+		return err
+	}
+
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM %s", batchSize, name)
+	for {
+		n, err := fetchBatch(ctx, tx, fetch, fn)
+		if err != nil {
+			return err
+		}
+		if n < batchSize {
+			break
+		}
+	}
+
+	if _, err = tx.Exec(ctx, "CLOSE "+name); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Cursor is the server-side cursor of SelectBuilder.StreamCursor, positioned
+// on the current row.
+type Cursor struct {
+	// Name is the name of the cursor, e.g. for UpdateBuilder.WhereCurrentOf.
+	Name string
+	// Pool runs statements in the transaction of the cursor.
+	Pool instapgxpool.Pool
+
+	rows pgx.Rows
+}
+
+// Scan reads the values of the current row into dest. It must be called
+// before statements are run with Pool and can only be called once per row.
+func (c *Cursor) Scan(dest ...interface{}) error {
+	if c.rows == nil {
+		return fmt.Errorf("row of cursor %s has already been scanned", c.Name)
+	}
+	rows := c.rows
+	c.rows = nil
+	defer rows.Close()
+	if err := rows.Scan(dest...); err != nil {
+		return err
+	}
+	rows.Close()
+	return rows.Err()
+}
+
+// StreamCursor runs the query through a server-side cursor and calls fn for
+// every row of the result, fetching one row at a time, so fn can update or
+// delete the current row with WhereCurrentOf. The query must select from a
+// single table without grouping; add FOR UPDATE to lock the rows as they
+// are fetched.
+//
+// Like with Stream, the cursor lives inside a transaction which is
+// committed once all rows have been consumed and rolled back if fn fails.
+//
+// Ex:
+//     err := sqrl.Select("id", "payload").From("jobs").Where("done = ?", false).Suffix("FOR UPDATE").
+//         StreamCursor(ctx, pool, func(c *sqrl.Cursor) error {
+//             var id int64
+//             var payload string
+//             if err := c.Scan(&id, &payload); err != nil {
+//                 return err
+//             }
+//             ...
+//             _, err := sqrl.Update("jobs").Set("done", true).WhereCurrentOf(c.Name).ExecContext(ctx, c.Pool)
+//             return err
+//         })
+func (b *SelectBuilder) StreamCursor(ctx context.Context, pool instapgxpool.Pool, fn func(c *Cursor) error) error {
+	if err := checkDryRun(ctx, pool, b); err != nil {
+		return err
+	}
+	query, args, err := buildQuery(ctx, b)
+	if err != nil {
+		return err
+	}
+
+	tx, err := beginTx(ctx, contextPool(ctx, pool))
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	name, err := declareCursor(ctx, tx, query, args)
+	if err != nil {
+		return err
+	}
+
+	txp := &txPool{Pool: pool, tx: tx}
+	for {
+		rows, err := tx.Query(ctx, "FETCH NEXT FROM "+name)
+		if err != nil {
+			return err
+		}
+		if !rows.Next() {
+			rows.Close()
+			if err = rows.Err(); err != nil {
+				return err
+			}
+			break
+		}
+
+		c := &Cursor{Name: name, Pool: txp, rows: rows}
+		err = fn(c)
+		rows.Close()
+		if err != nil {
+			return err
+		}
+		if err = rows.Err(); err != nil {
+			return err
+		}
+	}
+
+	if _, err = tx.Exec(ctx, "CLOSE "+name); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// declareCursor declares a cursor for query in tx and returns its name.
+func declareCursor(ctx context.Context, tx pgx.Tx, query string, args []interface{}) (string, error) {
+	name := fmt.Sprintf("sqrl_cursor_%d", atomic.AddUint64(&cursorSeq, 1))
+	if _, err := tx.Exec(ctx, "DECLARE "+name+" NO SCROLL CURSOR FOR "+query, args...); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// fetchBatch runs a single FETCH and hands every returned row to fn.
+func fetchBatch(ctx context.Context, tx pgx.Tx, fetch string, fn func(rows pgx.Rows) error) (n int, err error) {
+	rows, err := tx.Query(ctx, fetch)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		n++
+		if err = fn(rows); err != nil {
+			return n, err
+		}
+	}
+	return n, rows.Err()
+}
@@ -0,0 +1,104 @@
+package sqrl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectBuilderStream(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{1}, {2}}, {{3}}}
+
+	var ids []int
+	err := Select("id").From("users").Where("active = $1", true).
+		Stream(context.Background(), pool, 2, func(rows pgx.Rows) error {
+			var id int
+			err := rows.Scan(&id)
+			ids = append(ids, id)
+			return err
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, ids)
+
+	if assert.Len(t, pool.stub.sqls, 4) {
+		name := strings.Fields(pool.stub.sqls[0])[1]
+		assert.Equal(t, "DECLARE "+name+" NO SCROLL CURSOR FOR SELECT id FROM users WHERE active = $1", pool.stub.sqls[0])
+		assert.Equal(t, []interface{}{true}, pool.stub.args[0])
+		assert.Equal(t, "FETCH FORWARD 2 FROM "+name, pool.stub.sqls[1])
+		assert.Equal(t, "FETCH FORWARD 2 FROM "+name, pool.stub.sqls[2])
+		assert.Equal(t, "CLOSE "+name, pool.stub.sqls[3])
+	}
+	assert.Equal(t, 1, pool.stub.committed)
+	assert.Equal(t, 0, pool.stub.rolledBack)
+}
+
+func TestSelectBuilderStreamCallbackErr(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{1}, {2}}}
+
+	stop := errors.New("stop")
+	err := Select("id").From("users").
+		Stream(context.Background(), pool, 2, func(rows pgx.Rows) error {
+			return stop
+		})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 0, pool.stub.committed)
+	assert.Equal(t, 1, pool.stub.rolledBack)
+}
+
+func TestSelectBuilderStreamInvalidBatchSize(t *testing.T) {
+	pool := newPoolStub()
+	err := Select("id").From("users").
+		Stream(context.Background(), pool, 0, func(rows pgx.Rows) error { return nil })
+	assert.Error(t, err)
+	assert.Empty(t, pool.stub.sqls)
+}
+
+func TestSelectBuilderStreamCursor(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{1}}, {{2}}, {}}
+
+	var ids []int
+	err := Select("id").From("jobs").Suffix("FOR UPDATE").
+		StreamCursor(context.Background(), pool, func(c *Cursor) error {
+			var id int
+			if err := c.Scan(&id); err != nil {
+				return err
+			}
+			assert.Error(t, c.Scan(&id))
+			ids = append(ids, id)
+			_, err := Update("jobs").Set("done", true).WhereCurrentOf(c.Name).ExecContext(context.Background(), c.Pool)
+			return err
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, ids)
+
+	if assert.Len(t, pool.stub.sqls, 7) {
+		name := strings.Fields(pool.stub.sqls[0])[1]
+		assert.Equal(t, "DECLARE "+name+" NO SCROLL CURSOR FOR SELECT id FROM jobs FOR UPDATE", pool.stub.sqls[0])
+		assert.Equal(t, "FETCH NEXT FROM "+name, pool.stub.sqls[1])
+		assert.Equal(t, "UPDATE jobs SET done = ? WHERE CURRENT OF "+name, pool.stub.sqls[2])
+		assert.Equal(t, "FETCH NEXT FROM "+name, pool.stub.sqls[5])
+		assert.Equal(t, "CLOSE "+name, pool.stub.sqls[6])
+	}
+	assert.Equal(t, 1, pool.stub.committed)
+}
+
+func TestSelectBuilderStreamCursorCallbackErr(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{1}}}
+
+	stop := errors.New("stop")
+	err := Select("id").From("jobs").
+		StreamCursor(context.Background(), pool, func(c *Cursor) error {
+			return stop
+		})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 0, pool.stub.committed)
+	assert.Equal(t, 1, pool.stub.rolledBack)
+}
@@ -25,6 +25,7 @@ type DeleteBuilder struct {
 	joins      []string
 	usingParts []Sqlizer
 	whereParts []Sqlizer
+	currentOf  string
 	orderBys   []string
 
 	limit       uint64
@@ -40,19 +41,75 @@ func NewDeleteBuilder(b StatementBuilderType) *DeleteBuilder {
 	return &DeleteBuilder{StatementBuilderType: b}
 }
 
-// ExecContext builds and Execs the query with the Runner set by RunWith using given context.
+// ExecContext builds and Execs the query using given context.
 func (b *DeleteBuilder) ExecContext(ctx context.Context, pool instapgxpool.Pool) (pgconn.CommandTag, error) {
-	return ExecWithContext(ctx, pool, b)
+	return b.execContext(ctx, pool, b)
+}
+
+// ExecAttached is like ExecContext, using the pool set with RunWithPool.
+func (b *DeleteBuilder) ExecAttached(ctx context.Context) (pgconn.CommandTag, error) {
+	return b.ExecContext(ctx, nil)
 }
 
 // QueryContext builds and runs the query using given context and Query command.
 func (b *DeleteBuilder) QueryContext(ctx context.Context, pool instapgxpool.Pool) (pgx.Rows, error) {
-	return QueryWithContext(ctx, pool, b)
+	return b.queryContext(ctx, pool, b)
+}
+
+// QueryAttached is like QueryContext, using the pool set with RunWithPool.
+func (b *DeleteBuilder) QueryAttached(ctx context.Context) (pgx.Rows, error) {
+	return b.QueryContext(ctx, nil)
 }
 
 // QueryRowContext builds and runs the query using given context.
 func (b *DeleteBuilder) QueryRowContext(ctx context.Context, pool instapgxpool.Pool) RowScanner {
-	return QueryRowWithContext(ctx, pool, b)
+	return b.queryRowContext(ctx, pool, b)
+}
+
+// QueryRowAttached is like QueryRowContext, using the pool set with RunWithPool.
+func (b *DeleteBuilder) QueryRowAttached(ctx context.Context) RowScanner {
+	return b.QueryRowContext(ctx, nil)
+}
+
+// StreamReturning runs the query, which must have a RETURNING clause, and
+// calls fn for every deleted row as it arrives, so deleted rows can be
+// archived without buffering them. Iteration stops at the first error
+// returned by fn, which is then returned by StreamReturning.
+//
+// The rows are deleted regardless of fn; run the query in a transaction,
+// e.g. with RunInTx, to keep them if archiving them fails.
+//
+// Ex:
+//     err := sqrl.RunInTx(ctx, pool, sqrl.TxOptions{}, func(pool instapgxpool.Pool) error {
+//         return sqrl.Delete("events").Where(sqrl.Lt{"created_at": cutoff}).Returning("*").
+//             StreamReturning(ctx, pool, func(rows pgx.Rows) error {
+//                 values, err := rows.Values()
+//                 ...
+//                 return archive.Write(values)
+//             })
+//     })
+func (b *DeleteBuilder) StreamReturning(ctx context.Context, pool instapgxpool.Pool, fn func(rows pgx.Rows) error) error {
+	if len(b.returning) == 0 {
+		return fmt.Errorf("streamed delete statements must have a RETURNING clause")
+	}
+	rows, err := b.QueryContext(ctx, pool)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := fn(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// StreamReturningAttached is like StreamReturning, using the pool set with
+// RunWithPool.
+func (b *DeleteBuilder) StreamReturningAttached(ctx context.Context, fn func(rows pgx.Rows) error) error {
+	return b.StreamReturning(ctx, nil, fn)
 }
 
 // Scan is a shortcut for QueryRow().Scan.
@@ -60,6 +117,47 @@ func (b *DeleteBuilder) Scan(ctx context.Context, pool instapgxpool.Pool, dest .
 	return b.QueryRowContext(ctx, pool).Scan(dest...)
 }
 
+// ScanContext is a shortcut for QueryRowAttached(ctx).Scan.
+func (b *DeleteBuilder) ScanContext(ctx context.Context, dest ...interface{}) error {
+	return b.QueryRowAttached(ctx).Scan(dest...)
+}
+
+// RunWithPool sets the pool used by ExecContext, QueryContext and
+// QueryRowContext when none is passed explicitly.
+func (b *DeleteBuilder) RunWithPool(pool instapgxpool.Pool) *DeleteBuilder {
+	b.runWithPool = pool
+	return b
+}
+
+// DryRun makes ExecContext, QueryContext and QueryRowContext return a
+// DryRunResult with the SQL and args instead of running the query.
+func (b *DeleteBuilder) DryRun() *DeleteBuilder {
+	b.dryRun = dryRunSQL
+	return b
+}
+
+// DryRunExplain is like DryRun, but also runs EXPLAIN for the query and
+// returns the plan with the DryRunResult.
+func (b *DeleteBuilder) DryRunExplain() *DeleteBuilder {
+	b.dryRun = dryRunExplain
+	return b
+}
+
+// SimpleProtocol makes ExecContext, QueryContext and QueryRowContext run the
+// query using the simple query protocol, with all args inlined as literals
+// (see ToSqlInlined).
+func (b *DeleteBuilder) SimpleProtocol() *DeleteBuilder {
+	b.simpleProtocol = true
+	return b
+}
+
+// Strict makes ToSql reject suspicious content in the strings interpolated
+// verbatim, see StatementBuilderType.Strict.
+func (b *DeleteBuilder) Strict() *DeleteBuilder {
+	b.strict = true
+	return b
+}
+
 // PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
 // query.
 func (b *DeleteBuilder) PlaceholderFormat(f PlaceholderFormat) *DeleteBuilder {
@@ -67,6 +165,25 @@ func (b *DeleteBuilder) PlaceholderFormat(f PlaceholderFormat) *DeleteBuilder {
 	return b
 }
 
+// MustSql builds the query like ToSql, but panics on errors. It is meant
+// for queries which are known to be valid, e.g. package level statements.
+func (b *DeleteBuilder) MustSql() (string, []interface{}) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// ToSqlInlined builds the query into a SQL string with all args inlined as
+// literals, e.g. for migration files or seed scripts. It fails for args
+// which can not be rendered as literals.
+func (b *DeleteBuilder) ToSqlInlined() (string, error) {
+	c := *b
+	c.placeholderFormat = Question
+	return toSqlInlined(&c)
+}
+
 // ToSql builds the query into a SQL string and bound args.
 func (b *DeleteBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 	if len(b.from) == 0 {
@@ -74,10 +191,24 @@ func (b *DeleteBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 		return
 	}
 
+	if b.strict {
+		if err = checkStrict(b); err != nil {
+			return
+		}
+	}
+	if err = b.schema.Validate(b); err != nil {
+		return
+	}
+	if err = b.whereConflicts.check(b.whereParts); err != nil {
+		return
+	}
+
 	sql := &bytes.Buffer{}
 
 	if len(b.prefixes) > 0 {
-		args, _ = b.prefixes.AppendToSql(sql, " ", args)
+		if args, err = b.prefixes.AppendToSql(sql, " ", args); err != nil {
+			return
+		}
 		sql.WriteString(" ")
 	}
 
@@ -105,9 +236,12 @@ func (b *DeleteBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 		}
 	}
 
-	if len(b.whereParts) > 0 {
-		sql.WriteString(" WHERE ")
-		args, err = appendToSql(b.whereParts, sql, " AND ", args)
+	if len(b.currentOf) > 0 {
+		if err = appendCurrentOf(sql, b.currentOf, b.whereParts); err != nil {
+			return
+		}
+	} else if len(b.whereParts) > 0 {
+		args, err = appendConditionsToSql([]Sqlizer{writeConditions(b.whereParts)}, sql, " WHERE ", args)
 		if err != nil {
 			return
 		}
@@ -138,7 +272,9 @@ func (b *DeleteBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 
 	if len(b.suffixes) > 0 {
 		sql.WriteString(" ")
-		args, _ = b.suffixes.AppendToSql(sql, " ", args)
+		if args, err = b.suffixes.AppendToSql(sql, " ", args); err != nil {
+			return
+		}
 	}
 
 	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
@@ -151,6 +287,14 @@ func (b *DeleteBuilder) Prefix(sql string, args ...interface{}) *DeleteBuilder {
 	return b
 }
 
+// PrefixExpr adds an expression built by a Sqlizer, e.g. a SelectBuilder
+// used as the body of a CTE, to the beginning of the query. Its args are
+// merged in order and its errors are returned by ToSql.
+func (b *DeleteBuilder) PrefixExpr(e Sqlizer) *DeleteBuilder {
+	b.prefixes = append(b.prefixes, Expr("?", e))
+	return b
+}
+
 // From sets the FROM clause of the query.
 func (b *DeleteBuilder) From(from string) *DeleteBuilder {
 	b.from = from
@@ -195,6 +339,14 @@ func (b *DeleteBuilder) UsingSelect(from *SelectBuilder, alias string) *DeleteBu
 	return b
 }
 
+// WhereCurrentOf restricts the query to the row cursor is positioned on.
+//
+// See UpdateBuilder.WhereCurrentOf for more information.
+func (b *DeleteBuilder) WhereCurrentOf(cursor string) *DeleteBuilder {
+	b.currentOf = cursor
+	return b
+}
+
 // Where adds WHERE expressions to the query.
 func (b *DeleteBuilder) Where(pred interface{}, args ...interface{}) *DeleteBuilder {
 	b.whereParts = append(b.whereParts, newWherePart(pred, args...))
@@ -243,6 +395,13 @@ func (b *DeleteBuilder) Suffix(sql string, args ...interface{}) *DeleteBuilder {
 	return b
 }
 
+// SuffixExpr adds an expression built by a Sqlizer to the end of the query.
+// Its args are merged in order and its errors are returned by ToSql.
+func (b *DeleteBuilder) SuffixExpr(e Sqlizer) *DeleteBuilder {
+	b.suffixes = append(b.suffixes, Expr("?", e))
+	return b
+}
+
 // JoinClause adds a join clause to the query.
 func (b *DeleteBuilder) JoinClause(join string) *DeleteBuilder {
 	b.joins = append(b.joins, join)
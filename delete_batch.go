@@ -0,0 +1,112 @@
+package sqrl
+
+import (
+	"context"
+	"fmt"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"reflect"
+	"time"
+)
+
+// DeleteByKeys deletes the rows of table whose keyCol is one of keys, a
+// slice, with DELETE statements matching at most chunkSize keys each with
+// keyCol = ANY(?). All statements are run in a single transaction, so
+// either all rows are deleted or none. DeleteByKeys returns the number of
+// rows deleted.
+//
+// Ex:
+//     n, err := sqrl.DeleteByKeys(ctx, pool, "events", "user_id", userIDs, 1000)
+func DeleteByKeys(ctx context.Context, pool instapgxpool.Pool, table, keyCol string, keys interface{}, chunkSize int) (int64, error) {
+	if chunkSize < 1 {
+		return 0, fmt.Errorf("chunk size must be positive, got %d", chunkSize)
+	}
+	v := reflect.ValueOf(keys)
+	if v.Kind() != reflect.Slice {
+		return 0, fmt.Errorf("keys for %s must be a slice, got %T", keyCol, keys)
+	}
+	if v.Len() == 0 {
+		return 0, nil
+	}
+
+	var deleted int64
+	err := RunInTx(ctx, pool, TxOptions{}, func(pool instapgxpool.Pool) error {
+		deleted = 0
+		for start := 0; start < v.Len(); start += chunkSize {
+			end := start + chunkSize
+			if end > v.Len() {
+				end = v.Len()
+			}
+			chunk := v.Slice(start, end).Interface()
+			n, err := ExecAffecting(ctx, pool, StatementBuilder.PlaceholderFormat(Dollar).
+				Delete(table).Where(keyCol+" = ANY(?)", chunk))
+			if err != nil {
+				return err
+			}
+			deleted += n
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+// DeleteInBatches runs the delete built by b in batches of at most
+// batchSize rows until no matching rows remain, pausing between batches, so
+// pruning old rows does not hold locks on millions of rows in one long
+// statement. Batches are limited with a subquery selecting the ctids of the
+// rows to delete, ordered by the ORDER BY expressions of b, if any. Every
+// batch is a statement of its own, unless ctx carries a transaction.
+// DeleteInBatches returns the number of rows deleted, also if it fails or
+// ctx is cancelled during a pause.
+//
+// Deletes with USING, joins, LIMIT or OFFSET are not supported.
+//
+// Ex:
+//     n, err := sqrl.DeleteInBatches(ctx, pool, sqrl.Delete("events").
+//         Where(sqrl.Lt{"created_at": time.Now().AddDate(0, -6, 0)}).
+//         OrderBy("created_at"), 5000, 100*time.Millisecond)
+func DeleteInBatches(ctx context.Context, pool instapgxpool.Pool, b *DeleteBuilder, batchSize uint64, pause time.Duration) (int64, error) {
+	if batchSize < 1 {
+		return 0, fmt.Errorf("batch size must be positive, got %d", batchSize)
+	}
+	if len(b.from) == 0 {
+		return 0, fmt.Errorf("delete statements must specify a From table")
+	}
+	if len(b.usingParts) > 0 || len(b.joins) > 0 || b.limitValid || b.offsetValid {
+		return 0, fmt.Errorf("batched deletes must not have USING, joins, LIMIT or OFFSET")
+	}
+
+	sub := NewSelectBuilder(b.StatementBuilderType).Columns("ctid").From(b.from).
+		OrderBy(b.orderBys...).Limit(batchSize)
+	if len(b.whereParts) > 0 {
+		sub.whereParts = []Sqlizer{writeConditions(b.whereParts)}
+	}
+
+	batch := *b
+	batch.whereParts = []Sqlizer{newWherePart(Expr("ctid = ANY(ARRAY?)", Subquery(sub)))}
+	batch.orderBys = nil
+
+	var deleted int64
+	for {
+		n, err := ExecAffecting(ctx, pool, &batch)
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+		if n < int64(batchSize) {
+			return deleted, nil
+		}
+
+		if pause > 0 {
+			timer := time.NewTimer(pause)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return deleted, ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+}
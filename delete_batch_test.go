@@ -0,0 +1,123 @@
+package sqrl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteByKeys(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.tag = pgconn.CommandTag("DELETE 2")
+
+	n, err := DeleteByKeys(context.Background(), pool, "events", "user_id", []int64{1, 2, 3, 4, 5}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), n)
+	assert.Equal(t, []string{
+		"DELETE FROM events WHERE user_id = ANY($1)",
+		"DELETE FROM events WHERE user_id = ANY($1)",
+		"DELETE FROM events WHERE user_id = ANY($1)",
+	}, pool.stub.sqls)
+	assert.Equal(t, [][]interface{}{{[]int64{1, 2}}, {[]int64{3, 4}}, {[]int64{5}}}, pool.stub.args)
+	assert.Equal(t, 1, pool.stub.begun)
+	assert.Equal(t, 1, pool.stub.committed)
+}
+
+func TestDeleteByKeysErrors(t *testing.T) {
+	pool := newPoolStub()
+
+	n, err := DeleteByKeys(context.Background(), pool, "events", "user_id", []int64{}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+	assert.Equal(t, 0, pool.stub.begun)
+
+	_, err = DeleteByKeys(context.Background(), pool, "events", "user_id", []int64{1}, 0)
+	assert.EqualError(t, err, "chunk size must be positive, got 0")
+
+	_, err = DeleteByKeys(context.Background(), pool, "events", "user_id", int64(1), 10)
+	assert.EqualError(t, err, "keys for user_id must be a slice, got int64")
+
+	pool.stub.err = errors.New("permission denied")
+	_, err = DeleteByKeys(context.Background(), pool, "events", "user_id", []int64{1}, 10)
+	assert.True(t, errors.Is(err, pool.stub.err))
+	assert.Equal(t, 1, pool.stub.rolledBack)
+}
+
+func TestDeleteInBatches(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.tag = pgconn.CommandTag("DELETE 2")
+
+	b := StatementBuilder.PlaceholderFormat(Dollar).Delete("events").
+		Where(Lt{"created_at": 10}).Where(Eq{"kind": "debug"}).
+		OrderBy("created_at")
+	deletes := 0
+	stub := &countingPool{poolStub: pool, exec: func() {
+		deletes++
+		if deletes == 2 {
+			pool.stub.tag = pgconn.CommandTag("DELETE 1")
+		}
+	}}
+	n, err := DeleteInBatches(context.Background(), stub, b, 2, time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	if assert.Len(t, pool.stub.sqls, 3) {
+		assert.Equal(t, "DELETE FROM events WHERE ctid = ANY(ARRAY(SELECT ctid FROM events WHERE created_at < $1 AND kind = $2 ORDER BY created_at LIMIT 2))", pool.stub.sqls[0])
+		assert.Equal(t, []interface{}{10, "debug"}, pool.stub.args[0])
+	}
+
+	sql, _, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM events WHERE created_at < $1 AND kind = $2 ORDER BY created_at", sql)
+}
+
+type countingPool struct {
+	*poolStub
+	exec func()
+}
+
+func (p *countingPool) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	tag, err := p.poolStub.Exec(ctx, sql, args...)
+	p.exec()
+	return tag, err
+}
+
+func TestDeleteInBatchesEmptyConditions(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.tag = pgconn.CommandTag("DELETE 0")
+
+	var id *int64
+	_, err := DeleteInBatches(context.Background(), pool, Delete("events").Where(EqNotZero{"id": id}), 10, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, pool.stub.sqls, 1) {
+		assert.Equal(t, "DELETE FROM events WHERE ctid = ANY(ARRAY(SELECT ctid FROM events WHERE FALSE LIMIT 10))", pool.stub.sqls[0])
+	}
+}
+
+func TestDeleteInBatchesCancelled(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.tag = pgconn.CommandTag("DELETE 2")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	n, err := DeleteInBatches(ctx, pool, Delete("events"), 2, time.Hour)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, int64(2), n)
+}
+
+func TestDeleteInBatchesErrors(t *testing.T) {
+	pool := newPoolStub()
+
+	_, err := DeleteInBatches(context.Background(), pool, Delete("events"), 0, 0)
+	assert.EqualError(t, err, "batch size must be positive, got 0")
+
+	_, err = DeleteInBatches(context.Background(), pool, Delete("events").Limit(10), 10, 0)
+	assert.EqualError(t, err, "batched deletes must not have USING, joins, LIMIT or OFFSET")
+
+	_, err = DeleteInBatches(context.Background(), pool, Delete(""), 10, 0)
+	assert.EqualError(t, err, "delete statements must specify a From table")
+	assert.Empty(t, pool.stub.sqls)
+}
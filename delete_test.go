@@ -1,8 +1,11 @@
 package sqrl
 
 import (
+	"context"
+	"errors"
 	"testing"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -29,6 +32,16 @@ func TestDeleteBuilderToSql(t *testing.T) {
 	assert.Equal(t, expectedArgs, args)
 }
 
+func TestDeleteBuilderWhereCurrentOf(t *testing.T) {
+	sql, args, err := Delete("a").WhereCurrentOf("c").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM a WHERE CURRENT OF c", sql)
+	assert.Empty(t, args)
+
+	_, _, err = Delete("a").Where("b = ?", 1).WhereCurrentOf("c").ToSql()
+	assert.EqualError(t, err, "WHERE CURRENT OF can not be combined with other WHERE conditions")
+}
+
 func TestDeleteFromAndWhatDiffer(t *testing.T) {
 	b := Delete("b").
 		From("a").
@@ -180,3 +193,40 @@ func TestIssue11(t *testing.T) {
 	expectedArgs := []interface{}{1}
 	assert.Equal(t, expectedArgs, args)
 }
+
+func TestDeleteBuilderToSqlInlined(t *testing.T) {
+	sql, err := Delete("users").Where("id = ?", 1).ToSqlInlined()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users WHERE id = 1", sql)
+}
+
+func TestDeleteBuilderStreamReturning(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{1}, {2}, {3}}}
+
+	var ids []int
+	err := Delete("events").Where(Lt{"created_at": 10}).Returning("id").
+		StreamReturning(context.Background(), pool, func(rows pgx.Rows) error {
+			var id int
+			err := rows.Scan(&id)
+			ids = append(ids, id)
+			return err
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, ids)
+	assert.Equal(t, []string{"DELETE FROM events WHERE created_at < ? RETURNING id"}, pool.stub.sqls)
+
+	pool.stub.results = [][][]interface{}{{{1}, {2}, {3}}}
+	stop := errors.New("archive failed")
+	calls := 0
+	err = Delete("events").Returning("id").RunWithPool(pool).
+		StreamReturningAttached(context.Background(), func(rows pgx.Rows) error {
+			calls++
+			return stop
+		})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, calls)
+
+	err = Delete("events").StreamReturning(context.Background(), pool, func(rows pgx.Rows) error { return nil })
+	assert.EqualError(t, err, "streamed delete statements must have a RETURNING clause")
+}
@@ -0,0 +1,128 @@
+package sqrl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgconn"
+	"strings"
+)
+
+// DropBuilder builds SQL DROP TABLE, DROP INDEX and DROP VIEW statements.
+type DropBuilder struct {
+	StatementBuilderType
+
+	kind         string
+	names        []string
+	concurrently bool
+	ifExists     bool
+	cascade      bool
+	restrict     bool
+}
+
+// NewDropBuilder creates new instance of DropBuilder for the given kind of
+// object, e.g. "TABLE" or "INDEX".
+func NewDropBuilder(b StatementBuilderType, kind string) *DropBuilder {
+	return &DropBuilder{StatementBuilderType: b, kind: kind}
+}
+
+// ExecContext builds and Execs the statement using given context.
+func (b *DropBuilder) ExecContext(ctx context.Context, pool instapgxpool.Pool) (pgconn.CommandTag, error) {
+	return b.execContext(ctx, pool, b)
+}
+
+// ExecAttached is like ExecContext, using the pool set with RunWithPool.
+func (b *DropBuilder) ExecAttached(ctx context.Context) (pgconn.CommandTag, error) {
+	return b.ExecContext(ctx, nil)
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// statement.
+func (b *DropBuilder) PlaceholderFormat(f PlaceholderFormat) *DropBuilder {
+	b.placeholderFormat = f
+	return b
+}
+
+// ToSql builds the statement into a SQL string and bound args.
+func (b *DropBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
+	if len(b.names) == 0 {
+		err = fmt.Errorf("drop %s statements must specify at least one name", strings.ToLower(b.kind))
+		return
+	}
+	if b.concurrently && b.kind != "INDEX" {
+		err = fmt.Errorf("drop %s statements do not support CONCURRENTLY", strings.ToLower(b.kind))
+		return
+	}
+	if b.concurrently && len(b.names) > 1 {
+		err = fmt.Errorf("drop index statements with CONCURRENTLY can only drop one index")
+		return
+	}
+	if b.concurrently && b.cascade {
+		err = fmt.Errorf("drop index statements with CONCURRENTLY do not support CASCADE")
+		return
+	}
+	if b.cascade && b.restrict {
+		err = fmt.Errorf("drop statements can not be both CASCADE and RESTRICT")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	sql.WriteString("DROP ")
+	sql.WriteString(b.kind)
+	sql.WriteString(" ")
+
+	if b.concurrently {
+		sql.WriteString("CONCURRENTLY ")
+	}
+
+	if b.ifExists {
+		sql.WriteString("IF EXISTS ")
+	}
+
+	sql.WriteString(strings.Join(b.names, ", "))
+
+	if b.cascade {
+		sql.WriteString(" CASCADE")
+	}
+
+	if b.restrict {
+		sql.WriteString(" RESTRICT")
+	}
+
+	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
+	return
+}
+
+// Names adds names of objects to be dropped.
+func (b *DropBuilder) Names(names ...string) *DropBuilder {
+	b.names = append(b.names, names...)
+	return b
+}
+
+// Concurrently adds CONCURRENTLY to the statement. Only valid for DROP INDEX
+// of a single index without CASCADE.
+func (b *DropBuilder) Concurrently() *DropBuilder {
+	b.concurrently = true
+	return b
+}
+
+// IfExists adds IF EXISTS to the statement.
+func (b *DropBuilder) IfExists() *DropBuilder {
+	b.ifExists = true
+	return b
+}
+
+// Cascade adds CASCADE to the statement, dropping dependent objects as well.
+func (b *DropBuilder) Cascade() *DropBuilder {
+	b.cascade = true
+	return b
+}
+
+// Restrict adds RESTRICT to the statement, refusing to drop objects which
+// have dependent objects.
+func (b *DropBuilder) Restrict() *DropBuilder {
+	b.restrict = true
+	return b
+}
@@ -0,0 +1,39 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDropBuilderToSql(t *testing.T) {
+	sql, args, err := DropTable("a", "b").IfExists().Cascade().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DROP TABLE IF EXISTS a, b CASCADE", sql)
+	assert.Empty(t, args)
+
+	sql, _, err = DropIndex("idx_a").Concurrently().IfExists().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DROP INDEX CONCURRENTLY IF EXISTS idx_a", sql)
+
+	sql, _, err = DropView("v").Restrict().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DROP VIEW v RESTRICT", sql)
+}
+
+func TestDropBuilderToSqlErr(t *testing.T) {
+	_, _, err := DropTable().ToSql()
+	assert.Error(t, err)
+
+	_, _, err = DropTable("a").Concurrently().ToSql()
+	assert.Error(t, err)
+
+	_, _, err = DropView("v").Cascade().Restrict().ToSql()
+	assert.Error(t, err)
+
+	_, _, err = DropIndex("a_idx", "b_idx").Concurrently().ToSql()
+	assert.EqualError(t, err, "drop index statements with CONCURRENTLY can only drop one index")
+
+	_, _, err = DropIndex("a_idx").Concurrently().Cascade().ToSql()
+	assert.EqualError(t, err, "drop index statements with CONCURRENTLY do not support CASCADE")
+}
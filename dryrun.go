@@ -0,0 +1,134 @@
+package sqrl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"strings"
+)
+
+type dryRunMode int
+
+const (
+	dryRunOff dryRunMode = iota
+	dryRunSQL
+	dryRunExplain
+)
+
+// ErrDryRun matches every DryRunResult with errors.Is.
+var ErrDryRun = errors.New("dry run")
+
+// DryRunResult is returned as error by ExecContext, QueryContext and
+// QueryRowContext of builders in dry run mode, instead of running the query.
+// The package level execution helpers, e.g. ExecWithContext, QueryEach or
+// DeleteInBatches, and Stream do not run builders in dry run mode either.
+//
+// Ex:
+//     _, err := sqrl.Delete("users").Where("last_login < ?", t).DryRun().ExecContext(ctx, pool)
+//     var preview *sqrl.DryRunResult
+//     if errors.As(err, &preview) {
+//         fmt.Println(preview.SQL, preview.Args)
+//     }
+type DryRunResult struct {
+	SQL  string
+	Args []interface{}
+	// Plan holds the lines returned by EXPLAIN if the dry run was started
+	// with DryRunExplain.
+	Plan []string
+}
+
+func (r *DryRunResult) Error() string {
+	return fmt.Sprintf("dry run: %s", r.SQL)
+}
+
+// Is reports whether target is ErrDryRun.
+func (r *DryRunResult) Is(target error) bool {
+	return target == ErrDryRun
+}
+
+// DryRun makes child builders return a DryRunResult from their execution
+// methods instead of running queries.
+func (b StatementBuilderType) DryRun() StatementBuilderType {
+	b.dryRun = dryRunSQL
+	return b
+}
+
+// DryRunExplain is like DryRun, but also runs EXPLAIN for the query and
+// returns the plan with the DryRunResult. EXPLAIN without ANALYZE does not
+// execute the statement.
+func (b StatementBuilderType) DryRunExplain() StatementBuilderType {
+	b.dryRun = dryRunExplain
+	return b
+}
+
+// dryRunResult builds s and, in explain mode, runs EXPLAIN for it.
+func (b StatementBuilderType) dryRunResult(ctx context.Context, pool instapgxpool.Pool, s Sqlizer) error {
+	query, args, err := statementSql(s)
+	if err != nil {
+		return newBuildError(s, query, err)
+	}
+	result := &DryRunResult{SQL: query, Args: args}
+	if b.dryRun != dryRunExplain {
+		return result
+	}
+
+	pool, err = b.resolvePool(pool)
+	if err != nil {
+		return err
+	}
+	rows, err := pool.Query(ctx, "EXPLAIN "+query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return err
+		}
+		result.Plan = append(result.Plan, line)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return result
+}
+
+// dryRunStatement is implemented by the builders embedding
+// StatementBuilderType.
+type dryRunStatement interface {
+	statementBuilder() StatementBuilderType
+}
+
+func (b StatementBuilderType) statementBuilder() StatementBuilderType {
+	return b
+}
+
+// checkDryRun returns the DryRunResult for s if it is a builder in dry run
+// mode, and nil otherwise.
+func checkDryRun(ctx context.Context, pool instapgxpool.Pool, s Sqlizer) error {
+	d, ok := s.(dryRunStatement)
+	if !ok {
+		return nil
+	}
+	b := d.statementBuilder()
+	if b.dryRun == dryRunOff {
+		return nil
+	}
+	return b.dryRunResult(ctx, pool, s)
+}
+
+// String returns the SQL, args and plan of the dry run for display.
+func (r *DryRunResult) String() string {
+	buf := &strings.Builder{}
+	buf.WriteString(r.SQL)
+	if len(r.Args) > 0 {
+		fmt.Fprintf(buf, "\nargs: %v", r.Args)
+	}
+	for _, line := range r.Plan {
+		buf.WriteString("\n")
+		buf.WriteString(line)
+	}
+	return buf.String()
+}
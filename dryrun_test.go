@@ -0,0 +1,90 @@
+package sqrl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRun(t *testing.T) {
+	pool := newPoolStub()
+
+	_, err := Delete("users").Where("last_login < ?", 5).DryRun().ExecContext(context.Background(), pool)
+	assert.True(t, errors.Is(err, ErrDryRun))
+
+	var preview *DryRunResult
+	if assert.True(t, errors.As(err, &preview)) {
+		assert.Equal(t, "DELETE FROM users WHERE last_login < ?", preview.SQL)
+		assert.Equal(t, []interface{}{5}, preview.Args)
+		assert.Nil(t, preview.Plan)
+		assert.Equal(t, "DELETE FROM users WHERE last_login < ?\nargs: [5]", preview.String())
+	}
+	assert.Empty(t, pool.stub.sqls)
+
+	err = Select("id").From("users").DryRun().ScanContext(context.Background(), new(int))
+	assert.True(t, errors.Is(err, ErrDryRun))
+
+	_, err = Update("users").Set("a", 1).DryRun().QueryAttached(context.Background())
+	assert.True(t, errors.Is(err, ErrDryRun))
+
+	_, err = StatementBuilder.DryRun().DropTable("users").ExecContext(context.Background(), pool)
+	assert.True(t, errors.Is(err, ErrDryRun))
+	assert.Empty(t, pool.stub.sqls)
+}
+
+func TestDryRunExplain(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{"Delete on users"}, {"  ->  Seq Scan on users"}}}
+
+	_, err := Delete("users").Where("id = ?", 1).PlaceholderFormat(Dollar).DryRunExplain().
+		ExecContext(context.Background(), pool)
+	var preview *DryRunResult
+	if assert.True(t, errors.As(err, &preview)) {
+		assert.Equal(t, []string{"Delete on users", "  ->  Seq Scan on users"}, preview.Plan)
+	}
+	assert.Equal(t, []string{"EXPLAIN DELETE FROM users WHERE id = $1"}, pool.stub.sqls)
+	assert.Equal(t, [][]interface{}{{1}}, pool.stub.args)
+
+	_, err = Delete("users").DryRunExplain().ExecAttached(context.Background())
+	assert.Equal(t, ErrPoolNotSet, err)
+}
+
+func TestDryRunBuildErr(t *testing.T) {
+	_, err := Insert("users").DryRun().ExecAttached(context.Background())
+	assert.IsType(t, &BuildError{}, err)
+}
+
+func TestDryRunHelpers(t *testing.T) {
+	pool := newPoolStub()
+	ctx := context.Background()
+	del := Delete("users").Where("id = ?", 1).DryRun()
+
+	_, err := ExecWithContext(ctx, pool, del)
+	assert.True(t, errors.Is(err, ErrDryRun))
+	_, err = ExecAffecting(ctx, pool, del)
+	assert.True(t, errors.Is(err, ErrDryRun))
+	assert.True(t, errors.Is(ExecExpectingOne(ctx, pool, del), ErrDryRun))
+	_, err = DeleteInBatches(ctx, pool, del, 10, 0)
+	assert.True(t, errors.Is(err, ErrDryRun))
+
+	sel := Select("id").From("users").DryRun()
+	_, err = QueryWithContext(ctx, pool, sel)
+	assert.True(t, errors.Is(err, ErrDryRun))
+	assert.True(t, errors.Is(QueryRowWithContext(ctx, pool, sel).Scan(new(int)), ErrDryRun))
+	err = QueryEach(ctx, pool, sel, func(rows pgx.Rows) error { return nil })
+	assert.True(t, errors.Is(err, ErrDryRun))
+	err = sel.Stream(ctx, pool, 10, func(rows pgx.Rows) error { return nil })
+	assert.True(t, errors.Is(err, ErrDryRun))
+	err = sel.StreamCursor(ctx, pool, func(c *Cursor) error { return nil })
+	assert.True(t, errors.Is(err, ErrDryRun))
+
+	_, err = ExecWith(nil, del)
+	assert.True(t, errors.Is(err, ErrDryRun))
+	assert.True(t, errors.Is(QueryRowWith(nil, sel).Scan(new(int)), ErrDryRun))
+
+	assert.Empty(t, pool.stub.sqls)
+	assert.Equal(t, 0, pool.stub.begun)
+}
@@ -0,0 +1,58 @@
+package sqrl
+
+import (
+	"fmt"
+	"sync"
+)
+
+var enums = struct {
+	sync.RWMutex
+	values map[string]map[string]bool
+}{values: make(map[string]map[string]bool)}
+
+// RegisterEnum registers the allowed values of the enum type typ, so Enum
+// values of the type are validated when building the query. Registering a
+// type again replaces its values.
+//
+// Ex:
+//     sqrl.RegisterEnum("status_type", "draft", "published", "archived")
+func RegisterEnum(typ string, values ...string) {
+	allowed := make(map[string]bool, len(values))
+	for _, v := range values {
+		allowed[v] = true
+	}
+
+	enums.Lock()
+	defer enums.Unlock()
+	enums.values[typ] = allowed
+}
+
+type enum struct {
+	typ   string
+	value interface{}
+}
+
+// Enum binds value with an explicit cast to the enum type typ. If values of
+// typ were registered with RegisterEnum, building the query fails for other
+// values. A nil value binds as NULL.
+//
+// Ex:
+//     .Where(Eq{"status": Enum("status_type", "draft")})
+//     == "status = ?::status_type", "draft"
+func Enum(typ string, value interface{}) Sqlizer {
+	return enum{typ: typ, value: value}
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (e enum) ToSql() (string, []interface{}, error) {
+	value := derefArg(e.value)
+	if value != nil {
+		enums.RLock()
+		allowed, ok := enums.values[e.typ]
+		enums.RUnlock()
+		if ok && !allowed[fmt.Sprint(value)] {
+			return "", nil, fmt.Errorf("invalid value %q for enum type %s", fmt.Sprint(value), e.typ)
+		}
+	}
+	return "?::" + e.typ, []interface{}{value}, nil
+}
@@ -0,0 +1,33 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnum(t *testing.T) {
+	sql, args, err := Select("id").From("posts").Where(Eq{"status": Enum("mood_type", "happy")}).PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM posts WHERE status = $1::mood_type", sql)
+	assert.Equal(t, []interface{}{"happy"}, args)
+
+	sql, args, err = Update("posts").Set("status", Enum("mood_type", nil)).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE posts SET status = ?::mood_type", sql)
+	assert.Equal(t, []interface{}{nil}, args)
+}
+
+type postStatus string
+
+func TestEnumRegistered(t *testing.T) {
+	RegisterEnum("post_status", "draft", "published")
+
+	status := postStatus("published")
+	_, args, err := Insert("posts").Columns("status").Values(Enum("post_status", &status)).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{postStatus("published")}, args)
+
+	_, _, err = Insert("posts").Columns("status").Values(Enum("post_status", "pubished")).ToSql()
+	assert.EqualError(t, err, `invalid value "pubished" for enum type post_status`)
+}
@@ -0,0 +1,154 @@
+package sqrl
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgx/v4"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ExportCSV runs the query built by s and streams its rows to w as CSV, with
+// a header row of the column names. NULLs are written as empty fields.
+//
+// Ex:
+//     w.Header().Set("Content-Type", "text/csv")
+//     err := ExportCSV(ctx, pool, Select("id", "email").From("users"), w)
+func ExportCSV(ctx context.Context, pool instapgxpool.Pool, s Sqlizer, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := true
+
+	err := QueryEach(ctx, pool, s, func(rows pgx.Rows) error {
+		if header {
+			header = false
+			if err := cw.Write(columnNames(rows)); err != nil {
+				return err
+			}
+		}
+
+		values, err := rows.Values()
+		if err != nil {
+			return err
+		}
+		record := make([]string, len(values))
+		for i, value := range values {
+			if record[i], err = csvValue(value); err != nil {
+				return err
+			}
+		}
+		return cw.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ExportJSON runs the query built by s and streams its rows to w as a JSON
+// array of objects keyed by column name, keeping the column order.
+func ExportJSON(ctx context.Context, pool instapgxpool.Pool, s Sqlizer, w io.Writer) error {
+	var keys [][]byte
+
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	err := QueryEach(ctx, pool, s, func(rows pgx.Rows) error {
+		sep := ","
+		if keys == nil {
+			sep = ""
+			for _, name := range columnNames(rows) {
+				key, err := json.Marshal(name)
+				if err != nil {
+					return err
+				}
+				keys = append(keys, key)
+			}
+		}
+
+		values, err := rows.Values()
+		if err != nil {
+			return err
+		}
+
+		buf := []byte(sep + "{")
+		for i, value := range values {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			if value, err = driverValue(value); err != nil {
+				return err
+			}
+			data, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			buf = append(buf, keys[i]...)
+			buf = append(buf, ':')
+			buf = append(buf, data...)
+		}
+		buf = append(buf, '}')
+		_, err = w.Write(buf)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "]")
+	return err
+}
+
+func columnNames(rows pgx.Rows) []string {
+	fields := rows.FieldDescriptions()
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = string(field.Name)
+	}
+	return names
+}
+
+func driverValue(v interface{}) (interface{}, error) {
+	if valuer, ok := v.(driver.Valuer); ok {
+		return valuer.Value()
+	}
+	return v, nil
+}
+
+func csvValue(v interface{}) (string, error) {
+	v, err := driverValue(v)
+	if err != nil {
+		return "", err
+	}
+
+	switch val := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return val, nil
+	case []byte:
+		return string(val), nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	case float32:
+		return strconv.FormatFloat(float64(val), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64), nil
+	case time.Time:
+		return val.Format(time.RFC3339Nano), nil
+	case fmt.Stringer:
+		return val.String(), nil
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(val)
+		return string(data), err
+	default:
+		return fmt.Sprint(val), nil
+	}
+}
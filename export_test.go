@@ -0,0 +1,63 @@
+package sqrl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func exportPool() *poolStub {
+	pool := newPoolStub()
+	pool.stub.columns = []string{"id", "name", "created_at", "meta"}
+	pool.stub.results = [][][]interface{}{{
+		{int64(1), "a, \"b\"", time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC), map[string]interface{}{"x": 1}},
+		{int64(2), nil, nil, nil},
+	}}
+	return pool
+}
+
+func TestExportCSV(t *testing.T) {
+	pool := exportPool()
+
+	buf := &bytes.Buffer{}
+	err := ExportCSV(context.Background(), pool, Select("*").From("users"), buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "id,name,created_at,meta\n"+
+		"1,\"a, \"\"b\"\"\",2020-05-01T12:00:00Z,\"{\"\"x\"\":1}\"\n"+
+		"2,,,\n", buf.String())
+}
+
+func TestExportJSON(t *testing.T) {
+	pool := exportPool()
+
+	buf := &bytes.Buffer{}
+	err := ExportJSON(context.Background(), pool, Select("*").From("users"), buf)
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"id":1,"name":"a, \"b\"","created_at":"2020-05-01T12:00:00Z","meta":{"x":1}},`+
+		`{"id":2,"name":null,"created_at":null,"meta":null}]`, buf.String())
+}
+
+func TestExportEmpty(t *testing.T) {
+	pool := newPoolStub()
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, ExportJSON(context.Background(), pool, Select("*").From("users"), buf))
+	assert.Equal(t, "[]", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, ExportCSV(context.Background(), pool, Select("*").From("users"), buf))
+	assert.Equal(t, "", buf.String())
+}
+
+func TestExportError(t *testing.T) {
+	pool := newPoolStub()
+	boom := errors.New("boom")
+	pool.stub.err = boom
+
+	err := ExportCSV(context.Background(), pool, Select("*").From("users"), &bytes.Buffer{})
+	assert.True(t, errors.Is(err, boom))
+}
@@ -16,45 +16,126 @@ type expr struct {
 
 // Expr builds value expressions for InsertBuilder and UpdateBuilder.
 //
+// Every ? in sql is a placeholder and ToSql fails if their number does not
+// match the number of args, also for expressions without args. Literal
+// question marks, e.g. the JSONB operators ?, ?| and ?&, have to be escaped
+// as ??, or the expression passed as Raw.
+//
 // Ex:
 //     .Values(Expr("FROM_UNIXTIME(?)", t))
+//     .Where(Expr("data ?? ?", "tags"))
 func Expr(sql string, args ...interface{}) expr {
 	return expr{sql: sql, args: args}
 }
 
+// ToSql builds the query into a SQL string and bound args.
+//
+// Args which are Sqlizers are expanded in place of their placeholder, so
+// nested builders keep their args in order with the surrounding ones. ??
+// escapes are kept for the final placeholder replacement. The number of
+// placeholders has to match the number of args.
 func (e expr) ToSql() (string, []interface{}, error) {
-	if !hasSqlizer(e.args) {
-		return e.sql, e.args, nil
-	}
-
+	buf := &bytes.Buffer{}
 	args := make([]interface{}, 0, len(e.args))
-	sql, err := replacePlaceholders(e.sql, func(buf *bytes.Buffer, i int) error {
-		if i > len(e.args) {
-			buf.WriteRune('?')
-			return nil
+	sql := e.sql
+	i := 0
+	for {
+		p := strings.IndexByte(sql, '?')
+		if p == -1 {
+			break
+		}
+		buf.WriteString(sql[:p])
+
+		if strings.HasPrefix(sql[p:], "??") {
+			buf.WriteString("??")
+			sql = sql[p+2:]
+			continue
+		}
+		sql = sql[p+1:]
+
+		if i >= len(e.args) {
+			return "", nil, fmt.Errorf("expression %q has more placeholders than args (%d); escape literal question marks as ??", e.sql, len(e.args))
 		}
-		switch arg := e.args[i-1].(type) {
+		switch arg := encodeArg(e.args[i]).(type) {
 		case Sqlizer:
-			sql, vs, err := arg.ToSql()
+			argSql, argArgs, err := nested(arg).ToSql()
 			if err != nil {
-				return err
+				return "", nil, err
 			}
-			args = append(args, vs...)
-			fmt.Fprintf(buf, sql)
+			buf.WriteString(argSql)
+			args = append(args, argArgs...)
 		default:
+			buf.WriteByte('?')
 			args = append(args, arg)
-			buf.WriteRune('?')
 		}
-		return nil
-	})
+		i++
+	}
+	if i < len(e.args) {
+		return "", nil, fmt.Errorf("expression %q has %d placeholders but %d args", e.sql, i, len(e.args))
+	}
+
+	buf.WriteString(sql)
+	return buf.String(), args, nil
+}
+
+type raw string
+
+// Raw inserts trusted SQL verbatim. Unlike Expr, question marks in sql are
+// not placeholders: they are escaped as ?? so that Dollar turns them back
+// into literal question marks, e.g. for JSONB operators or quoted text.
+// Question keeps the escape in the output of ToSql, so the statement can
+// still be nested, but the execution methods send it as a literal question
+// mark as well.
+//
+// Raw must never be used with user input, as its content is not bound
+// as args.
+//
+// Ex:
+//     .Where(Raw("data ? 'tags'")).PlaceholderFormat(Dollar) == "data ? 'tags'"
+func Raw(sql string) Sqlizer {
+	return raw(sql)
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (r raw) ToSql() (string, []interface{}, error) {
+	return strings.ReplaceAll(string(r), "?", "??"), nil, nil
+}
+
+type subquery struct {
+	sb *SelectBuilder
+}
+
+// Subquery wraps sb in parentheses for use as a scalar value, e.g. in Eq,
+// Lt, Set or Values. Its args are merged into the surrounding query.
+//
+// Ex:
+//     .Where(Eq{"team_id": Subquery(Select("id").From("teams").Where("name = ?", "a"))})
+//     == "team_id = (SELECT id FROM teams WHERE name = ?)"
+func Subquery(sb *SelectBuilder) Sqlizer {
+	return subquery{sb: sb}
+}
+
+// ToSql builds the query into a SQL string and bound args.
+//
+// The subquery is always built with Question placeholders, so they are
+// numbered together with the surrounding query. It is built from a copy, so
+// sb may be shared by concurrent queries.
+func (s subquery) ToSql() (string, []interface{}, error) {
+	c := *s.sb
+	c.placeholderFormat = Question
+	c.defaultOrderBys = nil
+	sql, args, err := c.ToSql()
 	if err != nil {
 		return "", nil, err
 	}
-	return sql, args, nil
+	return "(" + sql + ")", args, nil
 }
 
 type exprs []expr
 
+// AppendToSql writes the expressions separated by sep to w and returns args
+// with their args appended. Each expression is built with expr.ToSql, so
+// Sqlizer args are expanded and placeholder counts are checked.
 func (es exprs) AppendToSql(w io.Writer, sep string, args []interface{}) ([]interface{}, error) {
 	for i, e := range es {
 		if i > 0 {
@@ -63,11 +144,14 @@ func (es exprs) AppendToSql(w io.Writer, sep string, args []interface{}) ([]inte
 				return nil, err
 			}
 		}
-		_, err := io.WriteString(w, e.sql)
+		sql, eArgs, err := e.ToSql()
 		if err != nil {
 			return nil, err
 		}
-		args = append(args, e.args...)
+		if _, err := io.WriteString(w, sql); err != nil {
+			return nil, err
+		}
+		args = append(args, eArgs...)
 	}
 	return args, nil
 }
@@ -87,7 +171,7 @@ func Alias(expr Sqlizer, alias string) aliasExpr {
 }
 
 func (e aliasExpr) ToSql() (sql string, args []interface{}, err error) {
-	sql, args, err = e.expr.ToSql()
+	sql, args, err = nested(e.expr).ToSql()
 	if err == nil {
 		sql = fmt.Sprintf("(%s) AS %s", sql, e.alias)
 	}
@@ -109,7 +193,7 @@ func lateralJoin(expr Sqlizer, alias string) lateralExpr {
 }
 
 func (e lateralExpr) ToSql() (sql string, args []interface{}, err error) {
-	sql, args, err = e.expr.ToSql()
+	sql, args, err = nested(e.expr).ToSql()
 	if err == nil {
 		sql = fmt.Sprintf("LATERAL (%s) AS %s", sql, e.alias)
 	}
@@ -119,56 +203,88 @@ func (e lateralExpr) ToSql() (sql string, args []interface{}, err error) {
 // Eq is syntactic sugar for use with Where/Having/Set methods.
 // Ex:
 //     .Where(Eq{"id": 1})
+//
+// Values which are Sqlizers are rendered inline with their args merged,
+// SelectBuilders in parentheses as with Subquery.
+// Ex:
+//     .Where(Eq{"updated_at": Expr("now() - interval '1 day'")})
+//     == "updated_at = now() - interval '1 day'"
 type Eq map[string]interface{}
 
-func (eq Eq) toSql(useNotOpr bool) (sql string, args []interface{}, err error) {
-	var (
-		exprs       []string
-		equalOpr    = "="
-		inOpr       = "IN"
-		nullOpr     = "IS"
-		inEmptyExpr = "(1=0)" // Portable FALSE
-	)
+type eqOprs struct {
+	equal   string
+	in      string
+	null    string
+	inEmpty string
+}
 
-	if useNotOpr {
-		equalOpr = "<>"
-		inOpr = "NOT IN"
-		nullOpr = "IS NOT"
-		inEmptyExpr = "(1=1)" // Portable TRUE
+var (
+	eqOpr = eqOprs{
+		equal:   "=",
+		in:      "IN",
+		null:    "IS",
+		inEmpty: "(1=0)", // Portable FALSE
+	}
+	notEqOpr = eqOprs{
+		equal:   "<>",
+		in:      "NOT IN",
+		null:    "IS NOT",
+		inEmpty: "(1=1)", // Portable TRUE
 	}
+)
 
-	for key, val := range eq {
-		expr := ""
+func (opr eqOprs) appendExpr(exprs []string, args []interface{}, key string, val interface{}) ([]string, []interface{}, error) {
+	val = encodeArg(val)
+	if sb, ok := val.(*SelectBuilder); ok {
+		val = Subquery(sb)
+	}
 
-		switch v := val.(type) {
-		case driver.Valuer:
-			if val, err = v.Value(); err != nil {
-				return
-			}
+	switch v := val.(type) {
+	case Sqlizer:
+		sql, subArgs, err := v.ToSql()
+		if err != nil {
+			return nil, nil, err
 		}
+		return append(exprs, fmt.Sprintf("%s %s %s", key, opr.equal, sql)), append(args, subArgs...), nil
+	case driver.Valuer:
+		var err error
+		if val, err = v.Value(); err != nil {
+			return nil, nil, err
+		}
+	}
 
-		if val == nil {
-			expr = fmt.Sprintf("%s %s NULL", key, nullOpr)
-		} else {
-			if isListType(val) {
-				valVal := reflect.ValueOf(val)
-				if valVal.Len() == 0 {
-					expr = inEmptyExpr
-					if args == nil {
-						args = []interface{}{}
-					}
-				} else {
-					for i := 0; i < valVal.Len(); i++ {
-						args = append(args, valVal.Index(i).Interface())
-					}
-					expr = fmt.Sprintf("%s %s (%s)", key, inOpr, Placeholders(valVal.Len()))
-				}
-			} else {
-				expr = fmt.Sprintf("%s %s ?", key, equalOpr)
-				args = append(args, val)
+	if val == nil {
+		return append(exprs, fmt.Sprintf("%s %s NULL", key, opr.null)), args, nil
+	}
+
+	if isListType(val) {
+		valVal := reflect.ValueOf(val)
+		if valVal.Len() == 0 {
+			if args == nil {
+				args = []interface{}{}
 			}
+			return append(exprs, opr.inEmpty), args, nil
+		}
+		for i := 0; i < valVal.Len(); i++ {
+			args = append(args, encodeArg(valVal.Index(i).Interface()))
+		}
+		return append(exprs, fmt.Sprintf("%s %s (%s)", key, opr.in, Placeholders(valVal.Len()))), args, nil
+	}
+
+	return append(exprs, fmt.Sprintf("%s %s ?", key, opr.equal)), append(args, val), nil
+}
+
+func (eq Eq) toSql(useNotOpr bool) (sql string, args []interface{}, err error) {
+	opr := eqOpr
+	if useNotOpr {
+		opr = notEqOpr
+	}
+
+	var exprs []string
+	for key, val := range eq {
+		if exprs, args, err = opr.appendExpr(exprs, args, key, val); err != nil {
+			return
 		}
-		exprs = append(exprs, expr)
 	}
 	sql = strings.Join(exprs, " AND ")
 	return
@@ -189,6 +305,106 @@ func (neq NotEq) ToSql() (sql string, args []interface{}, err error) {
 	return Eq(neq).toSql(true)
 }
 
+// EqNotZero is like Eq, but drops entries whose value is the zero value of
+// its type, e.g. nil, a nil pointer, "" or 0, which makes it handy for
+// filters built from optional request fields. An EqNotZero without
+// remaining entries adds no condition to selects; in updates and deletes it
+// matches no rows instead, so a missing optional ID can not turn into a
+// write on the whole table.
+// Ex:
+//     .Where(EqNotZero{"name": req.Name, "team_id": req.TeamID})
+type EqNotZero map[string]interface{}
+
+// ToSql builds the query into a SQL string and bound args.
+func (eq EqNotZero) ToSql() (sql string, args []interface{}, err error) {
+	return eq.filter().ToSql()
+}
+
+func (eq EqNotZero) filter() Eq {
+	filtered := make(Eq, len(eq))
+	for key, val := range eq {
+		if !isZero(val) {
+			filtered[key] = val
+		}
+	}
+	return filtered
+}
+
+// EqNotNil is like Eq, but drops entries whose value is nil or a nil
+// pointer. Unlike EqNotZero it keeps zero values like "" or 0. Without
+// remaining entries it is handled like an empty EqNotZero.
+// Ex:
+//     .Where(EqNotNil{"archived": req.Archived})
+type EqNotNil map[string]interface{}
+
+// ToSql builds the query into a SQL string and bound args.
+func (eq EqNotNil) ToSql() (sql string, args []interface{}, err error) {
+	return eq.filter().ToSql()
+}
+
+func (eq EqNotNil) filter() Eq {
+	filtered := make(Eq, len(eq))
+	for key, val := range eq {
+		if !isNil(val) {
+			filtered[key] = val
+		}
+	}
+	return filtered
+}
+
+// derefArg returns the value v points to, or nil if v is a nil pointer, so
+// optional values like *string bind as their value or NULL. Non-nil pointers
+// implementing driver.Valuer or Sqlizer are kept.
+func derefArg(v interface{}) interface{} {
+	for {
+		val := reflect.ValueOf(v)
+		if val.Kind() != reflect.Ptr {
+			return v
+		}
+		if val.IsNil() {
+			return nil
+		}
+		switch v.(type) {
+		case driver.Valuer, Sqlizer:
+			return v
+		}
+		v = val.Elem().Interface()
+	}
+}
+
+// isNil reports whether v is nil or a nil pointer.
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	val := reflect.ValueOf(v)
+	return val.Kind() == reflect.Ptr && val.IsNil()
+}
+
+// EqPair is a single column and value of an OrderedEq.
+type EqPair struct {
+	Column string
+	Value  interface{}
+}
+
+// OrderedEq is the same as Eq but keeps the order of its pairs in the
+// generated SQL and args, which Eq cannot guarantee for its map.
+// Ex:
+//     .Where(OrderedEq{{"id", 1}, {"name", "moe"}}) == "id = 1 AND name = 'moe'"
+type OrderedEq []EqPair
+
+// ToSql builds the query into a SQL string and bound args.
+func (eq OrderedEq) ToSql() (sql string, args []interface{}, err error) {
+	var exprs []string
+	for _, pair := range eq {
+		if exprs, args, err = eqOpr.appendExpr(exprs, args, pair.Column, pair.Value); err != nil {
+			return
+		}
+	}
+	sql = strings.Join(exprs, " AND ")
+	return
+}
+
 // Lt is syntactic sugar for use with Where/Having/Set methods.
 // Ex:
 //     .Where(Lt{"id": 1})
@@ -211,7 +427,20 @@ func (lt Lt) toSql(opposite, orEq bool) (sql string, args []interface{}, err err
 	for key, val := range lt {
 		expr := ""
 
+		val = encodeArg(val)
+		if sb, ok := val.(*SelectBuilder); ok {
+			val = Subquery(sb)
+		}
+
 		switch v := val.(type) {
+		case Sqlizer:
+			subSql, subArgs, subErr := v.ToSql()
+			if subErr != nil {
+				return "", nil, subErr
+			}
+			exprs = append(exprs, fmt.Sprintf("%s %s %s", key, opr, subSql))
+			args = append(args, subArgs...)
+			continue
 		case driver.Valuer:
 			if val, err = v.Value(); err != nil {
 				return
@@ -315,16 +544,6 @@ func isListType(val interface{}) bool {
 	return valVal.Kind() == reflect.Array || valVal.Kind() == reflect.Slice
 }
 
-func hasSqlizer(args []interface{}) bool {
-	for _, arg := range args {
-		_, ok := arg.(Sqlizer)
-		if ok {
-			return true
-		}
-	}
-	return false
-}
-
 // Between is syntactic sugar for use with BETWEEN methods.
 // Ex:
 //     .Where(Between{field: "id", left: 1, right: 5}) == "id between 1 and 5"
@@ -369,38 +588,115 @@ func ConcatExpr(parts ...interface{}) concatExpr {
 }
 
 type fn struct {
-	name  string
-	fargs conj
+	name     string
+	distinct bool
+	fargs    []interface{}
 }
 
-func Fn(name string, args ...Sqlizer) *fn {
+// Fn builds a call of the SQL function name. Args which are strings are
+// used verbatim, e.g. as column names, Sqlizers are expanded in place with
+// their args merged, SelectBuilders in parentheses as with Subquery, and
+// other values, including pointers to strings, are bound to placeholders.
+// To bind a string, wrap it in Expr("?", s).
+//
+// Ex:
+//     Fn("coalesce", "nickname", "name", Expr("?", "anonymous"))
+//     == "coalesce(nickname, name, ?)", "anonymous"
+//     Fn("round", "score", 2) == "round(score, ?)", 2
+func Fn(name string, args ...interface{}) *fn {
 	return &fn{name: name, fargs: args}
 }
 
+// Distinct adds DISTINCT to the call of an aggregate function.
+//
+// Ex:
+//     Fn("count", "user_id").Distinct() == "count(DISTINCT user_id)"
+func (fn *fn) Distinct() *fn {
+	fn.distinct = true
+	return fn
+}
+
+// ToSql builds the query into a SQL string and bound args.
 func (fn fn) ToSql() (sql string, args []interface{}, err error) {
-	var (
-		aSql  string
-		aArgs []interface{}
-	)
+	buf := &bytes.Buffer{}
+	buf.WriteString(fn.name)
+	buf.WriteString("(")
+	if fn.distinct {
+		buf.WriteString("DISTINCT ")
+	}
 
-	sql = fn.name + "("
 	args = make([]interface{}, 0)
-	for a := 0; a < len(fn.fargs); a++ {
-		if a > 0 {
-			sql += ", "
+	for i, farg := range fn.fargs {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		if args, err = appendOperand(buf, args, farg); err != nil {
+			return "", nil, err
 		}
+	}
+	buf.WriteString(")")
 
-		aSql, aArgs, err = fn.fargs[a].ToSql()
+	return buf.String(), args, nil
+}
+
+// appendOperand writes v as an argument of Fn or an operand of Op: strings
+// verbatim, Sqlizers expanded, SelectBuilders in parentheses and other
+// values as placeholders.
+func appendOperand(buf *bytes.Buffer, args []interface{}, v interface{}) ([]interface{}, error) {
+	if column, ok := v.(string); ok {
+		buf.WriteString(column)
+		return args, nil
+	}
+	if sb, ok := v.(*SelectBuilder); ok {
+		v = Subquery(sb)
+	}
+	switch a := encodeArg(v).(type) {
+	case Sqlizer:
+		sql, aArgs, err := a.ToSql()
 		if err != nil {
-			return
+			return nil, err
 		}
-
-		sql += aSql
-		args = append(args, aArgs...)
+		buf.WriteString(sql)
+		return append(args, aArgs...), nil
+	default:
+		buf.WriteString("?")
+		return append(args, a), nil
 	}
-	sql += ")"
+}
 
-	return
+type op struct {
+	left     interface{}
+	operator string
+	right    interface{}
+}
+
+// Op builds the binary operator expression "left operator right" for
+// operators without a dedicated helper, e.g. <-> or @@. Operands are handled
+// like the args of Fn: strings are used verbatim, e.g. as column names,
+// Sqlizers are expanded with their args merged and other values are bound
+// to placeholders.
+//
+// Ex:
+//     .Column(Alias(Op("embedding", "<->", Expr("?::vector", v)), "distance"))
+//     .Where(Op("search", "@@", Fn("plainto_tsquery", Expr("?", q))))
+//     == "search @@ plainto_tsquery(?)", q
+func Op(left interface{}, operator string, right interface{}) Sqlizer {
+	return op{left: left, operator: operator, right: right}
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (o op) ToSql() (sql string, args []interface{}, err error) {
+	buf := &bytes.Buffer{}
+	if args, err = appendOperand(buf, args, o.left); err != nil {
+		return "", nil, err
+	}
+	buf.WriteString(" ")
+	buf.WriteString(o.operator)
+	buf.WriteString(" ")
+	if args, err = appendOperand(buf, args, o.right); err != nil {
+		return "", nil, err
+	}
+	return buf.String(), args, nil
 }
 
 type any struct {
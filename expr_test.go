@@ -1,7 +1,10 @@
 package sqrl
 
 import (
+	"bytes"
+	"context"
 	"database/sql"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -91,6 +94,68 @@ func TestEqBytesToSql(t *testing.T) {
 	assert.Equal(t, expectedArgs, args)
 }
 
+func TestEqNotZeroToSql(t *testing.T) {
+	var teamID *int
+	sql, args, err := EqNotZero{"name": "", "age": 0, "team_id": teamID, "tags": []string(nil), "role": nil, "id": 1}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "id = ?", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, _, err = Select("id").From("users").Where(EqNotZero{"name": ""}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users", sql)
+}
+
+func TestEqNotNilToSql(t *testing.T) {
+	var teamID *int
+	sql, args, err := EqNotNil{"team_id": teamID, "role": nil, "age": 0}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "age = ?", sql)
+	assert.Equal(t, []interface{}{0}, args)
+}
+
+func TestEqNotZeroInWrites(t *testing.T) {
+	var id *int64
+	sql, args, err := Delete("users").Where(EqNotZero{"id": id}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users WHERE FALSE", sql)
+	assert.Empty(t, args)
+
+	sql, args, err = Update("users").Set("x", 1).Where(EqNotNil{"id": id}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET x = ? WHERE FALSE", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, args, err = Delete("users").Where(EqNotZero{"id": 0, "team_id": 2}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users WHERE team_id = ?", sql)
+	assert.Equal(t, []interface{}{2}, args)
+}
+
+func TestEqPointerToSql(t *testing.T) {
+	name := "moe"
+	var teamID *int
+	sql, args, err := OrderedEq{{"name", &name}, {"team_id", teamID}}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "name = ? AND team_id IS NULL", sql)
+	assert.Equal(t, []interface{}{"moe"}, args)
+
+	ids := []int{1, 2}
+	sql, args, err = NotEq{"id": &ids}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "id NOT IN (?,?)", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+}
+
+func TestExprPointerArgs(t *testing.T) {
+	age := 42
+	var name *string
+	sql, args, err := Expr("age > ? AND name = ?", &age, name).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "age > ? AND name = ?", sql)
+	assert.Equal(t, []interface{}{42, nil}, args)
+}
+
 func TestLtToSql(t *testing.T) {
 	b := Lt{"id": 1}
 	sql, args, err := b.ToSql()
@@ -212,3 +277,276 @@ func TestExprSqlizer(t *testing.T) {
 		assert.Equal(t, []interface{}{42, 42}, args)
 	}
 }
+
+func TestOrderedEqToSql(t *testing.T) {
+	b := OrderedEq{
+		{"z", 1},
+		{"a", "moe"},
+		{"m", []int{2, 3}},
+		{"n", nil},
+		{"e", []int{}},
+	}
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "z = ? AND a = ? AND m IN (?,?) AND n IS NULL AND (1=0)"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{1, "moe", 2, 3}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestOrderedEqEmptyToSql(t *testing.T) {
+	sql, args, err := OrderedEq{}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "", sql)
+	assert.Nil(t, args)
+}
+
+func TestExprPercent(t *testing.T) {
+	sql, args, err := Expr("name LIKE '%s%' AND id IN (?)", Select("id").From("t").Where("x LIKE ?", "%d")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "name LIKE '%s%' AND id IN (SELECT id FROM t WHERE x LIKE ?)", sql)
+	assert.Equal(t, []interface{}{"%d"}, args)
+}
+
+func TestExprNested(t *testing.T) {
+	inner := Expr("b = ? AND ?", 2, Expr("c = ?", 3))
+	sql, args, err := Expr("a = ? AND (?) AND d = ?", 1, inner, 4).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "a = ? AND (b = ? AND c = ?) AND d = ?", sql)
+	assert.Equal(t, []interface{}{1, 2, 3, 4}, args)
+
+	sql, _, err = Select("*").From("t").Where(Expr("a = ? AND (?)", 1, inner)).PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE a = $1 AND (b = $2 AND c = $3)", sql)
+}
+
+func TestExprEscapedPlaceholder(t *testing.T) {
+	sql, args, err := Expr("data ?? 'key' AND id = ? AND (?)", 1, Expr("tags ?? ?", "x")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "data ?? 'key' AND id = ? AND (tags ?? ?)", sql)
+	assert.Equal(t, []interface{}{1, "x"}, args)
+
+	sql, _, err = Select("*").From("t").Where(Expr("data ?? 'key' AND (?)", Eq{"id": 1})).PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE data ? 'key' AND (id = $1)", sql)
+}
+
+func TestExprPlaceholderMismatch(t *testing.T) {
+	_, _, err := Expr("a = ? AND b = ?", 1).ToSql()
+	assert.EqualError(t, err, `expression "a = ? AND b = ?" has more placeholders than args (1); escape literal question marks as ??`)
+
+	_, _, err = Expr("a = ?", 1, 2).ToSql()
+	assert.EqualError(t, err, `expression "a = ?" has 1 placeholders but 2 args`)
+
+	_, _, err = Expr("a = ?", Expr("?")).ToSql()
+	assert.Error(t, err)
+}
+
+func TestExprQuestionMarkOperator(t *testing.T) {
+	_, _, err := Select("*").From("posts").Where(Expr("tags ? 'foo'")).ToSql()
+	assert.EqualError(t, err, `expression "tags ? 'foo'" has more placeholders than args (0); escape literal question marks as ??`)
+
+	sql, args, err := Select("*").From("posts").
+		Where("tags ?? 'foo'").
+		Where(Expr("tags ??| ?", Array([]string{"a", "b"}))).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM posts WHERE tags ? 'foo' AND tags ?| $1", sql)
+	assert.Len(t, args, 1)
+
+	sql, _, err = Select("*").From("posts").Where(Raw("tags ? 'foo'")).PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM posts WHERE tags ? 'foo'", sql)
+}
+
+func TestRaw(t *testing.T) {
+	sql, args, err := Select("*").From("docs").
+		Where(Raw("data ? 'tags' AND note = 'why?'")).
+		Where("id = ?", 1).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM docs WHERE data ? 'tags' AND note = 'why?' AND id = $1", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, args, err = Expr("? OR a = ?", Raw("data ?| array['x']"), 2).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "data ??| array['x'] OR a = ?", sql)
+	assert.Equal(t, []interface{}{2}, args)
+}
+
+func TestRawQuestion(t *testing.T) {
+	docs := Select("id").From("docs").Where(Raw("data ? 'tags'"))
+
+	sql, _, err := docs.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM docs WHERE data ?? 'tags'", sql)
+
+	sql, _, err = Select("*").From("users").
+		Where(Expr("doc_id IN (?)", docs)).
+		Where("id = ?", 1).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE doc_id IN (SELECT id FROM docs WHERE data ? 'tags') AND id = $1", sql)
+
+	pool := newPoolStub()
+	_, err = docs.ExecContext(context.Background(), pool)
+	assert.NoError(t, err)
+	_, err = ExecWithContext(context.Background(), pool, Delete("docs").Where(Raw("data ? 'tags'")))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"SELECT id FROM docs WHERE data ? 'tags'",
+		"DELETE FROM docs WHERE data ? 'tags'",
+	}, pool.stub.sqls)
+}
+
+func TestSubquery(t *testing.T) {
+	teams := Select("id").From("teams").Where("name = ?", "a").PlaceholderFormat(Dollar)
+
+	sql, args, err := Select("*").From("users").
+		Where("active = ?", true).
+		Where(Eq{"team_id": Subquery(teams)}).
+		Where(Gt{"score": Subquery(Select("avg(score)").From("users").Where("age > ?", 18))}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE active = $1 AND team_id = (SELECT id FROM teams WHERE name = $2) "+
+		"AND score > (SELECT avg(score) FROM users WHERE age > $3)", sql)
+	assert.Equal(t, []interface{}{true, "a", 18}, args)
+
+	sql, _, err = teams.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM teams WHERE name = $1", sql)
+
+	sql, args, err = NotEq{"team_id": Subquery(teams)}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "team_id <> (SELECT id FROM teams WHERE name = ?)", sql)
+	assert.Equal(t, []interface{}{"a"}, args)
+}
+
+func TestSubqueryValues(t *testing.T) {
+	maxPos := Select("max(pos)").From("items").Where("list_id = ?", 1)
+
+	sql, args, err := Update("items").Set("pos", Subquery(maxPos)).Where("id = ?", 2).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE items SET pos = (SELECT max(pos) FROM items WHERE list_id = ?) WHERE id = ?", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	sql, args, err = Insert("items").Columns("list_id", "pos").Values(1, Subquery(maxPos)).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO items (list_id,pos) VALUES (?,(SELECT max(pos) FROM items WHERE list_id = ?))", sql)
+	assert.Equal(t, []interface{}{1, 1}, args)
+}
+
+func TestSubqueryConcurrent(t *testing.T) {
+	teams := Select("id").From("teams").Where("name = ?", "a").PlaceholderFormat(Dollar)
+	q := Select("*").From("users").Where(Eq{"team_id": Subquery(teams)}).PlaceholderFormat(Dollar)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			sql, _, err := q.ToSql()
+			assert.NoError(t, err)
+			assert.Equal(t, "SELECT * FROM users WHERE team_id = (SELECT id FROM teams WHERE name = $1)", sql)
+		}()
+		go func() {
+			defer wg.Done()
+			sql, _, err := teams.ToSql()
+			assert.NoError(t, err)
+			assert.Equal(t, "SELECT id FROM teams WHERE name = $1", sql)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSubqueryErr(t *testing.T) {
+	_, _, err := Eq{"id": Subquery(Select().From("t"))}.ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Lt{"id": Subquery(Select().From("t"))}.ToSql()
+	assert.Error(t, err)
+}
+
+func TestEqSqlizerValues(t *testing.T) {
+	sql, args, err := Select("id").From("users").
+		Where(Eq{"updated_at": Expr("now() - interval '1 day'")}).
+		Where(NotEq{"team_id": Select("id").From("teams").Where("name = ?", "a").PlaceholderFormat(Dollar)}).
+		Where(LtOrEq{"score": Expr("? * 2", 10)}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE updated_at = now() - interval '1 day' "+
+		"AND team_id <> (SELECT id FROM teams WHERE name = $1) AND score <= $2 * 2", sql)
+	assert.Equal(t, []interface{}{"a", 10}, args)
+
+	_, _, err = Eq{"id": Select().From("t")}.ToSql()
+	assert.Error(t, err)
+}
+
+func TestFn(t *testing.T) {
+	nick := "mo"
+	sql, args, err := Select().
+		Column(Fn("coalesce", "nickname", &nick, Expr("?", "anonymous"))).
+		Column(Fn("round", Fn("avg", "score"), 2)).
+		Column(Fn("count", "user_id").Distinct()).
+		Column(Fn("greatest", Select("max(id)").From("posts"), 0)).
+		From("users").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT coalesce(nickname, $1, $2), round(avg(score), $3), count(DISTINCT user_id), "+
+		"greatest((SELECT max(id) FROM posts), $4) FROM users", sql)
+	assert.Equal(t, []interface{}{"mo", "anonymous", 2, 0}, args)
+	assert.Equal(t, []string{"users", "posts"}, Tables(Select().Column(Fn("greatest", Select("max(id)").From("posts"))).From("users")))
+
+	sql, args, err = Fn("now").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "now()", sql)
+	assert.Empty(t, args)
+}
+
+func TestOp(t *testing.T) {
+	sql, args, err := Select("id").
+		Column(Alias(Op("embedding", "<->", Expr("?::vector", "[1,2]")), "distance")).
+		From("items").
+		Where(Op("search", "@@", Fn("plainto_tsquery", Expr("?", "moe")))).
+		Where(Op(Expr("tags"), "&&", Array([]string{"a"}))).
+		Where(Op(Select("count(*)").From("tags"), ">", 2)).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, (embedding <-> $1::vector) AS distance FROM items "+
+		"WHERE search @@ plainto_tsquery($2) AND tags && $3 AND (SELECT count(*) FROM tags) > $4", sql)
+	assert.Equal(t, []interface{}{"[1,2]", "moe", `{"a"}`, 2}, args)
+
+	_, _, err = Op("a", "=", Expr("?")).ToSql()
+	assert.Error(t, err)
+}
+
+func TestExprsAppendToSql(t *testing.T) {
+	es := exprs{
+		Expr("WITH a AS (?)", Select("id").From("users").Where("active = ?", true)),
+		Expr("b AS (SELECT ?::int)", 2),
+		Expr("c AS (SELECT '{}'::jsonb ?? 'k')"),
+	}
+	sql := &bytes.Buffer{}
+	args, err := es.AppendToSql(sql, ", ", []interface{}{0})
+	assert.NoError(t, err)
+	assert.Equal(t, "WITH a AS (SELECT id FROM users WHERE active = ?), b AS (SELECT ?::int), c AS (SELECT '{}'::jsonb ?? 'k')", sql.String())
+	assert.Equal(t, []interface{}{0, true, 2}, args)
+
+	_, err = exprs{Expr("LIMIT ?")}.AppendToSql(&bytes.Buffer{}, " ", nil)
+	assert.EqualError(t, err, `expression "LIMIT ?" has more placeholders than args (0); escape literal question marks as ??`)
+
+	_, err = exprs{Expr("LIMIT 1", 2)}.AppendToSql(&bytes.Buffer{}, " ", nil)
+	assert.Error(t, err)
+
+	_, _, err = CreateTable("t").Column("id", "int").Suffix("WITH (fillfactor = ?)").ToSql()
+	assert.Error(t, err)
+}
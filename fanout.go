@@ -0,0 +1,135 @@
+package sqrl
+
+import (
+	"context"
+	"errors"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgx/v4"
+	"sort"
+	"sync"
+)
+
+// Partition is one target of FanOut.
+type Partition struct {
+	// Table, if set, replaces the FROM clause of the query.
+	Table string
+	// Where, if set, is added to the WHERE clause of the query.
+	Where Sqlizer
+}
+
+// FanOutOptions configures how FanOut runs and merges the partition queries.
+type FanOutOptions[T interface{}] struct {
+	// Concurrency limits the number of queries running at once. Zero runs
+	// all partitions at once.
+	Concurrency int
+	// Less, if set, sorts the merged rows. Otherwise rows are returned in
+	// partition order.
+	Less func(a, b T) bool
+	// Limit, if greater than zero, limits the number of merged rows. Set it
+	// together with Less and a LIMIT on the query for top-N queries.
+	Limit int
+}
+
+// FanOut runs sb once per partition concurrently and merges the rows
+// scanned by scan, for scatter-gather queries over partitioned tables. The
+// first error cancels all outstanding queries.
+//
+// Ex:
+//     events, err := sqrl.FanOut(ctx, pool,
+//         sqrl.Select("id", "created_at").From("events").OrderBy("created_at DESC").Limit(10),
+//         []sqrl.Partition{{Table: "events_0"}, {Table: "events_1"}},
+//         scanEvent,
+//         sqrl.FanOutOptions[Event]{Less: newerEvent, Limit: 10})
+func FanOut[T interface{}](ctx context.Context, pool instapgxpool.Pool, sb *SelectBuilder, partitions []Partition, scan func(rows pgx.Rows) (T, error), opts FanOutOptions[T]) ([]T, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]T, len(partitions))
+	errs := make([]error, len(partitions))
+
+	var sem chan struct{}
+	if opts.Concurrency > 0 {
+		sem = make(chan struct{}, opts.Concurrency)
+	}
+
+	var wg sync.WaitGroup
+	for i, p := range partitions {
+		q := partitionQuery(sb, p)
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if sem != nil {
+				select {
+				case sem <- struct{}{}:
+					defer func() { <-sem }()
+				case <-ctx.Done():
+					errs[i] = ctx.Err()
+					return
+				}
+			}
+
+			results[i], errs[i] = fanOutQuery(ctx, pool, q, scan)
+			if errs[i] != nil {
+				cancel()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, context.Canceled) {
+			return nil, err
+		}
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var merged []T
+	for _, rows := range results {
+		merged = append(merged, rows...)
+	}
+	if opts.Less != nil {
+		sort.SliceStable(merged, func(i, j int) bool {
+			return opts.Less(merged[i], merged[j])
+		})
+	}
+	if opts.Limit > 0 && len(merged) > opts.Limit {
+		merged = merged[:opts.Limit]
+	}
+	return merged, nil
+}
+
+// partitionQuery returns a copy of sb targeting p. The where parts are
+// copied, so the queries do not share their backing array.
+func partitionQuery(sb *SelectBuilder, p Partition) *SelectBuilder {
+	q := sb.Clone()
+	if len(p.Table) > 0 {
+		q.fromParts = []Sqlizer{newPart(p.Table)}
+	}
+	if p.Where != nil {
+		q.whereParts = append(q.whereParts[:len(q.whereParts):len(q.whereParts)], newWherePart(p.Where))
+	}
+	return q
+}
+
+func fanOutQuery[T interface{}](ctx context.Context, pool instapgxpool.Pool, q *SelectBuilder, scan func(rows pgx.Rows) (T, error)) ([]T, error) {
+	rows, err := QueryWithContext(ctx, pool, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []T
+	for rows.Next() {
+		value, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
@@ -0,0 +1,67 @@
+package sqrl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func scanInt(rows pgx.Rows) (int, error) {
+	var v int
+	err := rows.Scan(&v)
+	return v, err
+}
+
+func TestFanOut(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.bySql = map[string][][]interface{}{
+		"SELECT v FROM events_0 ORDER BY v DESC LIMIT 2": {{5}, {1}},
+		"SELECT v FROM events_1 ORDER BY v DESC LIMIT 2": {{7}, {3}},
+		"SELECT v FROM events_2 ORDER BY v DESC LIMIT 2": {{4}},
+	}
+
+	sb := Select("v").From("events").OrderBy("v DESC").Limit(2)
+	partitions := []Partition{{Table: "events_0"}, {Table: "events_1"}, {Table: "events_2"}}
+
+	values, err := FanOut(context.Background(), pool, sb, partitions, scanInt, FanOutOptions[int]{})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{5, 1, 7, 3, 4}, values)
+
+	values, err = FanOut(context.Background(), pool, sb, partitions, scanInt, FanOutOptions[int]{
+		Concurrency: 2,
+		Less:        func(a, b int) bool { return a > b },
+		Limit:       2,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{7, 5}, values)
+}
+
+func TestFanOutWhere(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.bySql = map[string][][]interface{}{
+		"SELECT v FROM events WHERE v > ? AND bucket = ?": {{1}},
+	}
+
+	sb := Select("v").From("events").Where("v > ?", 0)
+	partitions := []Partition{{Where: Eq{"bucket": 0}}, {Where: Eq{"bucket": 1}}}
+
+	values, err := FanOut(context.Background(), pool, sb, partitions, scanInt, FanOutOptions[int]{})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 1}, values)
+	assert.ElementsMatch(t, [][]interface{}{{0, 0}, {0, 1}}, pool.stub.args)
+
+	sql, _, _ := sb.ToSql()
+	assert.Equal(t, "SELECT v FROM events WHERE v > ?", sql)
+}
+
+func TestFanOutError(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.err = errors.New("connection refused")
+
+	_, err := FanOut(context.Background(), pool, Select("v").From("events"),
+		[]Partition{{Table: "events_0"}, {Table: "events_1"}}, scanInt, FanOutOptions[int]{})
+	assert.True(t, errors.Is(err, pool.stub.err))
+}
@@ -0,0 +1,97 @@
+package filters
+
+import (
+	"fmt"
+	"github.com/clevabit/sqrl"
+	"sort"
+	"strings"
+)
+
+// MaskField describes a field that can be requested in a field mask.
+type MaskField struct {
+	// Columns are the columns fetched for the field. If empty, the field
+	// name is used.
+	Columns []string
+}
+
+func (f MaskField) columns(name string) []string {
+	if len(f.Columns) == 0 {
+		return []string{name}
+	}
+	return f.Columns
+}
+
+// MaskAllowlist maps field names, as used in field masks, to their
+// definition.
+type MaskAllowlist map[string]MaskField
+
+// ParseFieldMask splits a comma separated field mask, as used in partial
+// response query parameters like ?fields=id,name, into its paths.
+func ParseFieldMask(spec string) []string {
+	var paths []string
+	for _, path := range strings.Split(spec, ",") {
+		if path = strings.TrimSpace(path); len(path) > 0 {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// MaskColumns maps the paths of a field mask, e.g. the paths of a protobuf
+// FieldMask, to the columns needed to fetch them. Columns are returned once,
+// in the order they are first requested.
+//
+// A nested path like "address.city" which is not part of allow is mapped
+// through its closest allowed parent, "address". Other paths which are not
+// part of allow are rejected, so only trusted column names end up in the
+// query. An empty mask selects all fields of allow.
+func MaskColumns(paths []string, allow MaskAllowlist) ([]string, error) {
+	if len(paths) == 0 {
+		paths = make([]string, 0, len(allow))
+		for name := range allow {
+			paths = append(paths, name)
+		}
+		sort.Strings(paths)
+	}
+
+	var columns []string
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		name := path
+		field, ok := allow[name]
+		for !ok {
+			i := strings.LastIndexByte(name, '.')
+			if i < 0 {
+				return nil, fmt.Errorf("field %q is not allowed", path)
+			}
+			name = name[:i]
+			field, ok = allow[name]
+		}
+
+		for _, column := range field.columns(name) {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}
+	}
+	return columns, nil
+}
+
+// Project adds the columns needed for the paths of a field mask to b, see
+// MaskColumns.
+//
+// Ex:
+//     b := sqrl.Select("id").From("users")
+//     err := filters.Project(b, filters.ParseFieldMask(r.URL.Query().Get("fields")), filters.MaskAllowlist{
+//         "name":    {},
+//         "address": {Columns: []string{"street", "city"}},
+//     })
+func Project(b *sqrl.SelectBuilder, paths []string, allow MaskAllowlist) error {
+	columns, err := MaskColumns(paths, allow)
+	if err != nil {
+		return err
+	}
+	b.Columns(columns...)
+	return nil
+}
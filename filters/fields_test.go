@@ -0,0 +1,47 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/clevabit/sqrl"
+	"github.com/stretchr/testify/assert"
+)
+
+var maskAllow = MaskAllowlist{
+	"name":    {Columns: []string{"users.name"}},
+	"email":   {},
+	"address": {Columns: []string{"street", "city"}},
+	"city":    {Columns: []string{"city"}},
+}
+
+func TestParseFieldMask(t *testing.T) {
+	assert.Equal(t, []string{"name", "address.city"}, ParseFieldMask(" name,,address.city "))
+	assert.Nil(t, ParseFieldMask(""))
+}
+
+func TestMaskColumns(t *testing.T) {
+	columns, err := MaskColumns([]string{"email", "address.city", "city", "name"}, maskAllow)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"email", "street", "city", "users.name"}, columns)
+
+	columns, err = MaskColumns(nil, maskAllow)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"street", "city", "email", "users.name"}, columns)
+}
+
+func TestMaskColumnsNotAllowed(t *testing.T) {
+	_, err := MaskColumns([]string{"name", "password"}, maskAllow)
+	assert.EqualError(t, err, `field "password" is not allowed`)
+
+	_, err = MaskColumns([]string{"password.hash"}, maskAllow)
+	assert.EqualError(t, err, `field "password.hash" is not allowed`)
+}
+
+func TestProject(t *testing.T) {
+	b := sqrl.Select("id").From("users")
+	assert.NoError(t, Project(b, ParseFieldMask("name,address"), maskAllow))
+
+	sql, _, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, users.name, street, city FROM users", sql)
+}
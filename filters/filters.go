@@ -0,0 +1,199 @@
+// Package filters turns HTTP query parameters into sqrl predicates.
+//
+// Parameters take the form field__op=value, e.g.
+//     age__gte=18&status__in=a,b&name__ilike=%x%
+// and are checked against a per-endpoint Allowlist before being turned into
+// a sqrl.Sqlizer suitable for SelectBuilder.Where.
+package filters
+
+import (
+	"fmt"
+	"github.com/clevabit/sqrl"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Op is a filter operator, given as the suffix of a parameter name.
+type Op string
+
+// Supported operators. A parameter without an operator suffix uses OpEq.
+const (
+	OpEq     Op = "eq"
+	OpNotEq  Op = "ne"
+	OpLt     Op = "lt"
+	OpLtOrEq Op = "lte"
+	OpGt     Op = "gt"
+	OpGtOrEq Op = "gte"
+	OpIn     Op = "in"
+	OpNotIn  Op = "nin"
+	OpLike   Op = "like"
+	OpILike  Op = "ilike"
+	OpIsNull Op = "isnull"
+)
+
+// Separator splits the field name from the operator in a parameter name.
+const Separator = "__"
+
+// Type is the type parameter values are coerced to.
+type Type int
+
+const (
+	String Type = iota
+	Int
+	Float
+	Bool
+	Time
+)
+
+// Field describes a filterable field.
+type Field struct {
+	// Column is the column the field is matched against. If empty, the
+	// field name is used.
+	Column string
+	// Type is the type values are coerced to.
+	Type Type
+	// Ops are the operators allowed for the field.
+	Ops []Op
+}
+
+func (f Field) allows(op Op) bool {
+	for _, o := range f.Ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// Allowlist maps field names, as used in query parameters, to their
+// definition.
+type Allowlist map[string]Field
+
+// Parse builds a predicate from the query parameters in values.
+//
+// Parameters whose field is not part of allow are ignored, so filters can
+// share the query string with other parameters such as paging. Using an
+// operator that is not allowed for a field or passing a value that cannot
+// be coerced to the field's type is an error. Multiple filters are combined
+// with AND; Parse returns nil if values contain no filters.
+//
+// Ex:
+//     pred, err := filters.Parse(r.URL.Query(), filters.Allowlist{
+//         "age":    {Type: filters.Int, Ops: []filters.Op{filters.OpGtOrEq, filters.OpLt}},
+//         "status": {Type: filters.String, Ops: []filters.Op{filters.OpEq, filters.OpIn}},
+//     })
+func Parse(values url.Values, allow Allowlist) (sqrl.Sqlizer, error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var preds sqrl.And
+	for _, key := range keys {
+		name, op := key, OpEq
+		if i := strings.LastIndex(key, Separator); i >= 0 {
+			name, op = key[:i], Op(key[i+len(Separator):])
+		}
+
+		field, ok := allow[name]
+		if !ok {
+			continue
+		}
+		if !field.allows(op) {
+			return nil, fmt.Errorf("operator %q is not allowed for filter %q", op, name)
+		}
+
+		column := field.Column
+		if len(column) == 0 {
+			column = name
+		}
+
+		for _, value := range values[key] {
+			pred, err := predicate(column, field.Type, op, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for filter %q: %v", key, err)
+			}
+			preds = append(preds, pred)
+		}
+	}
+
+	if len(preds) == 0 {
+		return nil, nil
+	}
+	return preds, nil
+}
+
+func predicate(column string, typ Type, op Op, value string) (sqrl.Sqlizer, error) {
+	switch op {
+	case OpIn, OpNotIn:
+		parts := strings.Split(value, ",")
+		list := make([]interface{}, len(parts))
+		for i, part := range parts {
+			v, err := coerce(typ, part)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = v
+		}
+		if op == OpIn {
+			return sqrl.Eq{column: list}, nil
+		}
+		return sqrl.NotEq{column: list}, nil
+
+	case OpIsNull:
+		isNull, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, err
+		}
+		if isNull {
+			return sqrl.Eq{column: nil}, nil
+		}
+		return sqrl.NotEq{column: nil}, nil
+
+	case OpLike, OpILike:
+		if typ != String {
+			return nil, fmt.Errorf("%s requires a string field", op)
+		}
+		return sqrl.Expr(fmt.Sprintf("%s %s ?", column, strings.ToUpper(string(op))), value), nil
+	}
+
+	v, err := coerce(typ, value)
+	if err != nil {
+		return nil, err
+	}
+	switch op {
+	case OpEq:
+		return sqrl.Eq{column: v}, nil
+	case OpNotEq:
+		return sqrl.NotEq{column: v}, nil
+	case OpLt:
+		return sqrl.Lt{column: v}, nil
+	case OpLtOrEq:
+		return sqrl.LtOrEq{column: v}, nil
+	case OpGt:
+		return sqrl.Gt{column: v}, nil
+	case OpGtOrEq:
+		return sqrl.GtOrEq{column: v}, nil
+	}
+	return nil, fmt.Errorf("unknown operator %q", op)
+}
+
+func coerce(typ Type, value string) (interface{}, error) {
+	switch typ {
+	case String:
+		return value, nil
+	case Int:
+		return strconv.ParseInt(value, 10, 64)
+	case Float:
+		return strconv.ParseFloat(value, 64)
+	case Bool:
+		return strconv.ParseBool(value)
+	case Time:
+		return time.Parse(time.RFC3339, value)
+	}
+	return nil, fmt.Errorf("unknown type %d", typ)
+}
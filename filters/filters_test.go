@@ -0,0 +1,88 @@
+package filters
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/clevabit/sqrl"
+	"github.com/stretchr/testify/assert"
+)
+
+var allow = Allowlist{
+	"age":     {Type: Int, Ops: []Op{OpEq, OpGtOrEq, OpLt}},
+	"status":  {Type: String, Ops: []Op{OpEq, OpIn, OpNotIn}},
+	"name":    {Column: "users.name", Type: String, Ops: []Op{OpILike, OpLike}},
+	"deleted": {Column: "deleted_at", Type: Time, Ops: []Op{OpIsNull, OpGt}},
+	"score":   {Type: Float, Ops: []Op{OpNotEq}},
+	"admin":   {Type: Bool, Ops: []Op{OpEq}},
+}
+
+func parse(t *testing.T, query string) (string, []interface{}, error) {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pred, err := Parse(values, allow)
+	if err != nil {
+		return "", nil, err
+	}
+	return sqrl.Select("*").From("users").Where(pred).ToSql()
+}
+
+func TestParse(t *testing.T) {
+	sql, args, err := parse(t, "age__gte=18&status__in=a,b&name__ilike=%25x%25&page=2")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE (age >= ? AND users.name ILIKE ? AND status IN (?,?))", sql)
+	assert.Equal(t, []interface{}{int64(18), "%x%", "a", "b"}, args)
+}
+
+func TestParseDefaultOp(t *testing.T) {
+	sql, args, err := parse(t, "admin=true&score__ne=1.5")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE (admin = ? AND score <> ?)", sql)
+	assert.Equal(t, []interface{}{true, 1.5}, args)
+}
+
+func TestParseRepeated(t *testing.T) {
+	sql, args, err := parse(t, "age__gte=18&age__lt=65&status__nin=x")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE (age >= ? AND age < ? AND status NOT IN (?))", sql)
+	assert.Equal(t, []interface{}{int64(18), int64(65), "x"}, args)
+}
+
+func TestParseIsNull(t *testing.T) {
+	sql, _, err := parse(t, "deleted__isnull=true")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE (deleted_at IS NULL)", sql)
+
+	sql, _, err = parse(t, "deleted__isnull=false")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE (deleted_at IS NOT NULL)", sql)
+}
+
+func TestParseTime(t *testing.T) {
+	_, args, err := parse(t, "deleted__gt=2020-05-01T10:00:00Z")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)}, args)
+}
+
+func TestParseNoFilters(t *testing.T) {
+	pred, err := Parse(url.Values{"page": {"1"}}, allow)
+	assert.NoError(t, err)
+	assert.Nil(t, pred)
+}
+
+func TestParseErrors(t *testing.T) {
+	_, _, err := parse(t, "age__ilike=1")
+	assert.EqualError(t, err, `operator "ilike" is not allowed for filter "age"`)
+
+	_, _, err = parse(t, "age__gte=old")
+	assert.Error(t, err)
+
+	_, _, err = parse(t, "status__gt=a")
+	assert.Error(t, err)
+
+	_, _, err = parse(t, "deleted__isnull=maybe")
+	assert.Error(t, err)
+}
@@ -0,0 +1,54 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/clevabit/sqrl"
+	"github.com/stretchr/testify/assert"
+)
+
+var sortAllow = SortAllowlist{
+	"name":       {Column: "users.name"},
+	"created_at": {},
+	"rank":       {Nulls: NullsLast},
+}
+
+func TestParseSort(t *testing.T) {
+	orderBys, err := ParseSort("name,-created_at", sortAllow)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"users.name ASC", "created_at DESC"}, orderBys)
+
+	sql, _, err := sqrl.Select("*").From("users").OrderBy(orderBys...).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users ORDER BY users.name ASC, created_at DESC", sql)
+}
+
+func TestParseSortNulls(t *testing.T) {
+	orderBys, err := ParseSort("+rank, -created_at:nullsfirst", sortAllow)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rank ASC NULLS LAST", "created_at DESC NULLS FIRST"}, orderBys)
+
+	orderBys, err = ParseSort("-rank:NullsFirst", sortAllow)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rank DESC NULLS FIRST"}, orderBys)
+}
+
+func TestParseSortEmpty(t *testing.T) {
+	orderBys, err := ParseSort(" ", sortAllow)
+	assert.NoError(t, err)
+	assert.Nil(t, orderBys)
+}
+
+func TestParseSortErrors(t *testing.T) {
+	_, err := ParseSort("name;DROP TABLE users", sortAllow)
+	assert.EqualError(t, err, `sorting by "name;DROP TABLE users" is not allowed`)
+
+	_, err = ParseSort("name,-name", sortAllow)
+	assert.EqualError(t, err, `duplicate sort field "name"`)
+
+	_, err = ParseSort("rank:sideways", sortAllow)
+	assert.Error(t, err)
+
+	_, err = ParseSort("name,", sortAllow)
+	assert.Error(t, err)
+}
@@ -0,0 +1,147 @@
+package fixtures
+
+import (
+	"context"
+	"fmt"
+	"github.com/clevabit/sqrl"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgx/v4"
+	"reflect"
+	"sync/atomic"
+)
+
+// Factory creates rows of a table from a template struct of type T. Columns
+// are taken from the db tags of the struct fields as with Set.AddStructs.
+//
+// Ex:
+//     users := NewFactory("users", User{Role: "member"}).
+//         Generate("email", func(seq int) interface{} {
+//             return fmt.Sprintf("user%d@example.com", seq)
+//         })
+//
+//     admins, err := users.Create(ctx, pool, 2, Row{"role": "admin"})
+type Factory[T interface{}] struct {
+	table      string
+	template   T
+	generators map[string]func(seq int) interface{}
+	seq        int64
+}
+
+// NewFactory creates a factory for rows of table based on template, which
+// must be a struct.
+func NewFactory[T interface{}](table string, template T) *Factory[T] {
+	return &Factory[T]{
+		table:      table,
+		template:   template,
+		generators: make(map[string]func(seq int) interface{}),
+	}
+}
+
+// Generate sets a generator for the value of column. It is called with a
+// sequence number unique to the factory, starting at 1, for every row.
+func (f *Factory[T]) Generate(column string, gen func(seq int) interface{}) *Factory[T] {
+	f.generators[column] = gen
+	return f
+}
+
+func (f *Factory[T]) fields() ([]sqrl.StructField, error) {
+	typ := reflect.TypeOf(f.template)
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("factory template for table %s must be a struct, got %T", f.table, f.template)
+	}
+	return sqrl.StructFields(typ), nil
+}
+
+// Rows returns n rows built from the template, the generators and the
+// overrides, which take precedence in this order.
+func (f *Factory[T]) Rows(n int, overrides Row) ([]Row, error) {
+	if _, err := f.fields(); err != nil {
+		return nil, err
+	}
+
+	rows := make([]Row, n)
+	for i := range rows {
+		seq := int(atomic.AddInt64(&f.seq, 1))
+		row := structRow(reflect.ValueOf(f.template))
+		for column, gen := range f.generators {
+			row[column] = gen(seq)
+		}
+		for column, value := range overrides {
+			row[column] = value
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
+
+// Insert builds a statement inserting n rows and returning all columns of
+// T.
+func (f *Factory[T]) Insert(n int, overrides Row) (*sqrl.InsertBuilder, error) {
+	fields, err := f.fields()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := f.Rows(n, overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	returning := make([]string, len(fields))
+	for i, field := range fields {
+		returning[i] = field.Column
+	}
+	return insertRows(f.table, rows).Returning(returning...), nil
+}
+
+// Create inserts n rows and returns them as populated by the database,
+// including defaults and generated columns.
+func (f *Factory[T]) Create(ctx context.Context, pool instapgxpool.Pool, n int, overrides Row) ([]T, error) {
+	if n < 1 {
+		return nil, nil
+	}
+
+	b, err := f.Insert(n, overrides)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := b.QueryContext(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+	return f.scan(rows)
+}
+
+// CreateOne inserts a single row and returns it as populated by the
+// database.
+func (f *Factory[T]) CreateOne(ctx context.Context, pool instapgxpool.Pool, overrides Row) (T, error) {
+	values, err := f.Create(ctx, pool, 1, overrides)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return values[0], nil
+}
+
+func (f *Factory[T]) scan(rows pgx.Rows) ([]T, error) {
+	defer rows.Close()
+
+	fields, err := f.fields()
+	if err != nil {
+		return nil, err
+	}
+
+	var values []T
+	for rows.Next() {
+		var value T
+		v := reflect.ValueOf(&value).Elem()
+		dest := make([]interface{}, len(fields))
+		for i, field := range fields {
+			dest[i] = v.Field(field.Index).Addr().Interface()
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
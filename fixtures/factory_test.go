@@ -0,0 +1,91 @@
+package fixtures
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type rowsStub struct {
+	values [][]interface{}
+	pos    int
+	closed bool
+}
+
+func (r *rowsStub) Close()                                         { r.closed = true }
+func (r *rowsStub) Err() error                                     { return nil }
+func (r *rowsStub) CommandTag() pgconn.CommandTag                  { return nil }
+func (r *rowsStub) FieldDescriptions() []pgproto3.FieldDescription { return nil }
+func (r *rowsStub) RawValues() [][]byte                            { return nil }
+
+func (r *rowsStub) Next() bool {
+	if r.closed || r.pos >= len(r.values) {
+		return false
+	}
+	r.pos++
+	return true
+}
+
+func (r *rowsStub) Values() ([]interface{}, error) {
+	return r.values[r.pos-1], nil
+}
+
+func (r *rowsStub) Scan(dest ...interface{}) error {
+	for i, d := range dest {
+		reflect.ValueOf(d).Elem().Set(reflect.ValueOf(r.values[r.pos-1][i]))
+	}
+	return nil
+}
+
+type factoryUser struct {
+	ID    int64  `db:"id,omitempty"`
+	Email string `db:"email"`
+	Role  string `db:"role"`
+}
+
+func newUserFactory() *Factory[factoryUser] {
+	return NewFactory("users", factoryUser{Role: "member"}).
+		Generate("email", func(seq int) interface{} {
+			return fmt.Sprintf("user%d@example.com", seq)
+		})
+}
+
+func TestFactoryInsert(t *testing.T) {
+	f := newUserFactory()
+
+	b, err := f.Insert(2, Row{"role": "admin"})
+	assert.NoError(t, err)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (email,role) VALUES ($1,$2),($3,$4) RETURNING id, email, role", sql)
+	assert.Equal(t, []interface{}{"user1@example.com", "admin", "user2@example.com", "admin"}, args)
+
+	rows, err := f.Rows(1, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []Row{{"email": "user3@example.com", "role": "member"}}, rows)
+}
+
+func TestFactoryScan(t *testing.T) {
+	rows := &rowsStub{values: [][]interface{}{
+		{int64(1), "user1@example.com", "member"},
+		{int64(2), "user2@example.com", "member"},
+	}}
+
+	users, err := newUserFactory().scan(rows)
+	assert.NoError(t, err)
+	assert.Equal(t, []factoryUser{
+		{ID: 1, Email: "user1@example.com", Role: "member"},
+		{ID: 2, Email: "user2@example.com", Role: "member"},
+	}, users)
+	assert.True(t, rows.closed)
+}
+
+func TestFactoryInvalidTemplate(t *testing.T) {
+	_, err := NewFactory("users", 1).Insert(1, nil)
+	assert.EqualError(t, err, "factory template for table users must be a struct, got int")
+}
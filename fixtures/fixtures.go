@@ -0,0 +1,376 @@
+// Package fixtures loads fixture rows from YAML or JSON files or Go structs
+// and inserts them with sqrl, e.g. to set up integration tests.
+//
+// A fixture file maps table names either to a list of rows or to an object
+// listing the tables it references by foreign key and its rows:
+//     users:
+//       - id: 1
+//         name: alice
+//     posts:
+//       depends_on: [users]
+//       rows:
+//         - id: 1
+//           user_id: 1
+//
+// Tables are inserted after the tables they depend on, in chunks of
+// multi-row INSERT statements.
+package fixtures
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/clevabit/sqrl"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DefaultChunkSize is the number of rows inserted per statement unless a Set
+// specifies otherwise.
+const DefaultChunkSize = 500
+
+// maxArgs is the limit Postgres puts on the number of bound parameters of a
+// statement.
+const maxArgs = 65535
+
+// Row is a fixture row, mapping column names to values. Columns missing
+// from a row are inserted as DEFAULT.
+type Row map[string]interface{}
+
+type table struct {
+	rows []Row
+	deps []string
+}
+
+// Set is a set of fixture rows for one or more tables.
+type Set struct {
+	// ChunkSize is the maximum number of rows per INSERT statement. It
+	// defaults to DefaultChunkSize and is lowered as needed to keep the
+	// number of args of a statement within the limits of Postgres.
+	ChunkSize int
+
+	tables map[string]*table
+	names  []string
+}
+
+// NewSet creates an empty fixture set.
+func NewSet() *Set {
+	return &Set{tables: make(map[string]*table)}
+}
+
+func (s *Set) table(name string) *table {
+	t, ok := s.tables[name]
+	if !ok {
+		t = &table{}
+		s.tables[name] = t
+		s.names = append(s.names, name)
+	}
+	return t
+}
+
+// Add adds rows to be inserted into the named table.
+func (s *Set) Add(table string, rows ...Row) *Set {
+	t := s.table(table)
+	t.rows = append(t.rows, rows...)
+	return s
+}
+
+// DependsOn declares that table references deps by foreign key, so its rows
+// are inserted after theirs. Dependencies on tables without fixtures are
+// ignored.
+func (s *Set) DependsOn(table string, deps ...string) *Set {
+	t := s.table(table)
+	t.deps = append(t.deps, deps...)
+	return s
+}
+
+// AddStructs adds a slice of structs as rows of the named table. Columns are
+// taken from the db tags of the struct fields; fields without a db tag or
+// tagged "-" are skipped and zero fields tagged omitempty are inserted as
+// DEFAULT.
+//
+// Ex:
+//     type User struct {
+//         ID   int64  `db:"id,omitempty"`
+//         Name string `db:"name"`
+//     }
+//
+//     err := set.AddStructs("users", []User{{Name: "alice"}})
+func (s *Set) AddStructs(table string, structs interface{}) error {
+	v := reflect.ValueOf(structs)
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("fixtures for table %s must be a slice of structs, got %T", table, structs)
+	}
+
+	rows := make([]Row, v.Len())
+	for i := range rows {
+		elem := reflect.Indirect(v.Index(i))
+		if elem.Kind() != reflect.Struct {
+			return fmt.Errorf("fixtures for table %s must be a slice of structs, got %T", table, structs)
+		}
+		rows[i] = structRow(elem)
+	}
+	s.Add(table, rows...)
+	return nil
+}
+
+func structRow(v reflect.Value) Row {
+	row := Row{}
+	for _, field := range sqrl.StructFields(v.Type()) {
+		value := v.Field(field.Index)
+		if value.IsZero() && field.OmitEmpty {
+			continue
+		}
+		row[field.Column] = value.Interface()
+	}
+	return row
+}
+
+// LoadFiles reads fixture files into the set. Files ending in .yaml or .yml
+// are read as YAML, all others as JSON.
+func (s *Set) LoadFiles(paths ...string) error {
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		unmarshal := unmarshalJSON
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml":
+			unmarshal = yaml.Unmarshal
+		}
+
+		if err := s.Decode(data, unmarshal); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// Decode reads fixtures from data using unmarshal, e.g. json.Unmarshal or
+// yaml.Unmarshal, into the set.
+func (s *Set) Decode(data []byte, unmarshal func([]byte, interface{}) error) error {
+	var doc interface{}
+	if err := unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	tables, ok := normalize(doc).(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("fixtures must map table names to rows")
+	}
+
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		rows := tables[name]
+		if obj, ok := rows.(map[string]interface{}); ok {
+			deps, err := toStrings(obj["depends_on"])
+			if err != nil {
+				return fmt.Errorf("table %s: %v", name, err)
+			}
+			s.DependsOn(name, deps...)
+			rows = obj["rows"]
+		}
+
+		list, ok := rows.([]interface{})
+		if !ok && rows != nil {
+			return fmt.Errorf("table %s: rows must be a list, got %T", name, rows)
+		}
+		t := s.table(name)
+		for i, row := range list {
+			values, ok := row.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("table %s: row %d must map columns to values, got %T", name, i, row)
+			}
+			t.rows = append(t.rows, Row(values))
+		}
+	}
+	return nil
+}
+
+func unmarshalJSON(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// normalize converts the maps decoded by yaml.v2 to string keyed maps and
+// JSON numbers to int64 or float64.
+func normalize(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, e := range val {
+			m[fmt.Sprint(k)] = normalize(e)
+		}
+		return m
+	case map[string]interface{}:
+		for k, e := range val {
+			val[k] = normalize(e)
+		}
+		return val
+	case []interface{}:
+		for i, e := range val {
+			val[i] = normalize(e)
+		}
+		return val
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		f, _ := val.Float64()
+		return f
+	default:
+		return v
+	}
+}
+
+func toStrings(v interface{}) ([]string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("depends_on must be a list of table names, got %T", v)
+	}
+	strs := make([]string, len(list))
+	for i, e := range list {
+		s, ok := e.(string)
+		if !ok {
+			return nil, fmt.Errorf("depends_on must be a list of table names, got %T", e)
+		}
+		strs[i] = s
+	}
+	return strs, nil
+}
+
+// Tables returns the names of the tables of the set in insertion order:
+// every table comes after the tables it depends on, others keep the order
+// they were added in.
+func (s *Set) Tables() ([]string, error) {
+	inserted := make(map[string]bool, len(s.names))
+	order := make([]string, 0, len(s.names))
+	for len(order) < len(s.names) {
+		progress := false
+		for _, name := range s.names {
+			if inserted[name] || !s.ready(name, inserted) {
+				continue
+			}
+			inserted[name] = true
+			order = append(order, name)
+			progress = true
+		}
+		if !progress {
+			var cycle []string
+			for _, name := range s.names {
+				if !inserted[name] {
+					cycle = append(cycle, name)
+				}
+			}
+			return nil, fmt.Errorf("fixture tables form a dependency cycle: %s", strings.Join(cycle, ", "))
+		}
+	}
+	return order, nil
+}
+
+func (s *Set) ready(name string, inserted map[string]bool) bool {
+	for _, dep := range s.tables[name].deps {
+		if _, ok := s.tables[dep]; ok && dep != name && !inserted[dep] {
+			return false
+		}
+	}
+	return true
+}
+
+// Statements builds the INSERT statements for the set, in the order they
+// must be run.
+func (s *Set) Statements() ([]*sqrl.InsertBuilder, error) {
+	names, err := s.Tables()
+	if err != nil {
+		return nil, err
+	}
+
+	var stmts []*sqrl.InsertBuilder
+	for _, name := range names {
+		rows := s.tables[name].rows
+		for len(rows) > 0 {
+			n := s.chunkSize(rows)
+			stmts = append(stmts, insertRows(name, rows[:n]))
+			rows = rows[n:]
+		}
+	}
+	return stmts, nil
+}
+
+func (s *Set) chunkSize(rows []Row) int {
+	size := s.ChunkSize
+	if size <= 0 {
+		size = DefaultChunkSize
+	}
+	if size > len(rows) {
+		size = len(rows)
+	}
+	for size > 1 && len(columns(rows[:size]))*size > maxArgs {
+		size /= 2
+	}
+	return size
+}
+
+func columns(rows []Row) []string {
+	seen := make(map[string]bool)
+	var cols []string
+	for _, row := range rows {
+		for col := range row {
+			if !seen[col] {
+				seen[col] = true
+				cols = append(cols, col)
+			}
+		}
+	}
+	sort.Strings(cols)
+	return cols
+}
+
+func insertRows(table string, rows []Row) *sqrl.InsertBuilder {
+	cols := columns(rows)
+	b := sqrl.StatementBuilder.PlaceholderFormat(sqrl.Dollar).Insert(table).Columns(cols...)
+	for _, row := range rows {
+		values := make([]interface{}, len(cols))
+		for i, col := range cols {
+			value, ok := row[col]
+			if !ok {
+				value = sqrl.Expr("DEFAULT")
+			}
+			values[i] = value
+		}
+		b.Values(values...)
+	}
+	return b
+}
+
+// Insert inserts the fixtures of the set using pool, stopping at the first
+// failing statement.
+func (s *Set) Insert(ctx context.Context, pool instapgxpool.Pool) error {
+	stmts, err := s.Statements()
+	if err != nil {
+		return err
+	}
+	for _, stmt := range stmts {
+		if _, err := stmt.ExecContext(ctx, pool); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func statements(t *testing.T, s *Set) ([]string, [][]interface{}) {
+	stmts, err := s.Statements()
+	assert.NoError(t, err)
+
+	var sqls []string
+	var args [][]interface{}
+	for _, stmt := range stmts {
+		sql, a, err := stmt.ToSql()
+		assert.NoError(t, err)
+		sqls = append(sqls, sql)
+		args = append(args, a)
+	}
+	return sqls, args
+}
+
+func TestLoadFiles(t *testing.T) {
+	s := NewSet()
+	assert.NoError(t, s.LoadFiles("testdata/comments.json", "testdata/blog.yaml"))
+
+	tables, err := s.Tables()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"users", "posts", "comments"}, tables)
+
+	sqls, args := statements(t, s)
+	assert.Equal(t, []string{
+		"INSERT INTO users (name) VALUES ($1)",
+		"INSERT INTO posts (title,user) VALUES ($1,$2)",
+		"INSERT INTO comments (body,id,post_id,score) VALUES ($1,$2,$3,$4),(DEFAULT,$5,$6,DEFAULT)",
+	}, sqls)
+	assert.Equal(t, []interface{}{"first", int64(1), int64(1), 1.5, int64(2), int64(1)}, args[2])
+}
+
+func TestLoadFilesErrors(t *testing.T) {
+	err := NewSet().LoadFiles("testdata/missing.json")
+	assert.Error(t, err)
+
+	err = NewSet().Decode([]byte(`{"users": {"rows": 1}}`), json.Unmarshal)
+	assert.EqualError(t, err, "table users: rows must be a list, got float64")
+
+	err = NewSet().Decode([]byte(`{"users": {"depends_on": "teams"}}`), json.Unmarshal)
+	assert.EqualError(t, err, "table users: depends_on must be a list of table names, got string")
+
+	err = NewSet().Decode([]byte(`[]`), json.Unmarshal)
+	assert.EqualError(t, err, "fixtures must map table names to rows")
+}
+
+func TestAddStructs(t *testing.T) {
+	type user struct {
+		ID       int64  `db:"id,omitempty"`
+		Name     string `db:"name"`
+		Password string `db:"-"`
+		Note     string
+	}
+
+	s := NewSet()
+	assert.NoError(t, s.AddStructs("users", []user{{ID: 1, Name: "a", Password: "x"}, {Name: "b"}}))
+
+	sqls, args := statements(t, s)
+	assert.Equal(t, []string{"INSERT INTO users (id,name) VALUES ($1,$2),(DEFAULT,$3)"}, sqls)
+	assert.Equal(t, [][]interface{}{{int64(1), "a", "b"}}, args)
+
+	assert.EqualError(t, s.AddStructs("users", user{}), "fixtures for table users must be a slice of structs, got fixtures.user")
+}
+
+func TestChunking(t *testing.T) {
+	s := NewSet()
+	s.ChunkSize = 2
+	s.Add("tags", Row{"name": "a"}, Row{"name": "b"}, Row{"name": "c"})
+
+	sqls, args := statements(t, s)
+	assert.Equal(t, []string{
+		"INSERT INTO tags (name) VALUES ($1),($2)",
+		"INSERT INTO tags (name) VALUES ($1)",
+	}, sqls)
+	assert.Equal(t, [][]interface{}{{"a", "b"}, {"c"}}, args)
+}
+
+func TestChunkingArgLimit(t *testing.T) {
+	row := Row{}
+	for i := 0; i < 200; i++ {
+		row[fmt.Sprintf("c%d", i)] = i
+	}
+	rows := make([]Row, DefaultChunkSize)
+	for i := range rows {
+		rows[i] = row
+	}
+
+	s := NewSet().Add("wide", rows...)
+	stmts, err := s.Statements()
+	assert.NoError(t, err)
+	assert.Len(t, stmts, 2)
+}
+
+func TestDependencyCycle(t *testing.T) {
+	s := NewSet().
+		Add("a", Row{"id": 1}).
+		Add("b", Row{"id": 1}).
+		Add("c", Row{"id": 1}).
+		DependsOn("a", "b").
+		DependsOn("b", "a").
+		DependsOn("c", "missing", "c")
+
+	_, err := s.Statements()
+	assert.EqualError(t, err, "fixture tables form a dependency cycle: a, b")
+}
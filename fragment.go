@@ -0,0 +1,100 @@
+package sqrl
+
+// Fragment is a named bundle of result columns, joins and WHERE conditions
+// which is applied to a SelectBuilder as a whole, e.g. for optional
+// "include=author,stats" expansions of a list query. A fragment is applied
+// at most once per builder, so fragments required by several others do not
+// duplicate their joins.
+//
+// Ex:
+//     author := sqrl.NewFragment("author").
+//         Columns("a.name AS author_name").
+//         Join("authors a ON a.id = p.author_id")
+//     stats := sqrl.NewFragment("stats").
+//         Requires(author).
+//         Columns("s.views").
+//         LeftJoin("author_stats s ON s.author_id = a.id")
+//     Select("p.id").From("posts p").Apply(author, stats)
+type Fragment struct {
+	name     string
+	requires []*Fragment
+	b        *SelectBuilder
+}
+
+// NewFragment returns an empty Fragment. Fragments are identified by name
+// when applied.
+func NewFragment(name string) *Fragment {
+	return &Fragment{name: name, b: &SelectBuilder{}}
+}
+
+// Name returns the name of the fragment.
+func (f *Fragment) Name() string {
+	return f.name
+}
+
+// Requires adds fragments which are applied before f.
+func (f *Fragment) Requires(fragments ...*Fragment) *Fragment {
+	f.requires = append(f.requires, fragments...)
+	return f
+}
+
+// Columns adds result columns to the fragment.
+func (f *Fragment) Columns(columns ...string) *Fragment {
+	f.b.Columns(columns...)
+	return f
+}
+
+// Column adds a result column to the fragment, see SelectBuilder.Column.
+func (f *Fragment) Column(column interface{}, args ...interface{}) *Fragment {
+	f.b.Column(column, args...)
+	return f
+}
+
+// JoinClause adds a join clause to the fragment.
+func (f *Fragment) JoinClause(pred interface{}, args ...interface{}) *Fragment {
+	f.b.JoinClause(pred, args...)
+	return f
+}
+
+// Join adds a JOIN clause to the fragment.
+func (f *Fragment) Join(join string, rest ...interface{}) *Fragment {
+	f.b.Join(join, rest...)
+	return f
+}
+
+// LeftJoin adds a LEFT JOIN clause to the fragment.
+func (f *Fragment) LeftJoin(join string, rest ...interface{}) *Fragment {
+	f.b.LeftJoin(join, rest...)
+	return f
+}
+
+// Where adds a condition to the fragment, see SelectBuilder.Where.
+func (f *Fragment) Where(pred interface{}, args ...interface{}) *Fragment {
+	f.b.Where(pred, args...)
+	return f
+}
+
+// Apply adds the columns, joins and conditions of fragments and the
+// fragments they require to the query. Fragments already applied to the
+// query are skipped.
+func (b *SelectBuilder) Apply(fragments ...*Fragment) *SelectBuilder {
+	for _, f := range fragments {
+		if b.hasFragment(f.name) {
+			continue
+		}
+		b.fragments = append(b.fragments, f.name)
+		b.Apply(f.requires...)
+		// A fragment has no FROM clause and OFFSET, so it can not conflict.
+		b.Merge(f.b)
+	}
+	return b
+}
+
+func (b *SelectBuilder) hasFragment(name string) bool {
+	for _, applied := range b.fragments {
+		if applied == name {
+			return true
+		}
+	}
+	return false
+}
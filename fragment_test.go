@@ -0,0 +1,35 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectBuilderApply(t *testing.T) {
+	author := NewFragment("author").
+		Columns("a.name AS author_name").
+		Join("authors a ON a.id = p.author_id")
+	stats := NewFragment("stats").
+		Requires(author).
+		Columns("s.views").
+		LeftJoin("author_stats s ON s.author_id = a.id").
+		Where("s.hidden = ?", false)
+
+	sql, args, err := Select("p.id").From("posts p").Apply(stats, author).Apply(stats).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT p.id, a.name AS author_name, s.views FROM posts p "+
+		"JOIN authors a ON a.id = p.author_id LEFT JOIN author_stats s ON s.author_id = a.id WHERE s.hidden = ?", sql)
+	assert.Equal(t, []interface{}{false}, args)
+	assert.Equal(t, "stats", stats.Name())
+}
+
+func TestSelectBuilderApplyCycle(t *testing.T) {
+	a := NewFragment("a").Columns("a")
+	b := NewFragment("b").Columns("b").Requires(a)
+	a.Requires(b)
+
+	sql, _, err := Select("id").From("t").Apply(a).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, b, a FROM t", sql)
+}
@@ -0,0 +1,141 @@
+package sqrl
+
+import (
+	"bytes"
+	"strings"
+)
+
+// TableFunction is a call of a set-returning function used as a relation,
+// e.g. "jsonb_to_recordset(?) AS x(a int, b text)".
+type TableFunction struct {
+	call       string
+	args       []interface{}
+	ordinality bool
+	alias      string
+	columns    []string
+}
+
+// Function returns a call of a set-returning function for use with
+// SelectBuilder.JoinFunction. call may contain placeholders for args.
+//
+// Ex:
+//     Function("unnest(?::text[])", Array(tags)).WithOrdinality()
+func Function(call string, args ...interface{}) *TableFunction {
+	return &TableFunction{call: call, args: args}
+}
+
+// WithOrdinality adds a bigint column numbering the rows returned by the
+// function, starting from 1, after the columns of the function.
+func (f *TableFunction) WithOrdinality() *TableFunction {
+	f.ordinality = true
+	return f
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (f *TableFunction) ToSql() (string, []interface{}, error) {
+	sql, args, err := Expr(f.call, f.args...).ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+
+	buf := bytes.NewBufferString(sql)
+	if f.ordinality {
+		buf.WriteString(" WITH ORDINALITY")
+	}
+	if len(f.alias) > 0 {
+		buf.WriteString(" AS ")
+		buf.WriteString(f.alias)
+		if len(f.columns) > 0 {
+			buf.WriteString("(")
+			buf.WriteString(strings.Join(f.columns, ", "))
+			buf.WriteString(")")
+		}
+	}
+	return buf.String(), args, nil
+}
+
+// FunctionFrom is a set-returning function in the FROM clause of a
+// SelectBuilder, see SelectBuilder.FromFunction.
+type FunctionFrom struct {
+	b *SelectBuilder
+	f *TableFunction
+}
+
+// FromFunction adds a call of a set-returning function to the FROM clause
+// of the query. call may contain placeholders for args. The alias and, for
+// functions returning records, the column definitions are set with As.
+//
+// Ex:
+//     Select("x.a", "x.b").
+//         FromFunction("jsonb_to_recordset(?)", data).As("x", "a int", "b text")
+//     == "SELECT x.a, x.b FROM jsonb_to_recordset(?) AS x(a int, b text)"
+func (b *SelectBuilder) FromFunction(call string, args ...interface{}) *FunctionFrom {
+	f := Function(call, args...)
+	b.fromParts = append(b.fromParts, f)
+	return &FunctionFrom{b: b, f: f}
+}
+
+// WithOrdinality adds a bigint column numbering the rows returned by the
+// function, starting from 1, after the columns of the function.
+func (f *FunctionFrom) WithOrdinality() *FunctionFrom {
+	f.f.WithOrdinality()
+	return f
+}
+
+// As sets the alias of the function and its column names or, for functions
+// returning records, column definitions like "a int", and returns the
+// SelectBuilder.
+func (f *FunctionFrom) As(alias string, columns ...string) *SelectBuilder {
+	f.f.alias = alias
+	f.f.columns = columns
+	return f.b
+}
+
+// functionJoin joins a TableFunction.
+type functionJoin struct {
+	join string
+	fn   TableFunction
+	on   string
+	args []interface{}
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (j functionJoin) ToSql() (string, []interface{}, error) {
+	sql, args, err := j.fn.ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+	on, onArgs, err := Expr(j.on, j.args...).ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+	return j.join + " " + sql + " ON " + on, append(args, onArgs...), nil
+}
+
+// JoinFunction adds a JOIN of the set-returning function fn to the query.
+// alias may include column names or definitions, e.g. "t(tag, n)". on is
+// the join condition, which may contain placeholders for args; an empty on
+// joins every row of fn with ON true.
+//
+// Ex:
+//     Select("p.id", "t.tag", "t.rank").From("posts p").
+//         JoinFunction(Function("unnest(p.tags)").WithOrdinality(), "t(tag, rank)", "")
+//     == "SELECT p.id, t.tag, t.rank FROM posts p JOIN unnest(p.tags) WITH ORDINALITY AS t(tag, rank) ON true"
+func (b *SelectBuilder) JoinFunction(fn *TableFunction, alias string, on string, args ...interface{}) *SelectBuilder {
+	return b.joinFunction("JOIN", fn, alias, on, args)
+}
+
+// LeftJoinFunction is like JoinFunction, but adds a LEFT JOIN.
+func (b *SelectBuilder) LeftJoinFunction(fn *TableFunction, alias string, on string, args ...interface{}) *SelectBuilder {
+	return b.joinFunction("LEFT JOIN", fn, alias, on, args)
+}
+
+func (b *SelectBuilder) joinFunction(join string, fn *TableFunction, alias string, on string, args []interface{}) *SelectBuilder {
+	if len(on) == 0 {
+		on = "true"
+	}
+	f := *fn
+	f.alias = alias
+	b.joins = append(b.joins, functionJoin{join: join, fn: f, on: on, args: args})
+	return b
+}
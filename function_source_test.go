@@ -0,0 +1,51 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectBuilderFromFunction(t *testing.T) {
+	data := `[{"a": 1, "b": "x"}]`
+	sql, args, err := Select("x.a", "x.b", "u.name").
+		FromFunction("jsonb_to_recordset(?)", data).As("x", "a int", "b text").
+		Join("users u ON u.id = x.a").
+		Where("x.b <> ?", "y").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT x.a, x.b, u.name FROM jsonb_to_recordset($1) AS x(a int, b text) "+
+		"JOIN users u ON u.id = x.a WHERE x.b <> $2", sql)
+	assert.Equal(t, []interface{}{data, "y"}, args)
+	assert.Equal(t, []string{"users"}, Tables(Select("x.a").FromFunction("jsonb_to_recordset(?)", data).As("x", "a int").Join("users u ON u.id = x.a")))
+
+	sql, args, err = Select("t.tag", "t.n").
+		FromFunction("unnest(?::text[])", Array([]string{"a", "b"})).WithOrdinality().As("t", "tag", "n").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT t.tag, t.n FROM unnest(?::text[]) WITH ORDINALITY AS t(tag, n)", sql)
+	assert.Equal(t, []interface{}{`{"a","b"}`}, args)
+
+	_, _, err = Select("n").FromFunction("generate_series(?, ?)", 1).As("n").ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderJoinFunction(t *testing.T) {
+	sql, args, err := Select("p.id", "t.tag", "t.rank").
+		From("posts p").
+		JoinFunction(Function("unnest(p.tags)").WithOrdinality(), "t(tag, rank)", "").
+		LeftJoinFunction(Function("jsonb_to_recordset(p.scores)"), "s(tag text, score int)", "s.tag = t.tag AND s.score > ?", 10).
+		Where("p.id = ?", 1).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT p.id, t.tag, t.rank FROM posts p "+
+		"JOIN unnest(p.tags) WITH ORDINALITY AS t(tag, rank) ON true "+
+		"LEFT JOIN jsonb_to_recordset(p.scores) AS s(tag text, score int) ON s.tag = t.tag AND s.score > $1 "+
+		"WHERE p.id = $2", sql)
+	assert.Equal(t, []interface{}{10, 1}, args)
+
+	tags := Array([]string{"a"})
+	assert.Equal(t, []string{"posts"}, Tables(Select("p.id").From("posts p").JoinFunction(Function("unnest(?::text[])", tags), "t", "")))
+}
@@ -0,0 +1,147 @@
+package sqrl
+
+import (
+	"context"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgx/v4"
+)
+
+// Get runs the query built by s and scans the single column of its first
+// row into a value of type T.
+//
+// Ex:
+//     count, err := sqrl.Get[int](ctx, pool, sqrl.Select("count(*)").From("users"))
+func Get[T interface{}](ctx context.Context, pool instapgxpool.Pool, s Sqlizer) (T, error) {
+	var value T
+	err := QueryRowWithContext(ctx, pool, s).Scan(&value)
+	return value, err
+}
+
+// Pluck runs the query built by s and collects the single column of every
+// row into a slice of type T.
+//
+// Ex:
+//     names, err := sqrl.Pluck[string](ctx, pool, sqrl.Select("name").From("users"))
+func Pluck[T interface{}](ctx context.Context, pool instapgxpool.Pool, s Sqlizer) ([]T, error) {
+	rows, err := QueryWithContext(ctx, pool, s)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make([]T, 0)
+	for rows.Next() {
+		var value T
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// ToMap runs the query built by s and collects the first column of every row
+// as key and the second column as value into a map.
+//
+// Ex:
+//     names, err := sqrl.ToMap[int64, string](ctx, pool, sqrl.Select("id", "name").From("users"))
+func ToMap[K comparable, V interface{}](ctx context.Context, pool instapgxpool.Pool, s Sqlizer) (map[K]V, error) {
+	rows, err := QueryWithContext(ctx, pool, s)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make(map[K]V)
+	for rows.Next() {
+		var key K
+		var value V
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+	return values, rows.Err()
+}
+
+// Rows is a type-safe cursor over the result of a query, returned by
+// QueryRows. Every row is converted to a T by the scan function passed to
+// QueryRows. The result is closed once Next returns false; Close must be
+// called if iteration is stopped early, it may be called more than once.
+type Rows[T interface{}] struct {
+	rows   pgx.Rows
+	scan   func(row RowScanner) (T, error)
+	value  T
+	err    error
+	closed bool
+}
+
+// QueryRows runs the query built by s and returns a cursor over its rows,
+// converting every row with scan.
+//
+// Ex:
+//     rows, err := sqrl.QueryRows(ctx, pool, sqrl.Select("id", "name").From("users"),
+//         func(row sqrl.RowScanner) (User, error) {
+//             var u User
+//             err := row.Scan(&u.ID, &u.Name)
+//             return u, err
+//         })
+//     if err != nil {
+//         return err
+//     }
+//     defer rows.Close()
+//     for rows.Next() {
+//         user := rows.Value()
+//         ...
+//     }
+//     return rows.Err()
+func QueryRows[T interface{}](ctx context.Context, pool instapgxpool.Pool, s Sqlizer, scan func(row RowScanner) (T, error)) (*Rows[T], error) {
+	rows, err := QueryWithContext(ctx, pool, s)
+	if err != nil {
+		return nil, err
+	}
+	return &Rows[T]{rows: rows, scan: scan}, nil
+}
+
+// Next advances to the next row, returning false once all rows have been
+// read or an error occurred.
+func (r *Rows[T]) Next() bool {
+	if r.closed {
+		return false
+	}
+	if !r.rows.Next() {
+		r.Close()
+		return false
+	}
+
+	var zero T
+	r.value, r.err = r.scan(r.rows)
+	if r.err != nil {
+		r.value = zero
+		r.Close()
+		return false
+	}
+	return true
+}
+
+// Value returns the current row.
+func (r *Rows[T]) Value() T {
+	return r.value
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (r *Rows[T]) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.rows.Err()
+}
+
+// Close closes the result.
+func (r *Rows[T]) Close() {
+	if r.closed {
+		return
+	}
+	r.closed = true
+	r.rows.Close()
+}
@@ -0,0 +1,119 @@
+package sqrl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{42}}}
+
+	count, err := Get[int](context.Background(), pool, Select("count(*)").From("users").Where(Eq{"active": true}))
+	assert.NoError(t, err)
+	assert.Equal(t, 42, count)
+	assert.Equal(t, []string{"SELECT count(*) FROM users WHERE active = ?"}, pool.stub.sqls)
+	assert.Equal(t, [][]interface{}{{true}}, pool.stub.args)
+}
+
+func TestGetNoRows(t *testing.T) {
+	pool := newPoolStub()
+
+	name, err := Get[string](context.Background(), pool, Select("name").From("users"))
+	assert.Equal(t, pgx.ErrNoRows, err)
+	assert.Equal(t, "", name)
+}
+
+func TestPluck(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{"a"}, {"b"}, {"c"}}}
+
+	names, err := Pluck[string](context.Background(), pool, Select("name").From("users"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, names)
+
+	names, err = Pluck[string](context.Background(), pool, Select("name").From("users"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, names)
+}
+
+func TestPluckErr(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.err = errors.New("query failed")
+
+	names, err := Pluck[string](context.Background(), pool, Select("name").From("users"))
+	assert.True(t, errors.Is(err, pool.stub.err))
+	assert.Nil(t, names)
+}
+
+func TestToMap(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{1, "a"}, {2, "b"}}}
+
+	names, err := ToMap[int64, string](context.Background(), pool, Select("id", "name").From("users"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]string{1: "a", 2: "b"}, names)
+}
+
+func TestToMapScanErr(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{1}}}
+
+	_, err := ToMap[int64, string](context.Background(), pool, Select("id").From("users"))
+	assert.Error(t, err)
+}
+
+type rowsUser struct {
+	ID   int
+	Name string
+}
+
+func scanRowsUser(row RowScanner) (rowsUser, error) {
+	var u rowsUser
+	err := row.Scan(&u.ID, &u.Name)
+	return u, err
+}
+
+func TestQueryRows(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{1, "moe"}, {2, "larry"}}}
+
+	rows, err := QueryRows(context.Background(), pool, Select("id", "name").From("users"), scanRowsUser)
+	assert.NoError(t, err)
+	defer rows.Close()
+
+	var users []rowsUser
+	for rows.Next() {
+		users = append(users, rows.Value())
+	}
+	assert.NoError(t, rows.Err())
+	assert.Equal(t, []rowsUser{{1, "moe"}, {2, "larry"}}, users)
+	assert.False(t, rows.Next())
+}
+
+func TestQueryRowsScanErr(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{1, "moe"}, {2, "larry"}}}
+	stop := errors.New("stop")
+
+	rows, err := QueryRows(context.Background(), pool, Select("id", "name").From("users"), func(row RowScanner) (rowsUser, error) {
+		return rowsUser{}, stop
+	})
+	assert.NoError(t, err)
+	assert.False(t, rows.Next())
+	assert.Equal(t, stop, rows.Err())
+	rows.Close()
+}
+
+func TestQueryRowsQueryErr(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.err = errors.New("query failed")
+
+	rows, err := QueryRows(context.Background(), pool, Select("id", "name").From("users"), scanRowsUser)
+	assert.Nil(t, rows)
+	assert.True(t, errors.Is(err, pool.stub.err))
+}
@@ -3,8 +3,29 @@ module github.com/clevabit/sqrl
 require (
 	github.com/clevabit/utils-go v0.0.0-20200503110930-d34972e5c2d2
 	github.com/jackc/pgconn v1.5.0
+	github.com/jackc/pgproto3/v2 v2.0.1
 	github.com/jackc/pgx/v4 v4.6.0
 	github.com/stretchr/testify v1.5.1
+	gopkg.in/yaml.v2 v2.2.4
 )
 
-go 1.13
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/gogo/protobuf v1.3.1 // indirect
+	github.com/instana/go-sensor v1.11.0 // indirect
+	github.com/jackc/chunkreader/v2 v2.0.1 // indirect
+	github.com/jackc/pgio v1.0.0 // indirect
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20200307190119-3430c5407db8 // indirect
+	github.com/jackc/pgtype v1.3.0 // indirect
+	github.com/jackc/puddle v1.1.1 // indirect
+	github.com/looplab/fsm v0.1.0 // indirect
+	github.com/opentracing/basictracer-go v1.1.0 // indirect
+	github.com/opentracing/opentracing-go v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/crypto v0.0.0-20200429183012-4b2356b1ed79 // indirect
+	golang.org/x/text v0.3.2 // indirect
+	golang.org/x/xerrors v0.0.0-20191204190536-9bdfabe68543 // indirect
+)
+
+go 1.18
@@ -25,6 +25,9 @@ type InsertBuilder struct {
 	values   [][]interface{}
 	suffixes exprs
 	iselect  *SelectBuilder
+
+	onConflict *onConflict
+	nullIfZero nullIfZeroColumns
 }
 
 // NewInsertBuilder creates new instance of InsertBuilder
@@ -32,19 +35,34 @@ func NewInsertBuilder(b StatementBuilderType) *InsertBuilder {
 	return &InsertBuilder{StatementBuilderType: b}
 }
 
-// ExecContext builds and Execs the query with the Runner set by RunWith using given context.
+// ExecContext builds and Execs the query using given context.
 func (b *InsertBuilder) ExecContext(ctx context.Context, pool instapgxpool.Pool) (pgconn.CommandTag, error) {
-	return ExecWithContext(ctx, pool, b)
+	return b.execContext(ctx, pool, b)
+}
+
+// ExecAttached is like ExecContext, using the pool set with RunWithPool.
+func (b *InsertBuilder) ExecAttached(ctx context.Context) (pgconn.CommandTag, error) {
+	return b.ExecContext(ctx, nil)
 }
 
 // QueryContext builds and runs the query using given context and Query command.
 func (b *InsertBuilder) QueryContext(ctx context.Context, pool instapgxpool.Pool) (pgx.Rows, error) {
-	return QueryWithContext(ctx, pool, b)
+	return b.queryContext(ctx, pool, b)
+}
+
+// QueryAttached is like QueryContext, using the pool set with RunWithPool.
+func (b *InsertBuilder) QueryAttached(ctx context.Context) (pgx.Rows, error) {
+	return b.QueryContext(ctx, nil)
 }
 
 // QueryRowContext builds and runs the query using given context.
 func (b *InsertBuilder) QueryRowContext(ctx context.Context, pool instapgxpool.Pool) RowScanner {
-	return QueryRowWithContext(ctx, pool, b)
+	return b.queryRowContext(ctx, pool, b)
+}
+
+// QueryRowAttached is like QueryRowContext, using the pool set with RunWithPool.
+func (b *InsertBuilder) QueryRowAttached(ctx context.Context) RowScanner {
+	return b.QueryRowContext(ctx, nil)
 }
 
 // Scan is a shortcut for QueryRow().Scan.
@@ -52,6 +70,47 @@ func (b *InsertBuilder) Scan(ctx context.Context, pool instapgxpool.Pool, dest .
 	return b.QueryRowContext(ctx, pool).Scan(dest...)
 }
 
+// ScanContext is a shortcut for QueryRowAttached(ctx).Scan.
+func (b *InsertBuilder) ScanContext(ctx context.Context, dest ...interface{}) error {
+	return b.QueryRowAttached(ctx).Scan(dest...)
+}
+
+// RunWithPool sets the pool used by ExecContext, QueryContext and
+// QueryRowContext when none is passed explicitly.
+func (b *InsertBuilder) RunWithPool(pool instapgxpool.Pool) *InsertBuilder {
+	b.runWithPool = pool
+	return b
+}
+
+// DryRun makes ExecContext, QueryContext and QueryRowContext return a
+// DryRunResult with the SQL and args instead of running the query.
+func (b *InsertBuilder) DryRun() *InsertBuilder {
+	b.dryRun = dryRunSQL
+	return b
+}
+
+// DryRunExplain is like DryRun, but also runs EXPLAIN for the query and
+// returns the plan with the DryRunResult.
+func (b *InsertBuilder) DryRunExplain() *InsertBuilder {
+	b.dryRun = dryRunExplain
+	return b
+}
+
+// SimpleProtocol makes ExecContext, QueryContext and QueryRowContext run the
+// query using the simple query protocol, with all args inlined as literals
+// (see ToSqlInlined).
+func (b *InsertBuilder) SimpleProtocol() *InsertBuilder {
+	b.simpleProtocol = true
+	return b
+}
+
+// Strict makes ToSql reject suspicious content in the strings interpolated
+// verbatim, see StatementBuilderType.Strict.
+func (b *InsertBuilder) Strict() *InsertBuilder {
+	b.strict = true
+	return b
+}
+
 // PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
 // query.
 func (b *InsertBuilder) PlaceholderFormat(f PlaceholderFormat) *InsertBuilder {
@@ -59,6 +118,25 @@ func (b *InsertBuilder) PlaceholderFormat(f PlaceholderFormat) *InsertBuilder {
 	return b
 }
 
+// MustSql builds the query like ToSql, but panics on errors. It is meant
+// for queries which are known to be valid, e.g. package level statements.
+func (b *InsertBuilder) MustSql() (string, []interface{}) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// ToSqlInlined builds the query into a SQL string with all args inlined as
+// literals, e.g. for migration files or seed scripts. It fails for args
+// which can not be rendered as literals.
+func (b *InsertBuilder) ToSqlInlined() (string, error) {
+	c := *b
+	c.placeholderFormat = Question
+	return toSqlInlined(&c)
+}
+
 // ToSql builds the query into a SQL string and bound args.
 func (b *InsertBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 	if len(b.into) == 0 {
@@ -70,10 +148,21 @@ func (b *InsertBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 		return
 	}
 
+	if b.strict {
+		if err = checkStrict(b); err != nil {
+			return
+		}
+	}
+	if err = b.schema.Validate(b); err != nil {
+		return
+	}
+
 	sql := &bytes.Buffer{}
 
 	if len(b.prefixes) > 0 {
-		args, _ = b.prefixes.AppendToSql(sql, " ", args)
+		if args, err = b.prefixes.AppendToSql(sql, " ", args); err != nil {
+			return
+		}
 		sql.WriteString(" ")
 	}
 
@@ -103,6 +192,13 @@ func (b *InsertBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 		return
 	}
 
+	if b.onConflict != nil {
+		args, err = b.onConflict.appendToSql(sql, args)
+		if err != nil {
+			return
+		}
+	}
+
 	if len(b.returning) > 0 {
 		args, err = b.returning.AppendToSql(sql, args)
 		if err != nil {
@@ -112,7 +208,9 @@ func (b *InsertBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 
 	if len(b.suffixes) > 0 {
 		sql.WriteString(" ")
-		args, _ = b.suffixes.AppendToSql(sql, " ", args)
+		if args, err = b.suffixes.AppendToSql(sql, " ", args); err != nil {
+			return
+		}
 	}
 
 	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
@@ -124,17 +222,22 @@ func (b *InsertBuilder) appendValuesToSQL(w io.Writer, args []interface{}) ([]in
 		return args, errors.New("values for insert statements are not set")
 	}
 
-	io.WriteString(w, "VALUES ")
+	if _, err := io.WriteString(w, "VALUES "); err != nil {
+		return nil, err
+	}
 
 	valuesStrings := make([]string, len(b.values))
 	for r, row := range b.values {
 		valueStrings := make([]string, len(row))
 		for v, val := range row {
+			if v < len(b.columns) {
+				val = b.nullIfZero.value(b.columns[v], val)
+			}
+			if sb, ok := val.(*SelectBuilder); ok {
+				val = Subquery(sb)
+			}
 
-			switch typedVal := val.(type) {
-			case expr:
-				valueStrings[v] = typedVal.sql
-				args = append(args, typedVal.args...)
+			switch typedVal := encodeArg(val).(type) {
 			case Sqlizer:
 				var valSql string
 				var valArgs []interface{}
@@ -149,13 +252,15 @@ func (b *InsertBuilder) appendValuesToSQL(w io.Writer, args []interface{}) ([]in
 				args = append(args, valArgs...)
 			default:
 				valueStrings[v] = "?"
-				args = append(args, val)
+				args = append(args, typedVal)
 			}
 		}
 		valuesStrings[r] = fmt.Sprintf("(%s)", strings.Join(valueStrings, ","))
 	}
 
-	io.WriteString(w, strings.Join(valuesStrings, ","))
+	if _, err := io.WriteString(w, strings.Join(valuesStrings, ",")); err != nil {
+		return nil, err
+	}
 
 	return args, nil
 }
@@ -165,12 +270,14 @@ func (b *InsertBuilder) appendSelectToSQL(w io.Writer, args []interface{}) ([]in
 		return args, errors.New("select clause for insert statements are not set")
 	}
 
-	selectClause, sArgs, err := b.iselect.ToSql()
+	selectClause, sArgs, err := nested(b.iselect).ToSql()
 	if err != nil {
 		return args, err
 	}
 
-	io.WriteString(w, selectClause)
+	if _, err := io.WriteString(w, selectClause); err != nil {
+		return nil, err
+	}
 	args = append(args, sArgs...)
 
 	return args, nil
@@ -182,6 +289,14 @@ func (b *InsertBuilder) Prefix(sql string, args ...interface{}) *InsertBuilder {
 	return b
 }
 
+// PrefixExpr adds an expression built by a Sqlizer, e.g. a SelectBuilder
+// used as the body of a CTE, to the beginning of the query. Its args are
+// merged in order and its errors are returned by ToSql.
+func (b *InsertBuilder) PrefixExpr(e Sqlizer) *InsertBuilder {
+	b.prefixes = append(b.prefixes, Expr("?", e))
+	return b
+}
+
 // Options adds keyword options before the INTO clause of the query.
 func (b *InsertBuilder) Options(options ...string) *InsertBuilder {
 	b.options = append(b.options, options...)
@@ -200,7 +315,8 @@ func (b *InsertBuilder) Columns(columns ...string) *InsertBuilder {
 	return b
 }
 
-// Values adds a single row's values to the query.
+// Values adds a single row's values to the query. SelectBuilder values are
+// added as subqueries in parentheses, see Subquery.
 func (b *InsertBuilder) Values(values ...interface{}) *InsertBuilder {
 	b.values = append(b.values, values)
 	return b
@@ -228,6 +344,13 @@ func (b *InsertBuilder) Suffix(sql string, args ...interface{}) *InsertBuilder {
 	return b
 }
 
+// SuffixExpr adds an expression built by a Sqlizer to the end of the query.
+// Its args are merged in order and its errors are returned by ToSql.
+func (b *InsertBuilder) SuffixExpr(e Sqlizer) *InsertBuilder {
+	b.suffixes = append(b.suffixes, Expr("?", e))
+	return b
+}
+
 // SetMap set columns and values for insert builder from a map of column name and value
 // note that it will reset all previous columns and values was set if any
 func (b *InsertBuilder) SetMap(clauses map[string]interface{}) *InsertBuilder {
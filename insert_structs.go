@@ -0,0 +1,153 @@
+package sqrl
+
+import (
+	"context"
+	"fmt"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"reflect"
+)
+
+// maxInsertStructsChunk is the maximum number of rows InsertStructs inserts
+// per statement.
+const maxInsertStructsChunk = 1000
+
+// InsertStructs inserts a slice of structs, or pointers to structs, of the
+// same type into table. Columns are taken from the db tags of the struct fields; fields
+// without a db tag or tagged "-" are skipped and zero fields tagged
+// omitempty are inserted as DEFAULT.
+//
+// Rows are inserted with multi-row INSERT statements of at most 1000 rows
+// each, and fewer if needed to stay within the limits of Postgres on bound
+// parameters. If returning columns are given, e.g. a generated id, they are
+// added as RETURNING clause and their values are scanned back into the
+// fields tagged with them, which requires the elements of the slice to be
+// addressable. InsertStructs returns the number of rows inserted.
+//
+// Ex:
+//     type User struct {
+//         ID   int64  `db:"id,omitempty"`
+//         Name string `db:"name"`
+//     }
+//
+//     users := []User{{Name: "moe"}, {Name: "larry"}}
+//     n, err := sqrl.InsertStructs(ctx, pool, "users", users, "id")
+//     // users[0].ID and users[1].ID are set
+func InsertStructs(ctx context.Context, pool instapgxpool.Pool, table string, structs interface{}, returning ...string) (int64, error) {
+	v := reflect.ValueOf(structs)
+	if v.Kind() != reflect.Slice {
+		return 0, fmt.Errorf("structs inserted into %s must be a slice of structs, got %T", table, structs)
+	}
+	if v.Len() == 0 {
+		return 0, nil
+	}
+
+	elems := make([]reflect.Value, v.Len())
+	for i := range elems {
+		elem := v.Index(i)
+		if elem.Kind() == reflect.Interface {
+			elem = elem.Elem()
+		}
+		if elem.Kind() == reflect.Ptr {
+			if elem.IsNil() {
+				return 0, fmt.Errorf("struct %d inserted into %s is nil", i, table)
+			}
+			elem = elem.Elem()
+		}
+		if elem.Kind() != reflect.Struct {
+			return 0, fmt.Errorf("structs inserted into %s must be a slice of structs, got %T", table, structs)
+		}
+		// All types are checked before the first chunk is inserted, so a
+		// mixed slice does not leave a partial insert behind.
+		if i > 0 && elem.Type() != elems[0].Type() {
+			return 0, fmt.Errorf("structs inserted into %s must be of the same type, got %s and %s", table, elems[0].Type(), elem.Type())
+		}
+		elems[i] = elem
+	}
+
+	fields := StructFields(elems[0].Type())
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("%s has no db tagged fields", elems[0].Type())
+	}
+	columns := make([]string, len(fields))
+	for i, field := range fields {
+		columns[i] = field.Column
+	}
+
+	targets := make([]int, len(returning))
+	for i, column := range returning {
+		targets[i] = -1
+		for _, field := range fields {
+			if field.Column == column {
+				targets[i] = field.Index
+			}
+		}
+		if targets[i] < 0 {
+			return 0, fmt.Errorf("returning column %q has no db tagged field in %s", column, elems[0].Type())
+		}
+	}
+	for _, elem := range elems {
+		if len(returning) > 0 && !elem.CanAddr() {
+			return 0, fmt.Errorf("structs inserted into %s must be addressable to scan returning columns, got %T", table, structs)
+		}
+	}
+
+	chunk := maxArgs / len(columns)
+	if chunk > maxInsertStructsChunk {
+		chunk = maxInsertStructsChunk
+	}
+
+	var inserted int64
+	for len(elems) > 0 {
+		n := chunk
+		if n > len(elems) {
+			n = len(elems)
+		}
+
+		b := StatementBuilder.PlaceholderFormat(Dollar).Insert(table).Columns(columns...)
+		for _, elem := range elems[:n] {
+			values := make([]interface{}, len(fields))
+			for i, field := range fields {
+				value := elem.Field(field.Index)
+				if field.OmitEmpty && value.IsZero() {
+					values[i] = Expr("DEFAULT")
+					continue
+				}
+				values[i] = value.Interface()
+			}
+			b.Values(values...)
+		}
+
+		if len(returning) == 0 {
+			tag, err := b.ExecContext(ctx, pool)
+			if err != nil {
+				return inserted, err
+			}
+			inserted += tag.RowsAffected()
+		} else {
+			b.Returning(returning...)
+			rows, err := b.QueryContext(ctx, pool)
+			if err != nil {
+				return inserted, err
+			}
+			i := 0
+			for rows.Next() && i < n {
+				dest := make([]interface{}, len(targets))
+				for j, index := range targets {
+					dest[j] = elems[i].Field(index).Addr().Interface()
+				}
+				if err := rows.Scan(dest...); err != nil {
+					rows.Close()
+					return inserted, err
+				}
+				i++
+				inserted++
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return inserted, err
+			}
+		}
+		elems = elems[n:]
+	}
+	return inserted, nil
+}
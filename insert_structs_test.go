@@ -0,0 +1,82 @@
+package sqrl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+type insertStructsUser struct {
+	ID       int64  `db:"id,omitempty"`
+	Name     string `db:"name"`
+	Password string `db:"-"`
+	note     string
+}
+
+func TestInsertStructs(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.tag = pgconn.CommandTag("INSERT 0 2")
+
+	n, err := InsertStructs(context.Background(), pool, "users", []insertStructsUser{{Name: "moe"}, {ID: 7, Name: "larry"}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	assert.Equal(t, []string{"INSERT INTO users (id,name) VALUES (DEFAULT,$1),($2,$3)"}, pool.stub.sqls)
+	assert.Equal(t, [][]interface{}{{"moe", int64(7), "larry"}}, pool.stub.args)
+}
+
+func TestInsertStructsReturning(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{1}, {2}}}
+
+	users := []*insertStructsUser{{Name: "moe"}, {Name: "larry"}}
+	n, err := InsertStructs(context.Background(), pool, "users", users, "id")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	assert.Equal(t, int64(1), users[0].ID)
+	assert.Equal(t, int64(2), users[1].ID)
+	assert.Equal(t, []string{"INSERT INTO users (id,name) VALUES (DEFAULT,$1),(DEFAULT,$2) RETURNING id"}, pool.stub.sqls)
+}
+
+func TestInsertStructsChunks(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.tag = pgconn.CommandTag("INSERT 0 1000")
+
+	users := make([]insertStructsUser, 2500)
+	for i := range users {
+		users[i] = insertStructsUser{ID: int64(i + 1), Name: "moe"}
+	}
+	_, err := InsertStructs(context.Background(), pool, "users", users)
+	assert.NoError(t, err)
+	if assert.Len(t, pool.stub.args, 3) {
+		assert.Len(t, pool.stub.args[0], 2000)
+		assert.Len(t, pool.stub.args[2], 1000)
+	}
+}
+
+func TestInsertStructsErrors(t *testing.T) {
+	pool := newPoolStub()
+
+	_, err := InsertStructs(context.Background(), pool, "users", insertStructsUser{})
+	assert.EqualError(t, err, "structs inserted into users must be a slice of structs, got sqrl.insertStructsUser")
+
+	_, err = InsertStructs(context.Background(), pool, "users", []insertStructsUser{{}}, "email")
+	assert.EqualError(t, err, `returning column "email" has no db tagged field in sqrl.insertStructsUser`)
+
+	type insertStructsAdmin struct {
+		Name string `db:"name"`
+	}
+	mixed := make([]interface{}, 1500)
+	for i := range mixed {
+		mixed[i] = &insertStructsUser{Name: "moe"}
+	}
+	mixed[1200] = insertStructsAdmin{Name: "larry"}
+	_, err = InsertStructs(context.Background(), pool, "users", mixed)
+	assert.EqualError(t, err, "structs inserted into users must be of the same type, got sqrl.insertStructsUser and sqrl.insertStructsAdmin")
+
+	n, err := InsertStructs(context.Background(), pool, "users", []insertStructsUser{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+	assert.Empty(t, pool.stub.sqls)
+}
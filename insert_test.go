@@ -95,3 +95,52 @@ func TestInsertBuilderSelect(t *testing.T) {
 	expectedArgs := []interface{}{1}
 	assert.Equal(t, expectedArgs, args)
 }
+
+func TestInsertBuilderNestedExprValue(t *testing.T) {
+	sql, args, err := Insert("a").
+		Columns("b", "c").
+		Values(1, Expr("(?)", Select("max(c)").From("a").Where("d = ?", 2))).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO a (b,c) VALUES (?,(SELECT max(c) FROM a WHERE d = ?))", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+}
+
+func TestInsertBuilderSelectValue(t *testing.T) {
+	sql, args, err := Insert("a").
+		Columns("b", "c").
+		Values(1, Select("max(c)").From("a").Where("d = ?", 2)).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO a (b,c) VALUES ($1,(SELECT max(c) FROM a WHERE d = $2))", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	sql, args, err = Insert("a").
+		Columns("b").
+		Values(1).
+		OnConflict("b").DoUpdateSet("c", Select("max(c)").From("a").Where("d = ?", 2)).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO a (b) VALUES (?) ON CONFLICT (b) DO UPDATE SET c = (SELECT max(c) FROM a WHERE d = ?)", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+}
+
+func TestInsertBuilderToSqlInlined(t *testing.T) {
+	sql, err := Insert("users").
+		Columns("id", "name", "admin", "created_at").
+		Values(1, "a", true, Expr("now()")).
+		Values(2, nil, false, Expr("now() - ?::interval", "1 day")).
+		ToSqlInlined()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (id,name,admin,created_at) VALUES (1,'a',TRUE,now()),(2,NULL,FALSE,now() - '1 day'::interval)", sql)
+}
+
+func TestInsertBuilderPointerValues(t *testing.T) {
+	name := "moe"
+	var nick *string
+	sql, args, err := Insert("users").Columns("name", "nick").Values(&name, nick).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (name,nick) VALUES (?,?)", sql)
+	assert.Equal(t, []interface{}{"moe", nil}, args)
+}
@@ -0,0 +1,118 @@
+package sqrl
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Keyset is a position for keyset pagination: the values of the ordered
+// columns of the last row seen.
+//
+// Used with Where it selects the rows following that position.
+// Ex:
+//     k := Keyset{Columns: []string{"created_at", "id"}, Values: []interface{}{t, 42}}
+//     Select("*").From("posts").Where(k).OrderBy(k.OrderBys()...).Limit(20)
+//     == "SELECT * FROM posts WHERE (created_at, id) > (?,?) ORDER BY created_at ASC, id ASC LIMIT 20"
+type Keyset struct {
+	Columns []string
+	Values  []interface{}
+	Desc    bool
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (k Keyset) ToSql() (sql string, args []interface{}, err error) {
+	if len(k.Columns) == 0 {
+		return "", nil, fmt.Errorf("keyset must have at least one column")
+	}
+	if len(k.Columns) != len(k.Values) {
+		return "", nil, fmt.Errorf("keyset has %d columns but %d values", len(k.Columns), len(k.Values))
+	}
+
+	op := ">"
+	if k.Desc {
+		op = "<"
+	}
+	if len(k.Columns) == 1 {
+		return fmt.Sprintf("%s %s ?", k.Columns[0], op), k.Values, nil
+	}
+	return fmt.Sprintf("(%s) %s (%s)", strings.Join(k.Columns, ", "), op, Placeholders(len(k.Values))), k.Values, nil
+}
+
+// OrderBys returns the ORDER BY expressions matching the keyset.
+func (k Keyset) OrderBys() []string {
+	dir := " ASC"
+	if k.Desc {
+		dir = " DESC"
+	}
+	orderBys := make([]string, len(k.Columns))
+	for i, column := range k.Columns {
+		orderBys[i] = column + dir
+	}
+	return orderBys
+}
+
+type cursorToken struct {
+	Columns []string      `json:"c"`
+	Values  []interface{} `json:"v"`
+	Desc    bool          `json:"d,omitempty"`
+}
+
+// EncodeCursor encodes k into an opaque, URL safe cursor token.
+func EncodeCursor(k Keyset) (string, error) {
+	if len(k.Columns) != len(k.Values) {
+		return "", fmt.Errorf("keyset has %d columns but %d values", len(k.Columns), len(k.Values))
+	}
+	b, err := json.Marshal(cursorToken{Columns: k.Columns, Values: k.Values, Desc: k.Desc})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecodeCursor decodes a token created by EncodeCursor. The token must be
+// for exactly the given columns, so clients cannot inject columns of their
+// own.
+//
+// Integral numbers are decoded as int64, other numbers as float64. All
+// other values, timestamps included, are decoded as their JSON type and
+// left to Postgres to convert.
+func DecodeCursor(token string, columns ...string) (Keyset, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Keyset{}, fmt.Errorf("invalid cursor: %v", err)
+	}
+
+	var c cursorToken
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&c); err != nil {
+		return Keyset{}, fmt.Errorf("invalid cursor: %v", err)
+	}
+
+	if len(c.Columns) != len(columns) || len(c.Values) != len(columns) {
+		return Keyset{}, fmt.Errorf("invalid cursor: expected %d columns", len(columns))
+	}
+	for i, column := range columns {
+		if c.Columns[i] != column {
+			return Keyset{}, fmt.Errorf("invalid cursor: unexpected column %q", c.Columns[i])
+		}
+	}
+
+	for i, v := range c.Values {
+		n, ok := v.(json.Number)
+		if !ok {
+			continue
+		}
+		if iv, err := n.Int64(); err == nil {
+			c.Values[i] = iv
+		} else if fv, err := n.Float64(); err == nil {
+			c.Values[i] = fv
+		} else {
+			return Keyset{}, fmt.Errorf("invalid cursor: %v", err)
+		}
+	}
+	return Keyset{Columns: c.Columns, Values: c.Values, Desc: c.Desc}, nil
+}
@@ -0,0 +1,70 @@
+package sqrl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyset(t *testing.T) {
+	k := Keyset{Columns: []string{"created_at", "id"}, Values: []interface{}{"2020-05-01", 42}}
+	sql, args, err := Select("*").From("posts").Where(k).OrderBy(k.OrderBys()...).Limit(20).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM posts WHERE (created_at, id) > (?,?) ORDER BY created_at ASC, id ASC LIMIT 20", sql)
+	assert.Equal(t, []interface{}{"2020-05-01", 42}, args)
+
+	k = Keyset{Columns: []string{"id"}, Values: []interface{}{42}, Desc: true}
+	sql, args, err = k.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "id < ?", sql)
+	assert.Equal(t, []interface{}{42}, args)
+	assert.Equal(t, []string{"id DESC"}, k.OrderBys())
+}
+
+func TestKeysetErrors(t *testing.T) {
+	_, _, err := Keyset{}.ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Keyset{Columns: []string{"a", "b"}, Values: []interface{}{1}}.ToSql()
+	assert.Error(t, err)
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	ts := time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+	token, err := EncodeCursor(Keyset{
+		Columns: []string{"created_at", "id", "score"},
+		Values:  []interface{}{ts, 42, 1.5},
+		Desc:    true,
+	})
+	assert.NoError(t, err)
+	assert.NotContains(t, token, "=")
+
+	k, err := DecodeCursor(token, "created_at", "id", "score")
+	assert.NoError(t, err)
+	assert.Equal(t, Keyset{
+		Columns: []string{"created_at", "id", "score"},
+		Values:  []interface{}{"2020-05-01T10:00:00Z", int64(42), 1.5},
+		Desc:    true,
+	}, k)
+}
+
+func TestDecodeCursorErrors(t *testing.T) {
+	token, err := EncodeCursor(Keyset{Columns: []string{"id"}, Values: []interface{}{1}})
+	assert.NoError(t, err)
+
+	_, err = DecodeCursor(token, "name")
+	assert.EqualError(t, err, `invalid cursor: unexpected column "id"`)
+
+	_, err = DecodeCursor(token, "id", "name")
+	assert.EqualError(t, err, "invalid cursor: expected 2 columns")
+
+	_, err = DecodeCursor("!!", "id")
+	assert.Error(t, err)
+
+	_, err = DecodeCursor("bm90IGpzb24", "id")
+	assert.Error(t, err)
+
+	_, err = EncodeCursor(Keyset{Columns: []string{"id"}})
+	assert.Error(t, err)
+}
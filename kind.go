@@ -0,0 +1,311 @@
+package sqrl
+
+import (
+	"strings"
+)
+
+// StatementKind is the kind of statement a Sqlizer builds.
+type StatementKind int
+
+const (
+	// KindOther is any statement not built by one of the sqrl builders.
+	KindOther StatementKind = iota
+	// KindSelect is a SELECT statement.
+	KindSelect
+	// KindInsert is an INSERT statement.
+	KindInsert
+	// KindUpdate is an UPDATE statement.
+	KindUpdate
+	// KindDelete is a DELETE statement.
+	KindDelete
+	// KindDDL is a schema changing or maintenance statement, e.g. CREATE
+	// TABLE, DROP INDEX or VACUUM.
+	KindDDL
+)
+
+// String returns the lower case name of k, e.g. "select".
+func (k StatementKind) String() string {
+	switch k {
+	case KindSelect:
+		return "select"
+	case KindInsert:
+		return "insert"
+	case KindUpdate:
+		return "update"
+	case KindDelete:
+		return "delete"
+	case KindDDL:
+		return "ddl"
+	}
+	return "other"
+}
+
+// Kind returns the kind of statement s builds. It is computed from the
+// builder type, s is never built.
+func Kind(s Sqlizer) StatementKind {
+	switch s := s.(type) {
+	case *SelectBuilder:
+		return KindSelect
+	case *InsertBuilder:
+		return KindInsert
+	case *UpdateBuilder:
+		return KindUpdate
+	case *DeleteBuilder:
+		return KindDelete
+	case boundTemplate:
+		return Kind(s.t.s)
+	case simpleProtocolQuery:
+		return Kind(s.s)
+	case *CreateTableBuilder, *AlterTableBuilder, *DropBuilder,
+		*CreateMaterializedViewBuilder, *RefreshMaterializedViewBuilder,
+		*CreatePolicyBuilder, *AlterPolicyBuilder, *DropPolicyBuilder,
+		*VacuumBuilder, *AnalyzeBuilder, *ReindexBuilder, *ClusterBuilder:
+		return KindDDL
+	}
+	return KindOther
+}
+
+// Tables returns the tables referenced by s, main table first, without
+// duplicates. It is computed from the builder state, so tables used in
+// subqueries passed as Sqlizers (FromSelect, Subquery, Expr args, Where
+// maps, UNIONs, INSERT ... SELECT) are included, while tables mentioned only
+// in raw SQL strings other than FROM, JOIN and USING clauses are not.
+//
+// Aliases are stripped, e.g. From("users u") reports "users".
+//
+// Ex:
+//     Tables(Select("*").From("users u").Join("emails e ON e.user_id = u.id"))
+//     == []string{"users", "emails"}
+func Tables(s Sqlizer) []string {
+	c := &tableCollector{seen: map[string]bool{}}
+	c.walk(s)
+	return c.tables
+}
+
+// tableCollector walks builders and expressions collecting table names.
+type tableCollector struct {
+	seen   map[string]bool
+	tables []string
+}
+
+func (c *tableCollector) add(table string) {
+	table = firstWord(table)
+	if table == "" || strings.HasPrefix(table, "(") || c.seen[table] {
+		return
+	}
+	c.seen[table] = true
+	c.tables = append(c.tables, table)
+}
+
+func (c *tableCollector) walk(v interface{}) {
+	switch s := v.(type) {
+	case *SelectBuilder:
+		c.walkExprs(s.prefixes)
+		c.walkFroms(s.fromParts)
+		for _, j := range s.joins {
+			c.walkJoin(j)
+		}
+		c.walkAll(s.columns)
+		c.walkAll(s.whereParts)
+		c.walkAll(s.havingParts)
+		c.walkAll(s.union)
+		c.walkAll(s.unionAll)
+		c.walkExprs(s.suffixes)
+	case *InsertBuilder:
+		c.walkExprs(s.prefixes)
+		c.add(s.into)
+		for _, row := range s.values {
+			c.walkArgs(row)
+		}
+		if s.iselect != nil {
+			c.walk(s.iselect)
+		}
+		c.walkAll(s.returning)
+		c.walkExprs(s.suffixes)
+	case *UpdateBuilder:
+		c.walkExprs(s.prefixes)
+		c.add(s.table)
+		c.walkFroms(s.fromParts)
+		for _, set := range s.setClauses {
+			c.walk(set.value)
+		}
+		c.walkAll(s.whereParts)
+		c.walkAll(s.returning)
+		c.walkExprs(s.suffixes)
+	case *DeleteBuilder:
+		c.walkExprs(s.prefixes)
+		c.add(s.from)
+		for _, j := range s.joins {
+			c.add(joinTable(j))
+		}
+		c.walkFroms(s.usingParts)
+		c.walkAll(s.whereParts)
+		c.walkAll(s.returning)
+		c.walkExprs(s.suffixes)
+	case *CreateTableBuilder:
+		c.add(s.table)
+		c.add(s.partitionOf)
+		c.walkExprs(s.suffixes)
+	case *AlterTableBuilder:
+		c.add(s.table)
+	case *DropBuilder:
+		if s.kind != "INDEX" {
+			for _, name := range s.names {
+				c.add(name)
+			}
+		}
+	case *CreateMaterializedViewBuilder:
+		c.add(s.name)
+		if s.query != nil {
+			c.walk(s.query)
+		}
+	case *RefreshMaterializedViewBuilder:
+		c.add(s.name)
+	case *VacuumBuilder:
+		for _, table := range s.tables {
+			c.add(table)
+		}
+	case *AnalyzeBuilder:
+		for _, table := range s.tables {
+			c.add(table)
+		}
+	case *ReindexBuilder:
+		if s.kind == "TABLE" {
+			c.add(s.name)
+		}
+	case *ClusterBuilder:
+		c.add(s.table)
+	case *CreatePolicyBuilder:
+		c.add(s.table)
+	case *AlterPolicyBuilder:
+		c.add(s.table)
+	case *DropPolicyBuilder:
+		c.add(s.table)
+	case *part:
+		c.walk(s.pred)
+		c.walkArgs(s.args)
+	case *wherePart:
+		c.walk(s.pred)
+		c.walkArgs(s.args)
+	case *unionPart:
+		c.walk(s.expr)
+	case boundTemplate:
+		c.walk(s.t.s)
+	case simpleProtocolQuery:
+		c.walk(s.s)
+	case subquery:
+		c.walk(s.sb)
+	case aliasExpr:
+		c.walk(s.expr)
+	case lateralExpr:
+		c.walk(s.expr)
+	case *TableFunction:
+		c.walkArgs(s.args)
+	case functionJoin:
+		c.walk(&s.fn)
+		c.walkArgs(s.args)
+	case expr:
+		c.walkArgs(s.args)
+	case *fn:
+		c.walkArgs(s.fargs)
+	case op:
+		c.walk(s.left)
+		c.walk(s.right)
+	case *Group:
+		c.walkAll(s.parts)
+	case And:
+		c.walkAll(s)
+	case Or:
+		c.walkAll(s)
+	case Eq:
+		c.walkMap(s)
+	case NotEq:
+		c.walkMap(s)
+	case EqNotZero:
+		c.walkMap(s)
+	case EqNotNil:
+		c.walkMap(s)
+	case Lt:
+		c.walkMap(s)
+	case LtOrEq:
+		c.walkMap(s)
+	case Gt:
+		c.walkMap(s)
+	case GtOrEq:
+		c.walkMap(s)
+	case OrderedEq:
+		for _, p := range s {
+			c.walk(p.Value)
+		}
+	}
+}
+
+func (c *tableCollector) walkAll(parts []Sqlizer) {
+	for _, p := range parts {
+		c.walk(p)
+	}
+}
+
+func (c *tableCollector) walkExprs(es exprs) {
+	for _, e := range es {
+		c.walk(e)
+	}
+}
+
+func (c *tableCollector) walkArgs(args []interface{}) {
+	for _, arg := range args {
+		c.walk(arg)
+	}
+}
+
+func (c *tableCollector) walkMap(m map[string]interface{}) {
+	for _, v := range m {
+		c.walk(v)
+	}
+}
+
+// walkFroms collects tables from FROM or USING parts, which are either
+// table names, possibly comma separated, or subqueries.
+func (c *tableCollector) walkFroms(parts []Sqlizer) {
+	for _, p := range parts {
+		if p, ok := p.(*part); ok {
+			if from, ok := p.pred.(string); ok {
+				for _, table := range strings.Split(from, ",") {
+					c.add(table)
+				}
+				continue
+			}
+		}
+		c.walk(p)
+	}
+}
+
+func (c *tableCollector) walkJoin(j Sqlizer) {
+	if p, ok := j.(*part); ok {
+		if join, ok := p.pred.(string); ok {
+			c.add(joinTable(join))
+			c.walkArgs(p.args)
+			return
+		}
+	}
+	c.walk(j)
+}
+
+// joinTable returns the table name of a JOIN clause like
+// "LEFT JOIN emails e ON ...", or "" if it joins a subquery.
+func joinTable(join string) string {
+	fields := strings.Fields(join)
+	for i, f := range fields {
+		if !strings.EqualFold(f, "JOIN") {
+			continue
+		}
+		if i+1 < len(fields) && strings.EqualFold(fields[i+1], "LATERAL") {
+			i++
+		}
+		if i+1 < len(fields) && !strings.HasPrefix(fields[i+1], "(") {
+			return fields[i+1]
+		}
+		return ""
+	}
+	return ""
+}
@@ -0,0 +1,81 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKind(t *testing.T) {
+	assert.Equal(t, KindSelect, Kind(Select("*").From("users")))
+	assert.Equal(t, KindInsert, Kind(Insert("users")))
+	assert.Equal(t, KindUpdate, Kind(Update("users")))
+	assert.Equal(t, KindDelete, Kind(Delete("users")))
+	assert.Equal(t, KindDDL, Kind(DropTable("users")))
+	assert.Equal(t, KindDDL, Kind(Vacuum("users")))
+	assert.Equal(t, KindDDL, Kind(Analyze("users")))
+	assert.Equal(t, KindDDL, Kind(ReindexTable("users")))
+	assert.Equal(t, KindDDL, Kind(Cluster("users", "users_pkey")))
+	assert.Equal(t, KindOther, Kind(Expr("SELECT 1")))
+
+	assert.Equal(t, "delete", KindDelete.String())
+	assert.Equal(t, "other", KindOther.String())
+}
+
+func TestTablesSelect(t *testing.T) {
+	sub := Select("id").From("teams").Where("name = ?", "a")
+	b := Select("*").
+		From("users u").
+		Join("emails e ON e.user_id = u.id").
+		LeftJoin("profiles p USING (id)").
+		Where(Eq{"team_id": Subquery(sub)}).
+		Where(Expr("EXISTS (?)", Select("1").From("bans").Where("bans.user_id = u.id"))).
+		Union(Select("*").From("admins"))
+
+	assert.Equal(t, []string{"users", "emails", "profiles", "teams", "bans", "admins"}, Tables(b))
+}
+
+func TestTablesFromSelect(t *testing.T) {
+	b := Select("*").FromSelect(Select("*").From("orders").Join("users ON users.id = orders.user_id"), "o")
+	assert.Equal(t, []string{"orders", "users"}, Tables(b))
+}
+
+func TestTablesDuplicates(t *testing.T) {
+	b := Select("*").From("users a").Join("users b ON a.parent_id = b.id")
+	assert.Equal(t, []string{"users"}, Tables(b))
+}
+
+func TestTablesInsertUpdateDelete(t *testing.T) {
+	ins := Insert("archive").Select(Select("*").From("orders"))
+	assert.Equal(t, []string{"archive", "orders"}, Tables(ins))
+
+	upd := Update("orders").From("users").Set("total", Subquery(Select("sum(amount)").From("items")))
+	assert.Equal(t, []string{"orders", "users", "items"}, Tables(upd))
+
+	del := Delete("orders").Using("users").Where(Or{Eq{"a": 1}, Eq{"id": Subquery(Select("id").From("stale"))}})
+	assert.Equal(t, []string{"orders", "users", "stale"}, Tables(del))
+}
+
+func TestTablesPrefixesSuffixes(t *testing.T) {
+	active := Select("id").From("accounts").Where("active")
+	sel := Select("*").From("users").
+		PrefixExpr(Expr("WITH a AS (?)", active)).
+		SuffixExpr(Expr("UNION SELECT * FROM (?) x", Select("*").From("admins")))
+	assert.Equal(t, []string{"accounts", "users", "admins"}, Tables(sel))
+
+	ins := Insert("archive").PrefixExpr(Expr("WITH a AS (?)", active)).Values(1)
+	assert.Equal(t, []string{"accounts", "archive"}, Tables(ins))
+
+	upd := Update("orders").PrefixExpr(Expr("WITH a AS (?)", active)).Set("x", 1)
+	assert.Equal(t, []string{"accounts", "orders"}, Tables(upd))
+
+	del := Delete("orders").PrefixExpr(Expr("WITH a AS (?)", active)).
+		SuffixExpr(Expr("RETURNING (?)", Select("1").From("audit")))
+	assert.Equal(t, []string{"accounts", "orders", "audit"}, Tables(del))
+}
+
+func TestTablesDDL(t *testing.T) {
+	assert.Equal(t, []string{"a", "b"}, Tables(DropTable("a", "b")))
+	assert.Empty(t, Tables(DropIndex("a_idx")))
+	assert.Empty(t, Tables(Expr("SELECT 1")))
+}
@@ -0,0 +1,186 @@
+package sqrl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Lint rules reported in Warnings.
+const (
+	LintCrossJoin       = "cross-join"
+	LintSelectStar      = "select-star"
+	LintUnbounded       = "unbounded"
+	LintLeadingWildcard = "leading-wildcard"
+)
+
+// Warning is a risky construct found by Lint.
+type Warning struct {
+	// Rule is the lint rule, e.g. LintSelectStar.
+	Rule    string
+	Message string
+}
+
+func (w Warning) String() string {
+	return w.Rule + ": " + w.Message
+}
+
+// Linter checks statements for risky constructs:
+//   - joins without a join condition and multiple FROM tables without a
+//     WHERE clause, which produce cross products
+//   - SELECT * and SELECT t.*, which break on schema changes and fetch
+//     more than needed
+//   - queries without LIMIT on one of LargeTables
+//   - LIKE and ILIKE patterns starting with a wildcard, which can not use
+//     a btree index
+type Linter struct {
+	// LargeTables are tables which must not be queried without LIMIT.
+	LargeTables []string
+}
+
+// Lint checks s for risky constructs with a Linter without large tables.
+// Lint is meant for tests, e.g. over all templates of a TemplateRegistry.
+func Lint(s Sqlizer) []Warning {
+	return Linter{}.Lint(s)
+}
+
+// Lint checks s for risky constructs.
+//
+// Ex:
+//     linter := sqrl.Linter{LargeTables: []string{"events"}}
+//     for _, w := range linter.Lint(sqrl.Select("*").From("events")) {
+//         t.Error(w)
+//     }
+func (l Linter) Lint(s Sqlizer) []Warning {
+	var warnings []Warning
+	if t, ok := s.(boundTemplate); ok {
+		s = t.t.s
+	}
+	if b, ok := s.(*SelectBuilder); ok {
+		warnings = l.lintSelect(b, warnings)
+	}
+	return lintLike(s, warnings)
+}
+
+// LintTemplates checks all templates of r and returns their warnings by
+// template name. Templates without warnings are left out.
+func (l Linter) LintTemplates(r *TemplateRegistry) map[string][]Warning {
+	warnings := make(map[string][]Warning)
+	for _, t := range r.Templates() {
+		if w := l.Lint(boundTemplate{t: t, args: t.args}); len(w) > 0 {
+			warnings[t.Name] = w
+		}
+	}
+	return warnings
+}
+
+func (l Linter) lintSelect(b *SelectBuilder, warnings []Warning) []Warning {
+	for _, column := range b.columns {
+		sql, _, err := column.ToSql()
+		if err != nil {
+			continue
+		}
+		if sql = strings.TrimSpace(sql); sql == "*" || strings.HasSuffix(sql, ".*") {
+			warnings = append(warnings, Warning{LintSelectStar, fmt.Sprintf("query selects %s", sql)})
+		}
+	}
+
+	var from []string
+	for _, p := range b.fromParts {
+		switch p := p.(type) {
+		case *part:
+			if table, ok := p.pred.(string); ok {
+				for _, t := range strings.Split(table, ",") {
+					from = append(from, strings.TrimSpace(t))
+				}
+				continue
+			}
+			from = append(from, fmt.Sprint(p.pred))
+		case aliasExpr:
+			from = append(from, p.alias)
+			warnings = l.lintSubquery(p.expr, warnings)
+		case lateralExpr:
+			warnings = l.lintSubquery(p.expr, warnings)
+		}
+	}
+	if len(from) > 1 && len(b.whereParts) == 0 {
+		warnings = append(warnings, Warning{LintCrossJoin, fmt.Sprintf("FROM %s has no WHERE clause", strings.Join(from, ", "))})
+	}
+
+	for _, join := range b.joins {
+		sql, _, err := join.ToSql()
+		if err != nil {
+			continue
+		}
+		upper := " " + strings.ToUpper(sql) + " "
+		switch {
+		case strings.Contains(upper, " CROSS JOIN "):
+			warnings = append(warnings, Warning{LintCrossJoin, sql})
+		case strings.Contains(upper, " NATURAL "), strings.Contains(upper, " LATERAL "):
+		case !strings.Contains(upper, " ON ") && !strings.Contains(upper, " USING "):
+			warnings = append(warnings, Warning{LintCrossJoin, fmt.Sprintf("%s has no join condition", sql)})
+		}
+	}
+
+	if !b.limitValid && len(l.LargeTables) > 0 {
+		for _, table := range Tables(b) {
+			for _, large := range l.LargeTables {
+				if table == large {
+					warnings = append(warnings, Warning{LintUnbounded, fmt.Sprintf("query on large table %s has no LIMIT", table)})
+				}
+			}
+		}
+	}
+
+	for _, u := range b.union {
+		warnings = l.lintSubquery(u, warnings)
+	}
+	for _, u := range b.unionAll {
+		warnings = l.lintSubquery(u, warnings)
+	}
+	return warnings
+}
+
+func (l Linter) lintSubquery(s Sqlizer, warnings []Warning) []Warning {
+	switch s := s.(type) {
+	case *SelectBuilder:
+		return l.lintSelect(s, warnings)
+	case subquery:
+		return l.lintSelect(s.sb, warnings)
+	case *unionPart:
+		return l.lintSubquery(s.expr, warnings)
+	}
+	return warnings
+}
+
+var (
+	likeLiteralRegexp = regexp.MustCompile(`(?i)\bi?like\s+'[%_]`)
+	likeArgRegexp     = regexp.MustCompile(`(?i)\bi?like\s*$`)
+)
+
+// lintLike flags LIKE patterns with a leading wildcard, both inline and
+// bound as args.
+func lintLike(s Sqlizer, warnings []Warning) []Warning {
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return warnings
+	}
+
+	if m := likeLiteralRegexp.FindString(sql); len(m) > 0 {
+		warnings = append(warnings, Warning{LintLeadingWildcard, fmt.Sprintf("pattern %s...' starts with a wildcard", m)})
+	}
+
+	for i, match := range placeholderRegexp.FindAllStringIndex(sql, -1) {
+		if i >= len(args) {
+			break
+		}
+		pattern, ok := args[i].(string)
+		if !ok || !strings.HasPrefix(pattern, "%") && !strings.HasPrefix(pattern, "_") {
+			continue
+		}
+		if likeArgRegexp.MatchString(sql[:match[0]]) {
+			warnings = append(warnings, Warning{LintLeadingWildcard, fmt.Sprintf("pattern %q starts with a wildcard", pattern)})
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,76 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintClean(t *testing.T) {
+	b := Select("u.id", "p.title").
+		From("users u").
+		Join("posts p ON p.user_id = u.id").
+		Where("u.name LIKE ?", "moe%").
+		Limit(10)
+	assert.Empty(t, Linter{LargeTables: []string{"posts"}}.Lint(b))
+}
+
+func TestLintCrossJoin(t *testing.T) {
+	assert.Equal(t, []Warning{
+		{LintCrossJoin, "FROM users, posts has no WHERE clause"},
+		{LintCrossJoin, "JOIN teams has no join condition"},
+		{LintCrossJoin, "CROSS JOIN tags"},
+	}, Lint(Select("id").From("users, posts").Join("teams").JoinClause("CROSS JOIN tags")))
+
+	assert.Empty(t, Lint(Select("id").From("users", "posts").Where("posts.user_id = users.id")))
+	assert.Empty(t, Lint(Select("id").From("users").JoinClause("NATURAL JOIN profiles").LeftJoin("teams USING (team_id)")))
+}
+
+func TestLintSelectStar(t *testing.T) {
+	assert.Equal(t, []Warning{
+		{LintSelectStar, "query selects *"},
+		{LintSelectStar, "query selects u.*"},
+	}, Lint(Select("*", "u.*").From("users u")))
+
+	assert.Empty(t, Lint(Select("count(*)").From("users")))
+}
+
+func TestLintUnbounded(t *testing.T) {
+	linter := Linter{LargeTables: []string{"events"}}
+
+	assert.Equal(t, []Warning{
+		{LintUnbounded, "query on large table events has no LIMIT"},
+	}, linter.Lint(Select("e.id").From("users u").Join("events e ON e.user_id = u.id")))
+
+	assert.Empty(t, linter.Lint(Select("id").From("events").Limit(100)))
+	assert.Empty(t, Lint(Select("id").From("events")))
+}
+
+func TestLintLeadingWildcard(t *testing.T) {
+	assert.Equal(t, []Warning{
+		{LintLeadingWildcard, "pattern like '%...' starts with a wildcard"},
+		{LintLeadingWildcard, `pattern "%moe" starts with a wildcard`},
+	}, Lint(Select("id").From("users").Where("email like '%@example.com'").Where("name ILIKE ?", "%moe")))
+
+	assert.Equal(t, []Warning{
+		{LintLeadingWildcard, `pattern "_oe" starts with a wildcard`},
+	}, Lint(Delete("users").Where("id = ? AND name LIKE ?", "%", "_oe")))
+}
+
+func TestLintNested(t *testing.T) {
+	sub := Select("*").From("posts")
+	assert.Equal(t, []Warning{
+		{LintSelectStar, "query selects *"},
+		{LintSelectStar, "query selects *"},
+	}, Lint(Select("id").FromSelect(sub, "p").Union(Select("*").From("drafts"))))
+}
+
+func TestLintTemplates(t *testing.T) {
+	r := NewTemplateRegistry()
+	r.MustRegister("users_by_name", Select("*").From("users").Where("name LIKE ?", Param("name")), "name")
+	r.MustRegister("user_by_id", Select("id").From("users").Where(Eq{"id": Param("id")}), "id")
+
+	assert.Equal(t, map[string][]Warning{
+		"users_by_name": {{LintSelectStar, "query selects *"}},
+	}, Linter{}.LintTemplates(r))
+}
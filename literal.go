@@ -0,0 +1,182 @@
+package sqrl
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// literal renders v as a SQL literal for statements which can not use bound
+// parameters, e.g. DDL. Literals are safe to be placed anywhere a bound
+// parameter could be: negative numbers are wrapped in parentheses, so they
+// can not form a -- comment with a preceding minus, and strings containing
+// backslashes are written as escape strings, which are read the same
+// regardless of standard_conforming_strings.
+func literal(v interface{}) (string, error) {
+	if valuer, ok := v.(driver.Valuer); ok {
+		var err error
+		if v, err = valuer.Value(); err != nil {
+			return "", err
+		}
+	}
+
+	switch val := v.(type) {
+	case nil:
+		return "NULL", nil
+	case Sqlizer:
+		sql, args, err := val.ToSql()
+		if err != nil {
+			return "", err
+		}
+		if len(args) > 0 {
+			return "", fmt.Errorf("expression %q can not be used as a literal as it has bound args", sql)
+		}
+		return sql, nil
+	case string:
+		return quoteString(val)
+	case []byte:
+		return `E'\\x` + hex.EncodeToString(val) + `'::bytea`, nil
+	case bool:
+		if val {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case int:
+		return signed(strconv.FormatInt(int64(val), 10)), nil
+	case int8:
+		return signed(strconv.FormatInt(int64(val), 10)), nil
+	case int16:
+		return signed(strconv.FormatInt(int64(val), 10)), nil
+	case int32:
+		return signed(strconv.FormatInt(int64(val), 10)), nil
+	case int64:
+		return signed(strconv.FormatInt(val, 10)), nil
+	case uint:
+		return strconv.FormatUint(uint64(val), 10), nil
+	case uint8:
+		return strconv.FormatUint(uint64(val), 10), nil
+	case uint16:
+		return strconv.FormatUint(uint64(val), 10), nil
+	case uint32:
+		return strconv.FormatUint(uint64(val), 10), nil
+	case uint64:
+		return strconv.FormatUint(val, 10), nil
+	case float32:
+		return float(float64(val), 32), nil
+	case float64:
+		return float(val, 64), nil
+	case time.Time:
+		return quoteString(val.Format(time.RFC3339Nano))
+	default:
+		return "", fmt.Errorf("can not render value of type %T as a literal", v)
+	}
+}
+
+// escapedLiteral renders v like literal for the output of a Sqlizer, which is
+// passed through ReplacePlaceholders: question marks in the literal are
+// escaped as ??, so they are not replaced by placeholders. Sqlizers are
+// rendered as they are, as their output is escaped already.
+func escapedLiteral(v interface{}) (string, error) {
+	if _, ok := v.(driver.Valuer); !ok {
+		if _, ok := v.(Sqlizer); ok {
+			return literal(v)
+		}
+	}
+	lit, err := literal(v)
+	return strings.Replace(lit, "?", "??", -1), err
+}
+
+// signed wraps the formatted number n in parentheses if it is negative.
+func signed(n string) string {
+	if strings.HasPrefix(n, "-") {
+		return "(" + n + ")"
+	}
+	return n
+}
+
+// float renders f, quoting NaN and infinities which are no numeric literals.
+func float(f float64, bitSize int) string {
+	switch {
+	case math.IsNaN(f):
+		return "'NaN'"
+	case math.IsInf(f, 1):
+		return "'Infinity'"
+	case math.IsInf(f, -1):
+		return "'-Infinity'"
+	}
+	return signed(strconv.FormatFloat(f, 'g', -1, bitSize))
+}
+
+// quoteString quotes s as a SQL string literal. Strings containing
+// backslashes are written as escape strings with the backslashes escaped.
+// Postgres does not support NUL bytes in strings.
+func quoteString(s string) (string, error) {
+	if strings.IndexByte(s, 0) >= 0 {
+		return "", fmt.Errorf("string %q can not be used as a literal as it contains a NUL byte", s)
+	}
+	s = strings.Replace(s, "'", "''", -1)
+	if strings.Contains(s, `\`) {
+		return `E'` + strings.Replace(s, `\`, `\\`, -1) + "'", nil
+	}
+	return "'" + s + "'", nil
+}
+
+// inline replaces the placeholders of sql with the literal representation of
+// the corresponding args. All args must be used.
+func inline(sql string, args []interface{}) (string, error) {
+	n := 0
+	sql, err := replacePlaceholders(sql, func(buf *bytes.Buffer, i int) error {
+		if i > len(args) {
+			return fmt.Errorf("not enough args for %d placeholders", i)
+		}
+		lit, err := literal(args[i-1])
+		if err != nil {
+			return err
+		}
+		buf.WriteString(lit)
+		n = i
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if n != len(args) {
+		return "", fmt.Errorf("%d args given for %d placeholders", len(args), n)
+	}
+	return sql, nil
+}
+
+// toSqlInlined builds s, which must use Question placeholders, with its args
+// inlined as literals.
+func toSqlInlined(s Sqlizer) (string, error) {
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return "", err
+	}
+	return inline(sql, args)
+}
+
+// quoteIdent quotes name as a SQL identifier. Each part of a qualified
+// name like "public.users" is quoted separately.
+func quoteIdent(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = `"` + strings.Replace(part, `"`, `""`, -1) + `"`
+	}
+	return strings.Join(parts, ".")
+}
+
+// quoteIdents quotes each of names as a SQL identifier and joins them with
+// commas.
+func quoteIdents(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quoteIdent(name)
+	}
+	return strings.Join(quoted, ", ")
+}
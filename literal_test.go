@@ -0,0 +1,69 @@
+package sqrl
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLiteral(t *testing.T) {
+	cases := []struct {
+		v    interface{}
+		want string
+	}{
+		{nil, "NULL"},
+		{true, "TRUE"},
+		{1, "1"},
+		{-1, "(-1)"},
+		{int64(-42), "(-42)"},
+		{uint8(7), "7"},
+		{1.5, "1.5"},
+		{-1.5, "(-1.5)"},
+		{float32(-0.25), "(-0.25)"},
+		{math.NaN(), "'NaN'"},
+		{math.Inf(1), "'Infinity'"},
+		{float32(math.Inf(-1)), "'-Infinity'"},
+		{"O'Brien", "'O''Brien'"},
+		{`a\'b`, `E'a\\''b'`},
+		{`C:\temp`, `E'C:\\temp'`},
+		{[]byte{0xde, 0xad}, `E'\\xdead'::bytea`},
+		{Expr("now()"), "now()"},
+	}
+	for _, c := range cases {
+		lit, err := literal(c.v)
+		if assert.NoError(t, err, "%#v", c.v) {
+			assert.Equal(t, c.want, lit, "%#v", c.v)
+		}
+	}
+
+	_, err := literal("a\x00b")
+	assert.EqualError(t, err, `string "a\x00b" can not be used as a literal as it contains a NUL byte`)
+
+	_, err = literal(Expr("now() - ?", 1))
+	assert.Error(t, err)
+}
+
+func TestInline(t *testing.T) {
+	sql, err := inline("SELECT * FROM t WHERE b-? = 0 AND c = ?", []interface{}{-1, "x"})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE b-(-1) = 0 AND c = 'x'", sql)
+
+	sql, err = inline("SELECT ? AS x", []interface{}{`\'; DROP TABLE t; --`})
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT E'\\''; DROP TABLE t; --' AS x`, sql)
+
+	_, err = inline("y = ?", []interface{}{2, 3})
+	assert.EqualError(t, err, "2 args given for 1 placeholders")
+
+	_, err = inline("y = ? AND z = ?", []interface{}{2})
+	assert.EqualError(t, err, "not enough args for 2 placeholders")
+
+	_, err = inline("y = ?", []interface{}{"a\x00"})
+	assert.Error(t, err)
+}
+
+func TestToSqlInlinedSurplusArgs(t *testing.T) {
+	_, err := Select("*").From("t").Where("y = ?", 2, 3).ToSqlInlined()
+	assert.EqualError(t, err, "2 args given for 1 placeholders")
+}
@@ -0,0 +1,149 @@
+package sqrl
+
+import (
+	"context"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgx/v4"
+	"sync"
+	"time"
+)
+
+// DefaultLoaderWait is the time a Loader waits for further keys before
+// running a batch.
+const DefaultLoaderWait = 2 * time.Millisecond
+
+// Loader coalesces concurrent single row lookups by key into one query per
+// batch, matching all keys of the batch with keyColumn = ANY(?), and hands
+// the rows back to the respective callers.
+//
+// Ex:
+//     users := NewLoader(pool,
+//         func() *SelectBuilder { return Select("id", "name").From("users") },
+//         "id",
+//         func(rows pgx.Rows) (id int64, u User, err error) {
+//             err = rows.Scan(&u.ID, &u.Name)
+//             return u.ID, u, err
+//         })
+//
+//     user, err := users.Load(ctx, 42)
+type Loader[K comparable, V interface{}] struct {
+	pool      instapgxpool.Pool
+	query     func() *SelectBuilder
+	keyColumn string
+	scan      func(rows pgx.Rows) (K, V, error)
+	wait      time.Duration
+	maxBatch  int
+
+	mu    sync.Mutex
+	batch *loaderBatch[K, V]
+}
+
+type loaderBatch[K comparable, V interface{}] struct {
+	keys   []K
+	seen   map[K]bool
+	done   chan struct{}
+	values map[K]V
+	err    error
+}
+
+// NewLoader creates a Loader running the query built by query using pool.
+// scan is called for every row and returns its key along with the value.
+func NewLoader[K comparable, V interface{}](
+	pool instapgxpool.Pool,
+	query func() *SelectBuilder,
+	keyColumn string,
+	scan func(rows pgx.Rows) (K, V, error),
+) *Loader[K, V] {
+	return &Loader[K, V]{
+		pool:      pool,
+		query:     query,
+		keyColumn: keyColumn,
+		scan:      scan,
+		wait:      DefaultLoaderWait,
+	}
+}
+
+// Wait sets the time to wait for further keys before running a batch.
+func (l *Loader[K, V]) Wait(wait time.Duration) *Loader[K, V] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.wait = wait
+	return l
+}
+
+// MaxBatch sets the maximum number of keys of a batch. A full batch is run
+// without waiting further. Zero means no limit.
+func (l *Loader[K, V]) MaxBatch(maxBatch int) *Loader[K, V] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxBatch = maxBatch
+	return l
+}
+
+// Load returns the value for key, or pgx.ErrNoRows if there is no row with
+// that key.
+//
+// The batch query is shared by all Loads of the batch and runs detached from
+// their contexts: it does not run in a transaction carried by ctx, nor count
+// against its query budget, and canceling ctx only stops that Load from
+// waiting.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	b := l.batch
+	if b == nil {
+		b = &loaderBatch[K, V]{seen: make(map[K]bool), done: make(chan struct{})}
+		l.batch = b
+		time.AfterFunc(l.wait, func() { l.run(b) })
+	}
+	if !b.seen[key] {
+		b.seen[key] = true
+		b.keys = append(b.keys, key)
+	}
+	full := l.maxBatch > 0 && len(b.keys) >= l.maxBatch
+	l.mu.Unlock()
+
+	if full {
+		go l.run(b)
+	}
+
+	var zero V
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+
+	if b.err != nil {
+		return zero, b.err
+	}
+	value, ok := b.values[key]
+	if !ok {
+		return zero, pgx.ErrNoRows
+	}
+	return value, nil
+}
+
+func (l *Loader[K, V]) run(b *loaderBatch[K, V]) {
+	l.mu.Lock()
+	if l.batch != b {
+		// Already run because it was full.
+		l.mu.Unlock()
+		return
+	}
+	l.batch = nil
+	l.mu.Unlock()
+
+	defer close(b.done)
+
+	values := make(map[K]V, len(b.keys))
+	q := l.query().Where(l.keyColumn+" = ANY(?)", b.keys)
+	b.err = QueryEach(context.Background(), l.pool, q, func(rows pgx.Rows) error {
+		key, value, err := l.scan(rows)
+		if err != nil {
+			return err
+		}
+		values[key] = value
+		return nil
+	})
+	b.values = values
+}
@@ -0,0 +1,110 @@
+package sqrl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newNameLoader(pool *poolStub) *Loader[int64, string] {
+	return NewLoader(pool,
+		func() *SelectBuilder { return Select("id", "name").From("users") },
+		"id",
+		func(rows pgx.Rows) (id int64, name string, err error) {
+			err = rows.Scan(&id, &name)
+			return
+		})
+}
+
+func loadAll(l *Loader[int64, string], keys ...int64) ([]string, []error) {
+	names := make([]string, len(keys))
+	errs := make([]error, len(keys))
+
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func(i int, key int64) {
+			defer wg.Done()
+			names[i], errs[i] = l.Load(context.Background(), key)
+		}(i, key)
+	}
+	wg.Wait()
+	return names, errs
+}
+
+func TestLoader(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{int64(1), "a"}, {int64(3), "c"}}}
+
+	l := newNameLoader(pool).Wait(20 * time.Millisecond)
+	names, errs := loadAll(l, 1, 2, 3, 1)
+
+	assert.Equal(t, []string{"a", "", "c", "a"}, names)
+	assert.Equal(t, []error{nil, pgx.ErrNoRows, nil, nil}, errs)
+
+	assert.Equal(t, []string{"SELECT id, name FROM users WHERE id = ANY(?)"}, pool.stub.sqls)
+	assert.ElementsMatch(t, []int64{1, 2, 3}, pool.stub.args[0][0])
+}
+
+func TestLoaderMaxBatch(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{int64(1), "a"}, {int64(2), "b"}}}
+
+	l := newNameLoader(pool).Wait(time.Hour).MaxBatch(2)
+	names, errs := loadAll(l, 1, 2)
+
+	assert.Equal(t, []string{"a", "b"}, names)
+	assert.Equal(t, []error{nil, nil}, errs)
+}
+
+func TestLoaderError(t *testing.T) {
+	pool := newPoolStub()
+	boom := errors.New("boom")
+	pool.stub.err = boom
+
+	_, errs := loadAll(newNameLoader(pool), 1, 2)
+	assert.True(t, errors.Is(errs[0], boom))
+	assert.True(t, errors.Is(errs[1], boom))
+}
+
+func TestLoaderDetachedContext(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{int64(1), "a"}, {int64(2), "b"}}}
+	l := newNameLoader(pool).Wait(20 * time.Millisecond)
+
+	tx := newPoolStub()
+	ctx, cancel := context.WithCancel(ContextWithTx(WithQueryBudget(context.Background(), 0, nil), &txStub{stub: tx.stub}))
+
+	var wg sync.WaitGroup
+	var err error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, err = l.Load(ctx, 1)
+	}()
+	time.Sleep(5 * time.Millisecond)
+	cancel()
+	names, errs := loadAll(l, 2)
+	wg.Wait()
+
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, []string{"b"}, names)
+	assert.Equal(t, []error{nil}, errs)
+	assert.Len(t, pool.stub.sqls, 1)
+	assert.Empty(t, tx.stub.sqls)
+}
+
+func TestLoaderCanceled(t *testing.T) {
+	l := newNameLoader(newPoolStub()).Wait(time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := l.Load(ctx, 1)
+	assert.Equal(t, context.Canceled, err)
+}
@@ -0,0 +1,413 @@
+package sqrl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgconn"
+	"strings"
+)
+
+// VacuumBuilder builds SQL VACUUM statements. Table and column names are
+// quoted as identifiers.
+//
+// Ex:
+//     Vacuum("events").Full().Analyze()
+//     == `VACUUM (FULL, ANALYZE) "events"`
+type VacuumBuilder struct {
+	StatementBuilderType
+
+	tables     []string
+	columns    []string
+	full       bool
+	freeze     bool
+	analyze    bool
+	verbose    bool
+	skipLocked bool
+}
+
+// NewVacuumBuilder creates new instance of VacuumBuilder
+func NewVacuumBuilder(b StatementBuilderType) *VacuumBuilder {
+	return &VacuumBuilder{StatementBuilderType: b}
+}
+
+// ExecContext builds and Execs the statement using given context.
+func (b *VacuumBuilder) ExecContext(ctx context.Context, pool instapgxpool.Pool) (pgconn.CommandTag, error) {
+	return b.execContext(ctx, pool, b)
+}
+
+// ExecAttached is like ExecContext, using the pool set with RunWithPool.
+func (b *VacuumBuilder) ExecAttached(ctx context.Context) (pgconn.CommandTag, error) {
+	return b.ExecContext(ctx, nil)
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// statement.
+func (b *VacuumBuilder) PlaceholderFormat(f PlaceholderFormat) *VacuumBuilder {
+	b.placeholderFormat = f
+	return b
+}
+
+// ToSql builds the statement into a SQL string and bound args.
+func (b *VacuumBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
+	if len(b.columns) > 0 && !b.analyze {
+		err = fmt.Errorf("vacuum column lists require ANALYZE")
+		return
+	}
+	if len(b.columns) > 0 && len(b.tables) != 1 {
+		err = fmt.Errorf("vacuum column lists require exactly one table")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	sql.WriteString("VACUUM")
+
+	var options []string
+	if b.full {
+		options = append(options, "FULL")
+	}
+	if b.freeze {
+		options = append(options, "FREEZE")
+	}
+	if b.verbose {
+		options = append(options, "VERBOSE")
+	}
+	if b.analyze {
+		options = append(options, "ANALYZE")
+	}
+	if b.skipLocked {
+		options = append(options, "SKIP_LOCKED")
+	}
+	if len(options) > 0 {
+		sql.WriteString(" (")
+		sql.WriteString(strings.Join(options, ", "))
+		sql.WriteString(")")
+	}
+
+	if len(b.tables) > 0 {
+		sql.WriteString(" ")
+		sql.WriteString(quoteIdents(b.tables))
+	}
+
+	if len(b.columns) > 0 {
+		sql.WriteString(" (")
+		sql.WriteString(quoteIdents(b.columns))
+		sql.WriteString(")")
+	}
+
+	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
+	return
+}
+
+// Tables adds tables to be vacuumed. Without tables, the whole database is
+// vacuumed.
+func (b *VacuumBuilder) Tables(tables ...string) *VacuumBuilder {
+	b.tables = append(b.tables, tables...)
+	return b
+}
+
+// Columns restricts ANALYZE to the given columns. Requires Analyze and
+// exactly one table.
+func (b *VacuumBuilder) Columns(columns ...string) *VacuumBuilder {
+	b.columns = append(b.columns, columns...)
+	return b
+}
+
+// Full adds the FULL option, rewriting the tables to reclaim space.
+func (b *VacuumBuilder) Full() *VacuumBuilder {
+	b.full = true
+	return b
+}
+
+// Freeze adds the FREEZE option.
+func (b *VacuumBuilder) Freeze() *VacuumBuilder {
+	b.freeze = true
+	return b
+}
+
+// Analyze adds the ANALYZE option, updating planner statistics as well.
+func (b *VacuumBuilder) Analyze() *VacuumBuilder {
+	b.analyze = true
+	return b
+}
+
+// Verbose adds the VERBOSE option.
+func (b *VacuumBuilder) Verbose() *VacuumBuilder {
+	b.verbose = true
+	return b
+}
+
+// SkipLocked adds the SKIP_LOCKED option, skipping tables which can not be
+// locked immediately.
+func (b *VacuumBuilder) SkipLocked() *VacuumBuilder {
+	b.skipLocked = true
+	return b
+}
+
+// AnalyzeBuilder builds SQL ANALYZE statements. Table and column names are
+// quoted as identifiers.
+//
+// Ex:
+//     Analyze("events", "created_at")
+//     == `ANALYZE "events" ("created_at")`
+type AnalyzeBuilder struct {
+	StatementBuilderType
+
+	tables     []string
+	columns    []string
+	verbose    bool
+	skipLocked bool
+}
+
+// NewAnalyzeBuilder creates new instance of AnalyzeBuilder
+func NewAnalyzeBuilder(b StatementBuilderType) *AnalyzeBuilder {
+	return &AnalyzeBuilder{StatementBuilderType: b}
+}
+
+// ExecContext builds and Execs the statement using given context.
+func (b *AnalyzeBuilder) ExecContext(ctx context.Context, pool instapgxpool.Pool) (pgconn.CommandTag, error) {
+	return b.execContext(ctx, pool, b)
+}
+
+// ExecAttached is like ExecContext, using the pool set with RunWithPool.
+func (b *AnalyzeBuilder) ExecAttached(ctx context.Context) (pgconn.CommandTag, error) {
+	return b.ExecContext(ctx, nil)
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// statement.
+func (b *AnalyzeBuilder) PlaceholderFormat(f PlaceholderFormat) *AnalyzeBuilder {
+	b.placeholderFormat = f
+	return b
+}
+
+// ToSql builds the statement into a SQL string and bound args.
+func (b *AnalyzeBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
+	if len(b.columns) > 0 && len(b.tables) != 1 {
+		err = fmt.Errorf("analyze column lists require exactly one table")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	sql.WriteString("ANALYZE")
+
+	var options []string
+	if b.verbose {
+		options = append(options, "VERBOSE")
+	}
+	if b.skipLocked {
+		options = append(options, "SKIP_LOCKED")
+	}
+	if len(options) > 0 {
+		sql.WriteString(" (")
+		sql.WriteString(strings.Join(options, ", "))
+		sql.WriteString(")")
+	}
+
+	if len(b.tables) > 0 {
+		sql.WriteString(" ")
+		sql.WriteString(quoteIdents(b.tables))
+	}
+
+	if len(b.columns) > 0 {
+		sql.WriteString(" (")
+		sql.WriteString(quoteIdents(b.columns))
+		sql.WriteString(")")
+	}
+
+	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
+	return
+}
+
+// Tables adds tables to be analyzed. Without tables, the whole database is
+// analyzed.
+func (b *AnalyzeBuilder) Tables(tables ...string) *AnalyzeBuilder {
+	b.tables = append(b.tables, tables...)
+	return b
+}
+
+// Columns restricts the statement to the given columns. Requires exactly
+// one table.
+func (b *AnalyzeBuilder) Columns(columns ...string) *AnalyzeBuilder {
+	b.columns = append(b.columns, columns...)
+	return b
+}
+
+// Verbose adds the VERBOSE option.
+func (b *AnalyzeBuilder) Verbose() *AnalyzeBuilder {
+	b.verbose = true
+	return b
+}
+
+// SkipLocked adds the SKIP_LOCKED option, skipping tables which can not be
+// locked immediately.
+func (b *AnalyzeBuilder) SkipLocked() *AnalyzeBuilder {
+	b.skipLocked = true
+	return b
+}
+
+// ReindexBuilder builds SQL REINDEX statements. Names are quoted as
+// identifiers.
+//
+// Ex:
+//     ReindexIndex("events_created_at_idx").Concurrently()
+//     == `REINDEX INDEX CONCURRENTLY "events_created_at_idx"`
+type ReindexBuilder struct {
+	StatementBuilderType
+
+	kind         string
+	name         string
+	concurrently bool
+	verbose      bool
+}
+
+// NewReindexBuilder creates new instance of ReindexBuilder for the given
+// kind of object, e.g. "INDEX" or "TABLE".
+func NewReindexBuilder(b StatementBuilderType, kind string) *ReindexBuilder {
+	return &ReindexBuilder{StatementBuilderType: b, kind: kind}
+}
+
+// ExecContext builds and Execs the statement using given context.
+func (b *ReindexBuilder) ExecContext(ctx context.Context, pool instapgxpool.Pool) (pgconn.CommandTag, error) {
+	return b.execContext(ctx, pool, b)
+}
+
+// ExecAttached is like ExecContext, using the pool set with RunWithPool.
+func (b *ReindexBuilder) ExecAttached(ctx context.Context) (pgconn.CommandTag, error) {
+	return b.ExecContext(ctx, nil)
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// statement.
+func (b *ReindexBuilder) PlaceholderFormat(f PlaceholderFormat) *ReindexBuilder {
+	b.placeholderFormat = f
+	return b
+}
+
+// ToSql builds the statement into a SQL string and bound args.
+func (b *ReindexBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
+	if len(b.name) == 0 {
+		err = fmt.Errorf("reindex %s statements must specify a name", strings.ToLower(b.kind))
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	sql.WriteString("REINDEX ")
+	if b.verbose {
+		sql.WriteString("(VERBOSE) ")
+	}
+	sql.WriteString(b.kind)
+	sql.WriteString(" ")
+	if b.concurrently {
+		sql.WriteString("CONCURRENTLY ")
+	}
+	sql.WriteString(quoteIdent(b.name))
+
+	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
+	return
+}
+
+// Name sets the name of the object to be reindexed.
+func (b *ReindexBuilder) Name(name string) *ReindexBuilder {
+	b.name = name
+	return b
+}
+
+// Concurrently adds CONCURRENTLY to the statement, rebuilding the indexes
+// without locking out writes. It can not run inside a transaction.
+func (b *ReindexBuilder) Concurrently() *ReindexBuilder {
+	b.concurrently = true
+	return b
+}
+
+// Verbose adds the VERBOSE option.
+func (b *ReindexBuilder) Verbose() *ReindexBuilder {
+	b.verbose = true
+	return b
+}
+
+// ClusterBuilder builds SQL CLUSTER statements. Names are quoted as
+// identifiers.
+//
+// Ex:
+//     Cluster("events", "events_created_at_idx")
+//     == `CLUSTER "events" USING "events_created_at_idx"`
+type ClusterBuilder struct {
+	StatementBuilderType
+
+	table   string
+	index   string
+	verbose bool
+}
+
+// NewClusterBuilder creates new instance of ClusterBuilder
+func NewClusterBuilder(b StatementBuilderType) *ClusterBuilder {
+	return &ClusterBuilder{StatementBuilderType: b}
+}
+
+// ExecContext builds and Execs the statement using given context.
+func (b *ClusterBuilder) ExecContext(ctx context.Context, pool instapgxpool.Pool) (pgconn.CommandTag, error) {
+	return b.execContext(ctx, pool, b)
+}
+
+// ExecAttached is like ExecContext, using the pool set with RunWithPool.
+func (b *ClusterBuilder) ExecAttached(ctx context.Context) (pgconn.CommandTag, error) {
+	return b.ExecContext(ctx, nil)
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// statement.
+func (b *ClusterBuilder) PlaceholderFormat(f PlaceholderFormat) *ClusterBuilder {
+	b.placeholderFormat = f
+	return b
+}
+
+// ToSql builds the statement into a SQL string and bound args.
+func (b *ClusterBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
+	if len(b.index) > 0 && len(b.table) == 0 {
+		err = fmt.Errorf("cluster statements using an index must specify a table")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	sql.WriteString("CLUSTER")
+	if b.verbose {
+		sql.WriteString(" VERBOSE")
+	}
+	if len(b.table) > 0 {
+		sql.WriteString(" ")
+		sql.WriteString(quoteIdent(b.table))
+	}
+	if len(b.index) > 0 {
+		sql.WriteString(" USING ")
+		sql.WriteString(quoteIdent(b.index))
+	}
+
+	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
+	return
+}
+
+// Table sets the table to be clustered. Without a table, all previously
+// clustered tables are clustered again.
+func (b *ClusterBuilder) Table(table string) *ClusterBuilder {
+	b.table = table
+	return b
+}
+
+// Using sets the index to cluster the table by. Without an index, the
+// index the table was previously clustered by is used.
+func (b *ClusterBuilder) Using(index string) *ClusterBuilder {
+	b.index = index
+	return b
+}
+
+// Verbose adds the VERBOSE option.
+func (b *ClusterBuilder) Verbose() *ClusterBuilder {
+	b.verbose = true
+	return b
+}
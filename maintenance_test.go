@@ -0,0 +1,86 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVacuumBuilderToSql(t *testing.T) {
+	sql, args, err := Vacuum("events").Full().Analyze().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `VACUUM (FULL, ANALYZE) "events"`, sql)
+	assert.Empty(t, args)
+
+	sql, _, err = Vacuum().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "VACUUM", sql)
+
+	sql, _, err = Vacuum("public.events", "Logs").Freeze().Verbose().SkipLocked().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `VACUUM (FREEZE, VERBOSE, SKIP_LOCKED) "public"."events", "Logs"`, sql)
+
+	sql, _, err = Vacuum("events").Analyze().Columns("created_at", `we"ird`).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `VACUUM (ANALYZE) "events" ("created_at", "we""ird")`, sql)
+}
+
+func TestVacuumBuilderToSqlErr(t *testing.T) {
+	_, _, err := Vacuum("events").Columns("a").ToSql()
+	assert.EqualError(t, err, "vacuum column lists require ANALYZE")
+
+	_, _, err = Vacuum("a", "b").Analyze().Columns("c").ToSql()
+	assert.EqualError(t, err, "vacuum column lists require exactly one table")
+}
+
+func TestAnalyzeBuilderToSql(t *testing.T) {
+	sql, args, err := Analyze("events", "created_at").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `ANALYZE "events" ("created_at")`, sql)
+	assert.Empty(t, args)
+
+	sql, _, err = Analyze("events").Tables("users").Verbose().SkipLocked().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `ANALYZE (VERBOSE, SKIP_LOCKED) "events", "users"`, sql)
+
+	_, _, err = Analyze("events", "a").Tables("users").ToSql()
+	assert.EqualError(t, err, "analyze column lists require exactly one table")
+
+	assert.Equal(t, []string{"events", "users"}, Tables(Analyze("events").Tables("users")))
+}
+
+func TestReindexBuilderToSql(t *testing.T) {
+	sql, args, err := ReindexIndex("events_created_at_idx").Concurrently().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `REINDEX INDEX CONCURRENTLY "events_created_at_idx"`, sql)
+	assert.Empty(t, args)
+
+	sql, _, err = ReindexTable("public.events").Verbose().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `REINDEX (VERBOSE) TABLE "public"."events"`, sql)
+
+	sql, _, err = ReindexSchema("audit").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `REINDEX SCHEMA "audit"`, sql)
+
+	_, _, err = ReindexTable("").ToSql()
+	assert.EqualError(t, err, "reindex table statements must specify a name")
+}
+
+func TestClusterBuilderToSql(t *testing.T) {
+	sql, args, err := Cluster("events", "events_created_at_idx").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `CLUSTER "events" USING "events_created_at_idx"`, sql)
+	assert.Empty(t, args)
+
+	sql, _, err = Cluster("events", "").Verbose().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `CLUSTER VERBOSE "events"`, sql)
+
+	sql, _, err = Cluster("", "").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CLUSTER", sql)
+
+	_, _, err = Cluster("", "idx").ToSql()
+	assert.EqualError(t, err, "cluster statements using an index must specify a table")
+}
@@ -0,0 +1,684 @@
+package sqrl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Select, Insert, Update and Delete builders implement json.Marshaler and
+// json.Unmarshaler, so query definitions can be stored or shipped between
+// services and rendered later, e.g. with a different PlaceholderFormat.
+//
+// The JSON form keeps the structure of the statement (columns, tables,
+// joins, predicates, ordering, ...). Every expression is stored as its SQL
+// with Question placeholders plus its args, so custom Sqlizers survive the
+// round trip as plain SQL. Args must be JSON encodable; integral numbers
+// are decoded as int64, other numbers as float64 and everything else as its
+// JSON type.
+//
+// Unmarshaling keeps the PlaceholderFormat, runner and other settings of
+// the StatementBuilderType of the builder it is decoded into, except for
+// the default ORDER BY of an encoded SelectBuilder.
+//
+// Ex:
+//     data, err := json.Marshal(Select("*").From("users").Where(Eq{"id": 1}))
+//     ...
+//     b := StatementBuilder.PlaceholderFormat(Dollar).Select()
+//     err = json.Unmarshal(data, b)
+//     b.ToSql() == "SELECT * FROM users WHERE id = $1", 1
+
+// sqlFragment is the JSON form of a Sqlizer.
+type sqlFragment struct {
+	SQL  string        `json:"sql"`
+	Args []interface{} `json:"args,omitempty"`
+}
+
+func (f *sqlFragment) UnmarshalJSON(data []byte) error {
+	type plain sqlFragment
+	var p plain
+	if err := decodeJSON(data, &p); err != nil {
+		return err
+	}
+	args, err := jsonValues(p.Args)
+	if err != nil {
+		return err
+	}
+	*f = sqlFragment{SQL: p.SQL, Args: args}
+	return nil
+}
+
+// valueJSON is the JSON form of a value in Values or Set, which is either a
+// plain value or a Sqlizer. Statements are decoded with UseNumber, so Value
+// is converted by value.
+type valueJSON struct {
+	Value interface{}  `json:"value,omitempty"`
+	Expr  *sqlFragment `json:"expr,omitempty"`
+}
+
+type setJSON struct {
+	Column string `json:"column"`
+	valueJSON
+}
+
+type onConflictJSON struct {
+	Target      []string      `json:"target,omitempty"`
+	Constraint  string        `json:"constraint,omitempty"`
+	Where       []sqlFragment `json:"where,omitempty"`
+	DoNothing   bool          `json:"do_nothing,omitempty"`
+	Set         []setJSON     `json:"set,omitempty"`
+	UpdateWhere []sqlFragment `json:"update_where,omitempty"`
+}
+
+type selectJSON struct {
+	Type     string        `json:"type"`
+	Prefixes []sqlFragment `json:"prefixes,omitempty"`
+	Hints    []string      `json:"hints,omitempty"`
+	Distinct bool          `json:"distinct,omitempty"`
+	Options  []string      `json:"options,omitempty"`
+	Columns  []sqlFragment `json:"columns,omitempty"`
+	From     []sqlFragment `json:"from,omitempty"`
+	Joins    []sqlFragment `json:"joins,omitempty"`
+	Where    []sqlFragment `json:"where,omitempty"`
+	GroupBy  []string      `json:"group_by,omitempty"`
+	Having   []sqlFragment `json:"having,omitempty"`
+	OrderBy  []string      `json:"order_by,omitempty"`
+	Union    []*selectJSON `json:"union,omitempty"`
+	UnionAll []*selectJSON `json:"union_all,omitempty"`
+	Limit    *uint64       `json:"limit,omitempty"`
+	Offset   *uint64       `json:"offset,omitempty"`
+	Suffixes []sqlFragment `json:"suffixes,omitempty"`
+
+	DefaultOrderBy []string `json:"default_order_by,omitempty"`
+	Fragments      []string `json:"fragments,omitempty"`
+	JoinKeys       []string `json:"join_keys,omitempty"`
+}
+
+type insertJSON struct {
+	Type       string          `json:"type"`
+	Prefixes   []sqlFragment   `json:"prefixes,omitempty"`
+	Options    []string        `json:"options,omitempty"`
+	Into       string          `json:"into"`
+	Columns    []string        `json:"columns,omitempty"`
+	Values     [][]valueJSON   `json:"values,omitempty"`
+	Select     *selectJSON     `json:"select,omitempty"`
+	OnConflict *onConflictJSON `json:"on_conflict,omitempty"`
+	NullIfZero []string        `json:"null_if_zero,omitempty"`
+	Returning  []sqlFragment   `json:"returning,omitempty"`
+	Suffixes   []sqlFragment   `json:"suffixes,omitempty"`
+}
+
+type updateJSON struct {
+	Type       string        `json:"type"`
+	Prefixes   []sqlFragment `json:"prefixes,omitempty"`
+	Table      string        `json:"table"`
+	From       []sqlFragment `json:"from,omitempty"`
+	Set        []setJSON     `json:"set,omitempty"`
+	NullIfZero []string      `json:"null_if_zero,omitempty"`
+	Where      []sqlFragment `json:"where,omitempty"`
+	CurrentOf  string        `json:"current_of,omitempty"`
+	OrderBy    []string      `json:"order_by,omitempty"`
+	Limit      *uint64       `json:"limit,omitempty"`
+	Offset     *uint64       `json:"offset,omitempty"`
+	Returning  []sqlFragment `json:"returning,omitempty"`
+	Suffixes   []sqlFragment `json:"suffixes,omitempty"`
+}
+
+type deleteJSON struct {
+	Type      string        `json:"type"`
+	Prefixes  []sqlFragment `json:"prefixes,omitempty"`
+	What      []string      `json:"what,omitempty"`
+	From      string        `json:"from"`
+	Joins     []string      `json:"joins,omitempty"`
+	Using     []sqlFragment `json:"using,omitempty"`
+	Where     []sqlFragment `json:"where,omitempty"`
+	CurrentOf string        `json:"current_of,omitempty"`
+	OrderBy   []string      `json:"order_by,omitempty"`
+	Limit     *uint64       `json:"limit,omitempty"`
+	Offset    *uint64       `json:"offset,omitempty"`
+	Returning []sqlFragment `json:"returning,omitempty"`
+	Suffixes  []sqlFragment `json:"suffixes,omitempty"`
+}
+
+// MarshalJSON encodes the statement into its portable JSON form.
+func (b *SelectBuilder) MarshalJSON() ([]byte, error) {
+	j, err := b.toJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON restores a statement encoded by MarshalJSON.
+func (b *SelectBuilder) UnmarshalJSON(data []byte) error {
+	if err := checkJSONType(data, KindSelect); err != nil {
+		return err
+	}
+	var j selectJSON
+	if err := decodeJSON(data, &j); err != nil {
+		return err
+	}
+	*b = *j.builder(b.jsonStatementBuilder())
+	return nil
+}
+
+func (b *SelectBuilder) toJSON() (*selectJSON, error) {
+	j := &selectJSON{
+		Type:     KindSelect.String(),
+		Hints:    b.hints,
+		Distinct: b.distinct,
+		Options:  b.options,
+		GroupBy:  b.groupBys,
+		OrderBy:  b.orderBys,
+		Limit:    optionalUint(b.limit, b.limitValid),
+		Offset:   optionalUint(b.offset, b.offsetValid),
+
+		DefaultOrderBy: b.defaultOrderBys,
+		Fragments:      b.fragments,
+		JoinKeys:       b.joinKeys,
+	}
+
+	var err error
+	fragments := []struct {
+		dst   *[]sqlFragment
+		parts []Sqlizer
+	}{
+		{&j.Prefixes, b.prefixes.sqlizers()},
+		{&j.Columns, b.columns},
+		{&j.From, b.fromParts},
+		{&j.Joins, b.joins},
+		{&j.Where, b.whereParts},
+		{&j.Having, b.havingParts},
+		{&j.Suffixes, b.suffixes.sqlizers()},
+	}
+	for _, f := range fragments {
+		if *f.dst, err = toFragments(f.parts); err != nil {
+			return nil, err
+		}
+	}
+
+	if j.Union, err = unionsToJSON(b.union); err != nil {
+		return nil, err
+	}
+	if j.UnionAll, err = unionsToJSON(b.unionAll); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (j *selectJSON) builder(sb StatementBuilderType) *SelectBuilder {
+	b := NewSelectBuilder(sb)
+	b.prefixes = toExprs(j.Prefixes)
+	b.hints = j.Hints
+	b.distinct = j.Distinct
+	b.options = j.Options
+	b.columns = toParts(j.Columns)
+	b.fromParts = toParts(j.From)
+	b.joins = toParts(j.Joins)
+	b.whereParts = toWhereParts(j.Where)
+	b.groupBys = j.GroupBy
+	b.havingParts = toWhereParts(j.Having)
+	b.orderBys = j.OrderBy
+	for _, u := range j.Union {
+		b.union = append(b.union, newUnionPart(u.builder(sb)))
+	}
+	for _, u := range j.UnionAll {
+		b.unionAll = append(b.unionAll, newUnionPart(u.builder(sb)))
+	}
+	b.limit, b.limitValid = fromOptionalUint(j.Limit)
+	b.offset, b.offsetValid = fromOptionalUint(j.Offset)
+	b.suffixes = toExprs(j.Suffixes)
+	if len(j.DefaultOrderBy) > 0 {
+		b.defaultOrderBys = j.DefaultOrderBy
+	}
+	b.fragments = j.Fragments
+	b.joinKeys = j.JoinKeys
+	return b
+}
+
+// MarshalJSON encodes the statement into its portable JSON form.
+func (b *InsertBuilder) MarshalJSON() ([]byte, error) {
+	j := &insertJSON{
+		Type:       KindInsert.String(),
+		Options:    b.options,
+		Into:       b.into,
+		Columns:    b.columns,
+		NullIfZero: b.nullIfZero.columns(),
+	}
+
+	var err error
+	if j.Prefixes, err = toFragments(b.prefixes.sqlizers()); err != nil {
+		return nil, err
+	}
+	for _, row := range b.values {
+		values := make([]valueJSON, len(row))
+		for i, v := range row {
+			if values[i], err = toValueJSON(v); err != nil {
+				return nil, err
+			}
+		}
+		j.Values = append(j.Values, values)
+	}
+	if b.iselect != nil {
+		if j.Select, err = b.iselect.toJSON(); err != nil {
+			return nil, err
+		}
+	}
+	if b.onConflict != nil {
+		if j.OnConflict, err = b.onConflict.toJSON(); err != nil {
+			return nil, err
+		}
+	}
+	if j.Returning, err = toFragments(b.returning); err != nil {
+		return nil, err
+	}
+	if j.Suffixes, err = toFragments(b.suffixes.sqlizers()); err != nil {
+		return nil, err
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON restores a statement encoded by MarshalJSON.
+func (b *InsertBuilder) UnmarshalJSON(data []byte) error {
+	if err := checkJSONType(data, KindInsert); err != nil {
+		return err
+	}
+	var j insertJSON
+	if err := decodeJSON(data, &j); err != nil {
+		return err
+	}
+
+	sb := b.jsonStatementBuilder()
+	ib := InsertBuilder{
+		StatementBuilderType: sb,
+		returning:            toParts(j.Returning),
+		prefixes:             toExprs(j.Prefixes),
+		options:              j.Options,
+		into:                 j.Into,
+		columns:              j.Columns,
+		suffixes:             toExprs(j.Suffixes),
+	}
+	if len(j.NullIfZero) > 0 {
+		ib.nullIfZero = ib.nullIfZero.add(j.NullIfZero)
+	}
+	for _, row := range j.Values {
+		values := make([]interface{}, len(row))
+		for i, v := range row {
+			var err error
+			if values[i], err = v.value(); err != nil {
+				return err
+			}
+		}
+		ib.values = append(ib.values, values)
+	}
+	if j.Select != nil {
+		ib.iselect = j.Select.builder(sb)
+	}
+	if c := j.OnConflict; c != nil {
+		setClauses, err := toSetClauses(c.Set)
+		if err != nil {
+			return err
+		}
+		ib.onConflict = &onConflict{
+			target:      c.Target,
+			constraint:  c.Constraint,
+			whereParts:  toWhereParts(c.Where),
+			doNothing:   c.DoNothing,
+			setClauses:  setClauses,
+			updateWhere: toWhereParts(c.UpdateWhere),
+		}
+	}
+	*b = ib
+	return nil
+}
+
+func (c *onConflict) toJSON() (*onConflictJSON, error) {
+	j := &onConflictJSON{
+		Target:     c.target,
+		Constraint: c.constraint,
+		DoNothing:  c.doNothing,
+	}
+
+	var err error
+	if j.Where, err = toFragments(c.whereParts); err != nil {
+		return nil, err
+	}
+	if j.Set, err = toSetJSON(c.setClauses); err != nil {
+		return nil, err
+	}
+	if j.UpdateWhere, err = toFragments(c.updateWhere); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// MarshalJSON encodes the statement into its portable JSON form.
+func (b *UpdateBuilder) MarshalJSON() ([]byte, error) {
+	j := &updateJSON{
+		Type:       KindUpdate.String(),
+		Table:      b.table,
+		NullIfZero: b.nullIfZero.columns(),
+		CurrentOf:  b.currentOf,
+		OrderBy:    b.orderBys,
+		Limit:      optionalUint(b.limit, b.limitValid),
+		Offset:     optionalUint(b.offset, b.offsetValid),
+	}
+
+	var err error
+	if j.Set, err = toSetJSON(b.setClauses); err != nil {
+		return nil, err
+	}
+	fragments := []struct {
+		dst   *[]sqlFragment
+		parts []Sqlizer
+	}{
+		{&j.Prefixes, b.prefixes.sqlizers()},
+		{&j.From, b.fromParts},
+		{&j.Where, writeWhereParts(b.whereParts)},
+		{&j.Returning, b.returning},
+		{&j.Suffixes, b.suffixes.sqlizers()},
+	}
+	for _, f := range fragments {
+		if *f.dst, err = toFragments(f.parts); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON restores a statement encoded by MarshalJSON.
+func (b *UpdateBuilder) UnmarshalJSON(data []byte) error {
+	if err := checkJSONType(data, KindUpdate); err != nil {
+		return err
+	}
+	var j updateJSON
+	if err := decodeJSON(data, &j); err != nil {
+		return err
+	}
+
+	setClauses, err := toSetClauses(j.Set)
+	if err != nil {
+		return err
+	}
+
+	*b = UpdateBuilder{
+		StatementBuilderType: b.jsonStatementBuilder(),
+		returning:            toParts(j.Returning),
+		prefixes:             toExprs(j.Prefixes),
+		table:                j.Table,
+		fromParts:            toParts(j.From),
+		setClauses:           setClauses,
+		whereParts:           toWhereParts(j.Where),
+		currentOf:            j.CurrentOf,
+		orderBys:             j.OrderBy,
+		suffixes:             toExprs(j.Suffixes),
+	}
+	if len(j.NullIfZero) > 0 {
+		b.nullIfZero = b.nullIfZero.add(j.NullIfZero)
+	}
+	b.limit, b.limitValid = fromOptionalUint(j.Limit)
+	b.offset, b.offsetValid = fromOptionalUint(j.Offset)
+	return nil
+}
+
+// MarshalJSON encodes the statement into its portable JSON form.
+func (b *DeleteBuilder) MarshalJSON() ([]byte, error) {
+	j := &deleteJSON{
+		Type:      KindDelete.String(),
+		What:      b.what,
+		From:      b.from,
+		Joins:     b.joins,
+		CurrentOf: b.currentOf,
+		OrderBy:   b.orderBys,
+		Limit:     optionalUint(b.limit, b.limitValid),
+		Offset:    optionalUint(b.offset, b.offsetValid),
+	}
+
+	var err error
+	fragments := []struct {
+		dst   *[]sqlFragment
+		parts []Sqlizer
+	}{
+		{&j.Prefixes, b.prefixes.sqlizers()},
+		{&j.Using, b.usingParts},
+		{&j.Where, writeWhereParts(b.whereParts)},
+		{&j.Returning, b.returning},
+		{&j.Suffixes, b.suffixes.sqlizers()},
+	}
+	for _, f := range fragments {
+		if *f.dst, err = toFragments(f.parts); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON restores a statement encoded by MarshalJSON.
+func (b *DeleteBuilder) UnmarshalJSON(data []byte) error {
+	if err := checkJSONType(data, KindDelete); err != nil {
+		return err
+	}
+	var j deleteJSON
+	if err := decodeJSON(data, &j); err != nil {
+		return err
+	}
+
+	*b = DeleteBuilder{
+		StatementBuilderType: b.jsonStatementBuilder(),
+		returning:            toParts(j.Returning),
+		prefixes:             toExprs(j.Prefixes),
+		what:                 j.What,
+		from:                 j.From,
+		joins:                j.Joins,
+		usingParts:           toParts(j.Using),
+		whereParts:           toWhereParts(j.Where),
+		currentOf:            j.CurrentOf,
+		orderBys:             j.OrderBy,
+		suffixes:             toExprs(j.Suffixes),
+	}
+	b.limit, b.limitValid = fromOptionalUint(j.Limit)
+	b.offset, b.offsetValid = fromOptionalUint(j.Offset)
+	return nil
+}
+
+// jsonStatementBuilder returns the StatementBuilderType to use for a
+// statement decoded into b, falling back to StatementBuilder for zero value
+// builders.
+func (b StatementBuilderType) jsonStatementBuilder() StatementBuilderType {
+	if b.placeholderFormat == nil {
+		return StatementBuilder
+	}
+	return b
+}
+
+// checkJSONType checks data encodes a statement of the given kind before it
+// is decoded, as fields of different statements clash.
+func checkJSONType(data []byte, kind StatementKind) error {
+	var j struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Type != kind.String() {
+		return fmt.Errorf("expected %s statement, got %q", kind, j.Type)
+	}
+	return nil
+}
+
+func (es exprs) sqlizers() []Sqlizer {
+	parts := make([]Sqlizer, len(es))
+	for i, e := range es {
+		parts[i] = e
+	}
+	return parts
+}
+
+func toFragments(parts []Sqlizer) ([]sqlFragment, error) {
+	var fragments []sqlFragment
+	for _, p := range parts {
+		sql, args, err := p.ToSql()
+		if err != nil {
+			return nil, err
+		}
+		if len(sql) == 0 {
+			continue
+		}
+		fragments = append(fragments, sqlFragment{SQL: sql, Args: args})
+	}
+	return fragments, nil
+}
+
+// writeWhereParts returns the where parts of an UPDATE or DELETE to encode.
+// If all of them are empty, they are encoded as FALSE, as toFragments skips
+// empty parts and the decoded statement would match every row otherwise.
+func writeWhereParts(parts []Sqlizer) []Sqlizer {
+	if len(parts) == 0 {
+		return nil
+	}
+	sql, _, err := writeConditions(parts).ToSql()
+	if err == nil && sql == "FALSE" {
+		return []Sqlizer{Expr("FALSE")}
+	}
+	return parts
+}
+
+func toParts(fragments []sqlFragment) []Sqlizer {
+	var parts []Sqlizer
+	for _, f := range fragments {
+		parts = append(parts, newPart(f.SQL, f.Args...))
+	}
+	return parts
+}
+
+func toWhereParts(fragments []sqlFragment) []Sqlizer {
+	var parts []Sqlizer
+	for _, f := range fragments {
+		parts = append(parts, newWherePart(f.SQL, f.Args...))
+	}
+	return parts
+}
+
+func toExprs(fragments []sqlFragment) exprs {
+	var es exprs
+	for _, f := range fragments {
+		es = append(es, expr{sql: f.SQL, args: f.Args})
+	}
+	return es
+}
+
+func unionsToJSON(parts []Sqlizer) ([]*selectJSON, error) {
+	var unions []*selectJSON
+	for _, p := range parts {
+		u, ok := p.(*unionPart)
+		if !ok {
+			return nil, fmt.Errorf("cannot encode union of %T", p)
+		}
+		sb, ok := u.expr.(*SelectBuilder)
+		if !ok {
+			return nil, fmt.Errorf("cannot encode union of %T", u.expr)
+		}
+		j, err := sb.toJSON()
+		if err != nil {
+			return nil, err
+		}
+		unions = append(unions, j)
+	}
+	return unions, nil
+}
+
+func toValueJSON(v interface{}) (valueJSON, error) {
+	s, ok := v.(Sqlizer)
+	if !ok {
+		return valueJSON{Value: v}, nil
+	}
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return valueJSON{}, err
+	}
+	return valueJSON{Expr: &sqlFragment{SQL: sql, Args: args}}, nil
+}
+
+func (v valueJSON) value() (interface{}, error) {
+	if v.Expr != nil {
+		return expr{sql: v.Expr.SQL, args: v.Expr.Args}, nil
+	}
+	return jsonValue(v.Value)
+}
+
+func toSetJSON(clauses []setClause) ([]setJSON, error) {
+	var set []setJSON
+	for _, c := range clauses {
+		v, err := toValueJSON(c.value)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, setJSON{Column: c.column, valueJSON: v})
+	}
+	return set, nil
+}
+
+func toSetClauses(set []setJSON) ([]setClause, error) {
+	var clauses []setClause
+	for _, s := range set {
+		v, err := s.value()
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, setClause{column: s.Column, value: v})
+	}
+	return clauses, nil
+}
+
+func optionalUint(v uint64, valid bool) *uint64 {
+	if !valid {
+		return nil
+	}
+	return &v
+}
+
+func fromOptionalUint(v *uint64) (uint64, bool) {
+	if v == nil {
+		return 0, false
+	}
+	return *v, true
+}
+
+func decodeJSON(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func jsonValues(values []interface{}) ([]interface{}, error) {
+	for i, v := range values {
+		var err error
+		if values[i], err = jsonValue(v); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// jsonValue converts numbers decoded with UseNumber to int64 if they are
+// integral and float64 otherwise.
+func jsonValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case json.Number:
+		if iv, err := v.Int64(); err == nil {
+			return iv, nil
+		}
+		return v.Float64()
+	case []interface{}:
+		return jsonValues(v)
+	case map[string]interface{}:
+		for k, e := range v {
+			var err error
+			if v[k], err = jsonValue(e); err != nil {
+				return nil, err
+			}
+		}
+		return v, nil
+	}
+	return v, nil
+}
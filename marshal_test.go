@@ -0,0 +1,194 @@
+package sqrl
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assertRoundTrip(t *testing.T, b Sqlizer, into Sqlizer) {
+	expectedSql, expectedArgs, err := b.ToSql()
+	assert.NoError(t, err)
+
+	data, err := json.Marshal(b)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(data, into))
+
+	sql, args, err := into.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestSelectBuilderJSON(t *testing.T) {
+	b := Select("a", "count(*) AS c").
+		Prefix("WITH x AS (SELECT ?)", int64(1)).
+		Distinct().
+		From("users u").
+		Join("emails e ON e.user_id = u.id AND e.kind = ?", "primary").
+		Where(Eq{"u.id": []int64{1, 2}}).
+		Where(Eq{"u.deleted_at": nil}).
+		Where("u.name LIKE ?", "a%").
+		GroupBy("a").
+		Having("count(*) > ?", 1.5).
+		OrderBy("a DESC").
+		Union(Select("a", "0").From("admins").Where("active")).
+		Limit(10).
+		Offset(0).
+		Suffix("FOR UPDATE")
+
+	assertRoundTrip(t, b, Select())
+}
+
+func TestSelectBuilderJSONBookkeeping(t *testing.T) {
+	author := NewFragment("author").
+		Columns("a.name").
+		Join("authors a ON a.id = p.author_id")
+	b := Select("p.id").From("posts p").Apply(author).
+		JoinClauseOnce("stats s", "JOIN stats s ON s.post_id = p.id").
+		DefaultOrderBy("p.id")
+	assertRoundTrip(t, b, Select())
+
+	data, err := json.Marshal(b)
+	assert.NoError(t, err)
+	decoded := Select()
+	assert.NoError(t, json.Unmarshal(data, decoded))
+
+	sql, _, err := decoded.Apply(author).JoinClauseOnce("stats s", "JOIN stats s ON s.id = p.id").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT p.id, a.name FROM posts p JOIN authors a ON a.id = p.author_id JOIN stats s ON s.post_id = p.id ORDER BY p.id", sql)
+}
+
+// TestBuilderJSONFields makes sure that fields added to the builders are
+// added to their JSON form as well.
+func TestBuilderJSONFields(t *testing.T) {
+	encoded := map[reflect.Type][]string{
+		reflect.TypeOf(SelectBuilder{}): {"prefixes", "hints", "distinct", "options", "columns", "fromParts", "joins",
+			"whereParts", "groupBys", "havingParts", "orderBys", "union", "unionAll", "limit", "limitValid",
+			"offset", "offsetValid", "suffixes", "fragments", "joinKeys"},
+		reflect.TypeOf(InsertBuilder{}): {"returning", "prefixes", "options", "into", "columns", "values", "suffixes",
+			"iselect", "onConflict", "nullIfZero"},
+		reflect.TypeOf(UpdateBuilder{}): {"returning", "prefixes", "table", "fromParts", "setClauses", "whereParts",
+			"currentOf", "orderBys", "nullIfZero", "limit", "limitValid", "offset", "offsetValid", "suffixes"},
+		reflect.TypeOf(DeleteBuilder{}): {"returning", "prefixes", "what", "from", "joins", "usingParts", "whereParts",
+			"currentOf", "orderBys", "limit", "limitValid", "offset", "offsetValid", "suffixes"},
+		reflect.TypeOf(onConflict{}): {"target", "constraint", "whereParts", "doNothing", "setClauses", "updateWhere"},
+	}
+	for typ, fields := range encoded {
+		var actual []string
+		for i := 0; i < typ.NumField(); i++ {
+			if f := typ.Field(i); f.Name != "StatementBuilderType" {
+				actual = append(actual, f.Name)
+			}
+		}
+		assert.ElementsMatch(t, fields, actual, "%s", typ)
+	}
+}
+
+func TestSelectBuilderJSONPlaceholderFormat(t *testing.T) {
+	data, err := json.Marshal(Select("*").From("users").Where(Eq{"id": 1}))
+	assert.NoError(t, err)
+
+	b := StatementBuilder.PlaceholderFormat(Dollar).Select()
+	assert.NoError(t, json.Unmarshal(data, b))
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = $1", sql)
+	assert.Equal(t, []interface{}{int64(1)}, args)
+	assert.Equal(t, []string{"users"}, Tables(b))
+}
+
+func TestSelectBuilderJSONZeroValue(t *testing.T) {
+	data, err := json.Marshal(Select("id").From("users").Where("id = ?", 1))
+	assert.NoError(t, err)
+
+	var b SelectBuilder
+	assert.NoError(t, json.Unmarshal(data, &b))
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE id = ?", sql)
+	assert.Equal(t, []interface{}{int64(1)}, args)
+}
+
+func TestSelectBuilderJSONWrongType(t *testing.T) {
+	data, err := json.Marshal(Delete("users").Where("id = ?", 1))
+	assert.NoError(t, err)
+
+	err = json.Unmarshal(data, Select())
+	assert.EqualError(t, err, `expected select statement, got "delete"`)
+}
+
+func TestInsertBuilderJSON(t *testing.T) {
+	b := Insert("users").
+		Columns("id", "name", "created_at").
+		Values(int64(1), "a", Expr("now()")).
+		Values(int64(2), nil, Expr("now() - ?::interval", "1 day")).
+		OnConflict("id").
+		DoUpdateSet("name", Expr("EXCLUDED.name")).
+		Returning("id")
+
+	assertRoundTrip(t, b, Insert(""))
+
+	sel := Insert("archive").Select(Select("*").From("users").Where("id > ?", int64(10)))
+	assertRoundTrip(t, sel, Insert(""))
+}
+
+func TestUpdateBuilderJSON(t *testing.T) {
+	b := Update("users").
+		Set("name", "a").
+		Set("visits", Expr("visits + ?", int64(1))).
+		From("teams t").
+		Where("t.id = users.team_id AND t.name = ?", "b").
+		Returning("id")
+
+	assertRoundTrip(t, b, Update(""))
+}
+
+func TestDeleteBuilderJSON(t *testing.T) {
+	b := Delete("users").
+		Using("teams t").
+		Where("t.id = users.team_id").
+		Where(Lt{"users.id": int64(5)}).
+		Returning("id")
+
+	assertRoundTrip(t, b, Delete(""))
+}
+
+func TestWriteBuilderJSONEmptyConditions(t *testing.T) {
+	var id *int64
+	assertRoundTrip(t, Update("users").Set("name", "a").Where(EqNotZero{"id": id}), Update(""))
+	assertRoundTrip(t, Delete("users").Where(Eq{}).Where(EqNotNil{"id": id}), Delete(""))
+	assertRoundTrip(t, Delete("users").Where(Eq{}).Where("id = ?", int64(1)), Delete(""))
+}
+
+func TestWriteBuilderJSONNullIfZero(t *testing.T) {
+	ins := Insert("users").NullIfZero("nick", "age").Columns("name", "nick", "age").Values("moe", "", int64(0))
+	assertRoundTrip(t, ins, Insert(""))
+	_, args, err := ins.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"moe", nil, nil}, args)
+
+	data, err := json.Marshal(ins)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"null_if_zero":["age","nick"]`)
+
+	upd := Update("users").NullIfZero("nick").Set("nick", "").Where("id = ?", int64(1))
+	assertRoundTrip(t, upd, Update(""))
+}
+
+func TestWriteBuilderJSONCurrentOf(t *testing.T) {
+	assertRoundTrip(t, Update("t").Set("a", int64(1)).WhereCurrentOf("c"), Update(""))
+	assertRoundTrip(t, Delete("t").WhereCurrentOf("c").Returning("id"), Delete(""))
+
+	data, err := json.Marshal(Delete("t").WhereCurrentOf("c"))
+	assert.NoError(t, err)
+	b := Delete("")
+	assert.NoError(t, json.Unmarshal(data, b))
+	sql, _, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t WHERE CURRENT OF c", sql)
+}
@@ -0,0 +1,185 @@
+package sqrl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgconn"
+)
+
+// CreateMaterializedViewBuilder builds SQL CREATE MATERIALIZED VIEW statements.
+type CreateMaterializedViewBuilder struct {
+	StatementBuilderType
+
+	name        string
+	ifNotExists bool
+	query       *SelectBuilder
+	withNoData  bool
+}
+
+// NewCreateMaterializedViewBuilder creates new instance of CreateMaterializedViewBuilder
+func NewCreateMaterializedViewBuilder(b StatementBuilderType) *CreateMaterializedViewBuilder {
+	return &CreateMaterializedViewBuilder{StatementBuilderType: b}
+}
+
+// ExecContext builds and Execs the statement using given context.
+func (b *CreateMaterializedViewBuilder) ExecContext(ctx context.Context, pool instapgxpool.Pool) (pgconn.CommandTag, error) {
+	return b.execContext(ctx, pool, b)
+}
+
+// ExecAttached is like ExecContext, using the pool set with RunWithPool.
+func (b *CreateMaterializedViewBuilder) ExecAttached(ctx context.Context) (pgconn.CommandTag, error) {
+	return b.ExecContext(ctx, nil)
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// statement.
+func (b *CreateMaterializedViewBuilder) PlaceholderFormat(f PlaceholderFormat) *CreateMaterializedViewBuilder {
+	b.placeholderFormat = f
+	return b
+}
+
+// ToSql builds the statement into a SQL string and bound args.
+//
+// PostgreSQL does not allow materialized views to be defined using bound
+// parameters, so the query must not produce any args.
+func (b *CreateMaterializedViewBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
+	if len(b.name) == 0 {
+		err = fmt.Errorf("create materialized view statements must specify a name")
+		return
+	}
+	if b.query == nil {
+		err = fmt.Errorf("create materialized view statements must have a query")
+		return
+	}
+
+	query, queryArgs, err := nested(b.query).ToSql()
+	if err != nil {
+		return
+	}
+	if len(queryArgs) > 0 {
+		err = fmt.Errorf("materialized views may not be defined using bound parameters")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	sql.WriteString("CREATE MATERIALIZED VIEW ")
+	if b.ifNotExists {
+		sql.WriteString("IF NOT EXISTS ")
+	}
+	sql.WriteString(b.name)
+	sql.WriteString(" AS ")
+	sql.WriteString(query)
+
+	if b.withNoData {
+		sql.WriteString(" WITH NO DATA")
+	}
+
+	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
+	return
+}
+
+// Name sets the name of the materialized view.
+func (b *CreateMaterializedViewBuilder) Name(name string) *CreateMaterializedViewBuilder {
+	b.name = name
+	return b
+}
+
+// As sets the query defining the materialized view.
+func (b *CreateMaterializedViewBuilder) As(query *SelectBuilder) *CreateMaterializedViewBuilder {
+	b.query = query
+	return b
+}
+
+// IfNotExists adds IF NOT EXISTS to the statement.
+func (b *CreateMaterializedViewBuilder) IfNotExists() *CreateMaterializedViewBuilder {
+	b.ifNotExists = true
+	return b
+}
+
+// WithNoData creates the materialized view without populating it. It has to
+// be refreshed before it can be queried.
+func (b *CreateMaterializedViewBuilder) WithNoData() *CreateMaterializedViewBuilder {
+	b.withNoData = true
+	return b
+}
+
+// RefreshMaterializedViewBuilder builds SQL REFRESH MATERIALIZED VIEW statements.
+type RefreshMaterializedViewBuilder struct {
+	StatementBuilderType
+
+	name         string
+	concurrently bool
+	withNoData   bool
+}
+
+// NewRefreshMaterializedViewBuilder creates new instance of RefreshMaterializedViewBuilder
+func NewRefreshMaterializedViewBuilder(b StatementBuilderType) *RefreshMaterializedViewBuilder {
+	return &RefreshMaterializedViewBuilder{StatementBuilderType: b}
+}
+
+// ExecContext builds and Execs the statement using given context.
+func (b *RefreshMaterializedViewBuilder) ExecContext(ctx context.Context, pool instapgxpool.Pool) (pgconn.CommandTag, error) {
+	return b.execContext(ctx, pool, b)
+}
+
+// ExecAttached is like ExecContext, using the pool set with RunWithPool.
+func (b *RefreshMaterializedViewBuilder) ExecAttached(ctx context.Context) (pgconn.CommandTag, error) {
+	return b.ExecContext(ctx, nil)
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// statement.
+func (b *RefreshMaterializedViewBuilder) PlaceholderFormat(f PlaceholderFormat) *RefreshMaterializedViewBuilder {
+	b.placeholderFormat = f
+	return b
+}
+
+// ToSql builds the statement into a SQL string and bound args.
+func (b *RefreshMaterializedViewBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
+	if len(b.name) == 0 {
+		err = fmt.Errorf("refresh materialized view statements must specify a name")
+		return
+	}
+	if b.concurrently && b.withNoData {
+		err = fmt.Errorf("refresh materialized view can not be both CONCURRENTLY and WITH NO DATA")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	sql.WriteString("REFRESH MATERIALIZED VIEW ")
+	if b.concurrently {
+		sql.WriteString("CONCURRENTLY ")
+	}
+	sql.WriteString(b.name)
+
+	if b.withNoData {
+		sql.WriteString(" WITH NO DATA")
+	}
+
+	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
+	return
+}
+
+// Name sets the name of the materialized view.
+func (b *RefreshMaterializedViewBuilder) Name(name string) *RefreshMaterializedViewBuilder {
+	b.name = name
+	return b
+}
+
+// Concurrently refreshes the materialized view without locking out concurrent
+// selects. Requires a unique index on the materialized view.
+func (b *RefreshMaterializedViewBuilder) Concurrently() *RefreshMaterializedViewBuilder {
+	b.concurrently = true
+	return b
+}
+
+// WithNoData discards the contents of the materialized view, leaving it in
+// an unscannable state.
+func (b *RefreshMaterializedViewBuilder) WithNoData() *RefreshMaterializedViewBuilder {
+	b.withNoData = true
+	return b
+}
@@ -0,0 +1,42 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateMaterializedViewBuilderToSql(t *testing.T) {
+	query := Select("user_id", "count(*) AS orders").From("orders").GroupBy("user_id")
+
+	sql, args, err := CreateMaterializedView("order_stats", query).IfNotExists().WithNoData().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"CREATE MATERIALIZED VIEW IF NOT EXISTS order_stats AS "+
+			"SELECT user_id, count(*) AS orders FROM orders GROUP BY user_id WITH NO DATA", sql)
+	assert.Empty(t, args)
+}
+
+func TestCreateMaterializedViewBuilderToSqlErr(t *testing.T) {
+	_, _, err := CreateMaterializedView("v", nil).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = CreateMaterializedView("", Select("a")).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = CreateMaterializedView("v", Select("a").From("b").Where("c = ?", 1)).ToSql()
+	assert.Error(t, err)
+}
+
+func TestRefreshMaterializedViewBuilderToSql(t *testing.T) {
+	sql, _, err := RefreshMaterializedView("order_stats").Concurrently().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "REFRESH MATERIALIZED VIEW CONCURRENTLY order_stats", sql)
+
+	sql, _, err = RefreshMaterializedView("order_stats").WithNoData().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "REFRESH MATERIALIZED VIEW order_stats WITH NO DATA", sql)
+
+	_, _, err = RefreshMaterializedView("order_stats").Concurrently().WithNoData().ToSql()
+	assert.Error(t, err)
+}
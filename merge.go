@@ -0,0 +1,150 @@
+package sqrl
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Merge adds the parts of other to b, so feature modules can contribute
+// fragments to a shared base query. Parts are merged by these rules:
+//   - result columns, joins, GROUP BY and ORDER BY expressions, options,
+//     hints, prefixes and suffixes of other are appended, skipping those
+//     which render the same as one of b
+//   - WHERE and HAVING conditions and UNIONs are appended
+//   - the FROM clause of other is used if b has none; otherwise both must
+//     render the same
+//   - the smaller LIMIT is used; OFFSETs must be equal if both are set
+//   - DISTINCT is used if either query is DISTINCT
+//
+// Merge returns an error and leaves b unchanged if the queries conflict.
+//
+// Ex:
+//     base := Select("p.id", "p.title").From("posts p").Where("p.published")
+//     _, err := base.Merge(Select("a.name").Join("authors a ON a.id = p.author_id"))
+func (b *SelectBuilder) Merge(other *SelectBuilder) (*SelectBuilder, error) {
+	switch {
+	case len(other.fromParts) == 0:
+	case len(b.fromParts) == 0:
+		b.fromParts = append(b.fromParts, other.fromParts...)
+	default:
+		from, otherFrom := mergeKeys(b.fromParts), mergeKeys(other.fromParts)
+		if fmt.Sprint(from) != fmt.Sprint(otherFrom) {
+			return b, fmt.Errorf("merged queries select from different relations: %v and %v", from, otherFrom)
+		}
+	}
+
+	if other.offsetValid {
+		if b.offsetValid && b.offset != other.offset {
+			return b, fmt.Errorf("merged queries have different offsets: %d and %d", b.offset, other.offset)
+		}
+		b.Offset(other.offset)
+	}
+	if other.limitValid && (!b.limitValid || other.limit < b.limit) {
+		b.Limit(other.limit)
+	}
+
+	b.distinct = b.distinct || other.distinct
+	b.options = mergeStrings(b.options, other.options)
+	b.hints = mergeStrings(b.hints, other.hints)
+	b.groupBys = mergeStrings(b.groupBys, other.groupBys)
+	b.orderBys = mergeStrings(b.orderBys, other.orderBys)
+	b.fragments = mergeStrings(b.fragments, other.fragments)
+	b.joinKeys = mergeStrings(b.joinKeys, other.joinKeys)
+	if len(b.defaultOrderBys) == 0 {
+		b.defaultOrderBys = other.defaultOrderBys
+	}
+
+	b.columns = mergeParts(b.columns, other.columns)
+	b.joins = mergeParts(b.joins, other.joins)
+	b.whereParts = append(b.whereParts, other.whereParts...)
+	b.havingParts = append(b.havingParts, other.havingParts...)
+	b.union = append(b.union, other.union...)
+	b.unionAll = append(b.unionAll, other.unionAll...)
+
+	for _, e := range other.prefixes {
+		if !containsExpr(b.prefixes, e) {
+			b.prefixes = append(b.prefixes, e)
+		}
+	}
+	for _, e := range other.suffixes {
+		if !containsExpr(b.suffixes, e) {
+			b.suffixes = append(b.suffixes, e)
+		}
+	}
+	return b, nil
+}
+
+// mergeKey identifies a part by its SQL and args. Parts which fail to build
+// get a key of their own, so they are never merged away.
+func mergeKey(s Sqlizer) string {
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return fmt.Sprintf("%p", s)
+	}
+	return fmt.Sprintf("%s %#v", sql, args)
+}
+
+func mergeKeys(parts []Sqlizer) []string {
+	keys := make([]string, len(parts))
+	for i, p := range parts {
+		keys[i] = mergeKey(p)
+	}
+	return keys
+}
+
+// partSet holds the SQL and args of parts to find parts which render the
+// same. Parts which fail to build are never found, so they are never merged
+// away.
+type partSet map[string][][]interface{}
+
+// add adds p to the set and reports whether it was not in the set before.
+func (ps partSet) add(p Sqlizer) bool {
+	sql, args, err := p.ToSql()
+	if err != nil {
+		return true
+	}
+	for _, seen := range ps[sql] {
+		if len(seen) == 0 && len(args) == 0 || reflect.DeepEqual(seen, args) {
+			return false
+		}
+	}
+	ps[sql] = append(ps[sql], args)
+	return true
+}
+
+// mergeParts appends those of others to parts which render differently,
+// in their SQL or args, from all parts before them.
+func mergeParts(parts, others []Sqlizer) []Sqlizer {
+	seen := make(partSet, len(parts))
+	for _, p := range parts {
+		seen.add(p)
+	}
+	for _, p := range others {
+		if seen.add(p) {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+func mergeStrings(values, others []string) []string {
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		seen[v] = true
+	}
+	for _, v := range others {
+		if !seen[v] {
+			seen[v] = true
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func containsExpr(es exprs, e expr) bool {
+	seen := make(partSet, len(es))
+	for _, x := range es {
+		seen.add(x)
+	}
+	return !seen.add(e)
+}
@@ -0,0 +1,50 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectBuilderMerge(t *testing.T) {
+	base := Select("p.id", "p.title").From("posts p").Where("p.published = ?", true).OrderBy("p.id").Limit(50)
+
+	b, err := base.Merge(Select("p.id", "a.name").
+		Join("authors a ON a.id = p.author_id").
+		Where(Eq{"a.active": true}).
+		OrderBy("p.id", "a.name").
+		Limit(20))
+	assert.NoError(t, err)
+	assert.Equal(t, base, b)
+
+	b, err = b.Merge(Select("a.name").From("posts p").Join("authors a ON a.id = p.author_id").Offset(40).Distinct())
+	assert.NoError(t, err)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT DISTINCT p.id, p.title, a.name FROM posts p JOIN authors a ON a.id = p.author_id "+
+		"WHERE p.published = ? AND a.active = ? ORDER BY p.id, a.name LIMIT 20 OFFSET 40", sql)
+	assert.Equal(t, []interface{}{true, true}, args)
+}
+
+func TestSelectBuilderMergeFrom(t *testing.T) {
+	b, err := Select("id").Merge(Select().From("users"))
+	assert.NoError(t, err)
+	sql, _, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users", sql)
+}
+
+func TestSelectBuilderMergeConflicts(t *testing.T) {
+	b := Select("id").From("users").Offset(10)
+
+	_, err := b.Merge(Select("id").From("teams"))
+	assert.EqualError(t, err, "merged queries select from different relations: [users []interface {}(nil)] and [teams []interface {}(nil)]")
+
+	_, err = b.Merge(Select("id").Offset(20).Where("x"))
+	assert.EqualError(t, err, "merged queries have different offsets: 10 and 20")
+
+	sql, _, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users OFFSET 10", sql)
+}
@@ -0,0 +1,151 @@
+package sqrl
+
+import (
+	"context"
+	"github.com/jackc/pgx/v4"
+	"strings"
+	"time"
+)
+
+// MetricsCollector receives an observation for every query run through the
+// execution helpers (ExecWithContext, QueryWithContext, QueryRowWithContext
+// and the builder methods based on them).
+//
+// stmtType is the statement type ("select", "insert", "update", "delete" or
+// "other") and table the main table of the statement, if known. rows is the
+// number of affected rows for Exec and the number of read rows for queries.
+// Queries are observed once their rows are closed, so duration includes
+// reading the results.
+type MetricsCollector interface {
+	ObserveQuery(stmtType, table string, duration time.Duration, rows int64, err error)
+}
+
+// WithMetrics makes child builders report their queries to c. Passing nil
+// disables metrics. Other Sqlizers run by the execution helpers are reported
+// to the MetricsCollector of the StatementBuilderType carried by their
+// context, see FromContext.
+func (b StatementBuilderType) WithMetrics(c MetricsCollector) StatementBuilderType {
+	b.metrics = c
+	return b
+}
+
+// statementInfo returns statement type and main table of s.
+func statementInfo(s Sqlizer) (stmtType, table string) {
+	if tables := Tables(s); len(tables) > 0 {
+		table = tables[0]
+	}
+	return Kind(s).String(), table
+}
+
+// firstWord strips aliases like "users u" down to the table name.
+func firstWord(s string) string {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// queryObserver tracks a single execution of s: it reports it to the
+// MetricsCollector, the SlowQueryLogger and the cancellation hook, if set,
+// and wraps its errors in QueryErrors.
+type queryObserver struct {
+	collector MetricsCollector
+	slowLog   *SlowQueryLogger
+	cancelled func(ctx context.Context, q CancelledQuery)
+	omitArgs  bool
+	ctx       context.Context
+	s         Sqlizer
+	query     string
+	args      []interface{}
+	start     time.Time
+}
+
+func newQueryObserver(ctx context.Context, s Sqlizer, query string, args []interface{}) *queryObserver {
+	opts := runOptions(ctx, s)
+	return &queryObserver{
+		collector: opts.metrics,
+		slowLog:   slowQueryLogger(),
+		cancelled: loadCancellationHook(),
+		omitArgs:  opts.omitErrorArgs,
+		ctx:       ctx,
+		s:         s,
+		query:     query,
+		args:      args,
+		start:     time.Now(),
+	}
+}
+
+func (o *queryObserver) observe(rows int64, err error) {
+	if o.collector == nil && o.slowLog == nil && o.cancelled == nil {
+		return
+	}
+	duration := time.Since(o.start)
+	if o.cancelled != nil && err != nil && o.ctx.Err() != nil {
+		stmtType, table := statementInfo(o.s)
+		o.cancelled(o.ctx, CancelledQuery{StmtType: stmtType, Table: table, SQL: o.query, Duration: duration, Err: o.ctx.Err()})
+	}
+	if o.collector != nil {
+		stmtType, table := statementInfo(o.s)
+		o.collector.ObserveQuery(stmtType, table, duration, rows, err)
+	}
+	if o.slowLog != nil {
+		o.slowLog.observe(o.s, o.query, o.args, duration, err)
+	}
+}
+
+// observedRows counts the rows read and reports them once closed. Its
+// errors are wrapped in QueryErrors.
+type observedRows struct {
+	pgx.Rows
+	observer *queryObserver
+	n        int64
+	done     bool
+}
+
+func (r *observedRows) Next() bool {
+	if r.Rows.Next() {
+		r.n++
+		return true
+	}
+	r.finish()
+	return false
+}
+
+func (r *observedRows) Err() error {
+	return r.observer.wrapErr(r.Rows.Err())
+}
+
+func (r *observedRows) Close() {
+	r.Rows.Close()
+	r.finish()
+}
+
+func (r *observedRows) finish() {
+	if r.done {
+		return
+	}
+	r.done = true
+	r.observer.observe(r.n, r.Rows.Err())
+}
+
+// observedRow reports the query once its row is scanned. Its errors, except
+// pgx.ErrNoRows, are wrapped in QueryErrors.
+type observedRow struct {
+	RowScanner
+	observer *queryObserver
+}
+
+func (r *observedRow) Scan(dest ...interface{}) error {
+	err := r.RowScanner.Scan(dest...)
+	switch err {
+	case nil:
+		r.observer.observe(1, nil)
+	case pgx.ErrNoRows:
+		r.observer.observe(0, nil)
+	default:
+		r.observer.observe(0, err)
+		err = r.observer.wrapErr(err)
+	}
+	return err
+}
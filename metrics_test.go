@@ -0,0 +1,92 @@
+package sqrl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type observation struct {
+	stmtType string
+	table    string
+	rows     int64
+	err      error
+}
+
+type collectorStub struct {
+	observations []observation
+}
+
+func (c *collectorStub) ObserveQuery(stmtType, table string, duration time.Duration, rows int64, err error) {
+	c.observations = append(c.observations, observation{stmtType, table, rows, err})
+}
+
+// withCollector returns a collector stub and a StatementBuilderType
+// reporting to it.
+func withCollector() (*collectorStub, StatementBuilderType) {
+	c := &collectorStub{}
+	return c, StatementBuilder.WithMetrics(c)
+}
+
+func TestMetricsExec(t *testing.T) {
+	c, sb := withCollector()
+	pool := newPoolStub()
+	pool.stub.tag = pgconn.CommandTag("UPDATE 3")
+
+	_, err := sb.Update("users u").Set("a", 1).ExecContext(context.Background(), pool)
+	assert.NoError(t, err)
+
+	pool.stub.tag = nil
+	pool.stub.err = errors.New("failed")
+	_, err = sb.Delete("sessions").ExecContext(context.Background(), pool)
+	assert.Error(t, err)
+
+	_, err = sb.Insert("").ExecContext(context.Background(), pool)
+	assert.Error(t, err)
+
+	assert.Equal(t, []observation{
+		{"update", "users", 3, nil},
+		{"delete", "sessions", 0, pool.stub.err},
+	}, c.observations)
+}
+
+func TestMetricsQuery(t *testing.T) {
+	c, sb := withCollector()
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{1}, {2}}, {{3}}, {}}
+
+	rows, err := sb.Select("id").From("users").QueryContext(context.Background(), pool)
+	assert.NoError(t, err)
+	for rows.Next() {
+	}
+	rows.Close()
+
+	var id int
+	assert.NoError(t, sb.Select("id").From("orders o").Scan(context.Background(), pool, &id))
+	assert.Error(t, sb.Select("id").From("orders").Scan(context.Background(), pool, &id))
+
+	ctx := WithStatementBuilder(context.Background(), sb)
+	assert.NoError(t, QueryEach(ctx, pool, Expr("SELECT 1"), func(rows pgx.Rows) error { return nil }))
+	assert.NoError(t, QueryEach(context.Background(), pool, Expr("SELECT 2"), func(rows pgx.Rows) error { return nil }))
+
+	assert.Equal(t, []observation{
+		{"select", "users", 2, nil},
+		{"select", "orders", 1, nil},
+		{"select", "orders", 0, nil},
+		{"other", "", 0, nil},
+	}, c.observations)
+}
+
+func TestMetricsDisabled(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{1}}}
+
+	ids, err := Pluck[int](context.Background(), pool, Select("id").From("users"))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, ids)
+}
@@ -0,0 +1,158 @@
+package sqrl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"io"
+	"net"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultFailoverCooldown is how long a MultiRunner skips a pool after a
+// connection error, unless set with Cooldown.
+const DefaultFailoverCooldown = 30 * time.Second
+
+// MultiRunner runs read-only statements against a primary pool and fails
+// over to standby pools on connection errors. Pools that failed are skipped
+// for a cooldown, after which they are tried again.
+//
+// Only select statements are run, as other statements may have been
+// applied before the connection failed.
+//
+// Ex:
+//     runner := NewMultiRunner(replica1, replica2)
+//     rows, err := runner.QueryContext(ctx, Select("*").From("report"))
+type MultiRunner struct {
+	pools    []instapgxpool.Pool
+	failedAt []int64
+	cooldown time.Duration
+	failover func(err error) bool
+}
+
+// NewMultiRunner creates a MultiRunner trying primary first and standbys in
+// the given order.
+func NewMultiRunner(primary instapgxpool.Pool, standbys ...instapgxpool.Pool) *MultiRunner {
+	pools := append([]instapgxpool.Pool{primary}, standbys...)
+	return &MultiRunner{
+		pools:    pools,
+		failedAt: make([]int64, len(pools)),
+		cooldown: DefaultFailoverCooldown,
+		failover: IsConnectionError,
+	}
+}
+
+// Cooldown sets how long a pool is skipped after a connection error.
+func (m *MultiRunner) Cooldown(d time.Duration) *MultiRunner {
+	m.cooldown = d
+	return m
+}
+
+// FailoverOn sets the function deciding which errors fail over to the next
+// pool. It defaults to IsConnectionError.
+func (m *MultiRunner) FailoverOn(fn func(err error) bool) *MultiRunner {
+	m.failover = fn
+	return m
+}
+
+// Healthy reports for every pool, primary first, whether it is used or
+// skipped after a connection error.
+func (m *MultiRunner) Healthy() []bool {
+	now := time.Now().UnixNano()
+	healthy := make([]bool, len(m.pools))
+	for i := range m.pools {
+		failedAt := atomic.LoadInt64(&m.failedAt[i])
+		healthy[i] = failedAt == 0 || time.Duration(now-failedAt) >= m.cooldown
+	}
+	return healthy
+}
+
+// QueryContext runs the query built by s on the first healthy pool, failing
+// over to the next one on connection errors.
+func (m *MultiRunner) QueryContext(ctx context.Context, s Sqlizer) (rows pgx.Rows, err error) {
+	err = m.run(s, func(pool instapgxpool.Pool) error {
+		rows, err = QueryWithContext(ctx, pool, s)
+		return err
+	})
+	return rows, err
+}
+
+// QueryRowContext runs the query built by s on the first healthy pool,
+// failing over to the next one on connection errors.
+//
+// Like pgx, the query is only run on Scan.
+func (m *MultiRunner) QueryRowContext(ctx context.Context, s Sqlizer) RowScanner {
+	return &multiRow{m: m, ctx: ctx, s: s}
+}
+
+type multiRow struct {
+	m   *MultiRunner
+	ctx context.Context
+	s   Sqlizer
+}
+
+func (r *multiRow) Scan(dest ...interface{}) error {
+	return r.m.run(r.s, func(pool instapgxpool.Pool) error {
+		return QueryRowWithContext(r.ctx, pool, r.s).Scan(dest...)
+	})
+}
+
+// run calls fn with the healthy pools in order until it does not fail with
+// a connection error. If all pools are down, all of them are tried anyway.
+func (m *MultiRunner) run(s Sqlizer, fn func(pool instapgxpool.Pool) error) error {
+	if kind := Kind(s); kind != KindSelect {
+		return fmt.Errorf("failover is only supported for select statements, got %s", kind)
+	}
+
+	healthy := m.Healthy()
+	order := make([]int, 0, len(m.pools))
+	for i := range m.pools {
+		if healthy[i] {
+			order = append(order, i)
+		}
+	}
+	for i := range m.pools {
+		if !healthy[i] {
+			order = append(order, i)
+		}
+	}
+
+	var err error
+	for _, i := range order {
+		err = fn(m.pools[i])
+		if err == nil || !m.failover(err) {
+			atomic.StoreInt64(&m.failedAt[i], 0)
+			return err
+		}
+		atomic.StoreInt64(&m.failedAt[i], time.Now().UnixNano())
+	}
+	return err
+}
+
+// IsConnectionError reports whether err is caused by a broken or refused
+// connection or a server shutting down, i.e. whether the statement may
+// succeed on another server.
+func IsConnectionError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "57P01", "57P02", "57P03": // admin_shutdown, crash_shutdown, cannot_connect_now
+			return true
+		}
+		return strings.HasPrefix(pgErr.Code, "08") // connection_exception
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package sqrl
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiRunnerFailover(t *testing.T) {
+	primary, standby := newPoolStub(), newPoolStub()
+	primary.stub.err = &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+	standby.stub.results = [][][]interface{}{{{1}}, {{2}}, {{3}}}
+
+	runner := NewMultiRunner(primary, standby)
+
+	var v int
+	assert.NoError(t, runner.QueryRowContext(context.Background(), Select("v").From("t")).Scan(&v))
+	assert.Equal(t, 1, v)
+	assert.Equal(t, []bool{false, true}, runner.Healthy())
+
+	// The primary is skipped while it is down.
+	rows, err := runner.QueryContext(context.Background(), Select("v").From("t"))
+	assert.NoError(t, err)
+	rows.Close()
+	assert.Len(t, primary.stub.sqls, 1)
+	assert.Len(t, standby.stub.sqls, 2)
+
+	// Once the cooldown passed, the primary is tried again.
+	runner.Cooldown(0)
+	primary.stub.err = nil
+	primary.stub.results = [][][]interface{}{{{4}}}
+	assert.NoError(t, runner.QueryRowContext(context.Background(), Select("v").From("t")).Scan(&v))
+	assert.Len(t, primary.stub.sqls, 2)
+	assert.Equal(t, 4, v)
+	assert.Equal(t, []bool{true, true}, runner.Healthy())
+}
+
+func TestMultiRunnerAllDown(t *testing.T) {
+	primary, standby := newPoolStub(), newPoolStub()
+	primary.stub.err = io.EOF
+	standby.stub.err = &pgconn.PgError{Code: "57P03"}
+
+	runner := NewMultiRunner(primary, standby).Cooldown(time.Hour)
+
+	_, err := runner.QueryContext(context.Background(), Select("v").From("t"))
+	assert.True(t, errors.Is(err, standby.stub.err))
+	assert.Equal(t, []bool{false, false}, runner.Healthy())
+
+	_, err = runner.QueryContext(context.Background(), Select("v").From("t"))
+	assert.Error(t, err)
+	assert.Len(t, primary.stub.sqls, 2)
+	assert.Len(t, standby.stub.sqls, 2)
+}
+
+func TestMultiRunnerNoFailover(t *testing.T) {
+	primary, standby := newPoolStub(), newPoolStub()
+	primary.stub.err = &pgconn.PgError{Code: "42P01"}
+
+	runner := NewMultiRunner(primary, standby)
+	_, err := runner.QueryContext(context.Background(), Select("v").From("t"))
+	assert.True(t, errors.Is(err, primary.stub.err))
+	assert.Empty(t, standby.stub.sqls)
+	assert.Equal(t, []bool{true, true}, runner.Healthy())
+
+	_, err = runner.QueryContext(context.Background(), Update("t").Set("v", 1))
+	assert.EqualError(t, err, "failover is only supported for select statements, got update")
+}
+
+func TestIsConnectionError(t *testing.T) {
+	assert.True(t, IsConnectionError(&net.OpError{Op: "read", Err: errors.New("reset")}))
+	assert.True(t, IsConnectionError(io.ErrUnexpectedEOF))
+	assert.True(t, IsConnectionError(&pgconn.PgError{Code: "08006"}))
+	assert.True(t, IsConnectionError(&pgconn.PgError{Code: "57P01"}))
+	assert.False(t, IsConnectionError(&pgconn.PgError{Code: "23505"}))
+	assert.False(t, IsConnectionError(errors.New("boom")))
+}
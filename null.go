@@ -0,0 +1,94 @@
+package sqrl
+
+import (
+	"reflect"
+	"sort"
+)
+
+// NullIfZero returns nil if v is the zero value of its type, so it binds as
+// NULL, and v otherwise. Pointers are dereferenced first.
+//
+// Ex:
+//     Insert("users").Columns("name", "deleted_at").Values(name, NullIfZero(deletedAt))
+func NullIfZero(v interface{}) interface{} {
+	v = derefArg(v)
+	if isZero(v) {
+		return nil
+	}
+	return v
+}
+
+// NullString returns nil for an empty s, so it binds as NULL, and s
+// otherwise.
+func NullString(s string) interface{} {
+	if len(s) == 0 {
+		return nil
+	}
+	return s
+}
+
+// NullInt64 returns nil for i == 0, so it binds as NULL, and i otherwise.
+func NullInt64(i int64) interface{} {
+	if i == 0 {
+		return nil
+	}
+	return i
+}
+
+// isZero reports whether v is nil or the zero value of its type.
+func isZero(v interface{}) bool {
+	return v == nil || reflect.ValueOf(v).IsZero()
+}
+
+// nullIfZeroColumns is a set of columns whose zero values bind as NULL.
+type nullIfZeroColumns map[string]bool
+
+func (c nullIfZeroColumns) add(columns []string) nullIfZeroColumns {
+	if c == nil {
+		c = make(nullIfZeroColumns, len(columns))
+	}
+	for _, column := range columns {
+		c[column] = true
+	}
+	return c
+}
+
+// columns returns the columns in c in sorted order.
+func (c nullIfZeroColumns) columns() []string {
+	if len(c) == 0 {
+		return nil
+	}
+	columns := make([]string, 0, len(c))
+	for column := range c {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// value returns the value to bind for column, nil if val is zero and column
+// is in c.
+func (c nullIfZeroColumns) value(column string, val interface{}) interface{} {
+	if c[column] {
+		return NullIfZero(val)
+	}
+	return val
+}
+
+// NullIfZero makes the query bind zero values of columns as NULL, like
+// wrapping them in NullIfZero.
+//
+// Ex:
+//     Insert("users").NullIfZero("nick").Columns("name", "nick").Values("moe", "")
+//     == "INSERT INTO users (name,nick) VALUES (?,?)", "moe", nil
+func (b *InsertBuilder) NullIfZero(columns ...string) *InsertBuilder {
+	b.nullIfZero = b.nullIfZero.add(columns)
+	return b
+}
+
+// NullIfZero makes the query bind zero values set for columns as NULL, like
+// wrapping them in NullIfZero.
+func (b *UpdateBuilder) NullIfZero(columns ...string) *UpdateBuilder {
+	b.nullIfZero = b.nullIfZero.add(columns)
+	return b
+}
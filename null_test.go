@@ -0,0 +1,47 @@
+package sqrl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullIfZero(t *testing.T) {
+	empty := ""
+	assert.Nil(t, NullIfZero(""))
+	assert.Nil(t, NullIfZero(0))
+	assert.Nil(t, NullIfZero(time.Time{}))
+	assert.Nil(t, NullIfZero(&empty))
+	assert.Nil(t, NullIfZero((*int)(nil)))
+	assert.Equal(t, "a", NullIfZero("a"))
+
+	assert.Nil(t, NullString(""))
+	assert.Equal(t, "a", NullString("a"))
+	assert.Nil(t, NullInt64(0))
+	assert.Equal(t, int64(1), NullInt64(1))
+}
+
+func TestInsertBuilderNullIfZero(t *testing.T) {
+	sql, args, err := Insert("users").
+		NullIfZero("nick", "age").
+		Columns("name", "nick", "age").
+		Values("", "", 0).
+		Values("moe", "mo", 42).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (name,nick,age) VALUES (?,?,?),(?,?,?)", sql)
+	assert.Equal(t, []interface{}{"", nil, nil, "moe", "mo", 42}, args)
+}
+
+func TestUpdateBuilderNullIfZero(t *testing.T) {
+	sql, args, err := Update("users").
+		NullIfZero("nick").
+		Set("name", "").
+		Set("nick", "").
+		Where("id = ?", 1).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = ?, nick = ? WHERE id = ?", sql)
+	assert.Equal(t, []interface{}{"", nil, 1}, args)
+}
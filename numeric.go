@@ -0,0 +1,109 @@
+package sqrl
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// Numeric converts value into Postgres NUMERIC. It is bound as text with a
+// ::numeric cast, so no precision is lost to float conversion on the way.
+//
+// Valid values are *big.Rat with an exact decimal representation, *big.Int,
+// *big.Float, integers, floats, numeric strings and fmt.Stringers like
+// shopspring/decimal.Decimal. A nil value binds as NULL.
+//
+// Ex:
+//     Insert("payments").Columns("amount").Values(Numeric(decimal.RequireFromString("19.99")))
+//     == "INSERT INTO payments (amount) VALUES (?::numeric)", "19.99"
+func Numeric(value interface{}) Sqlizer {
+	return numeric{value}
+}
+
+type numeric struct {
+	value interface{}
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (n numeric) ToSql() (string, []interface{}, error) {
+	if n.value == nil {
+		return "?::numeric", []interface{}{nil}, nil
+	}
+	text, err := numericText(n.value)
+	if err != nil {
+		return "", nil, err
+	}
+	return "?::numeric", []interface{}{text}, nil
+}
+
+func numericText(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case *big.Rat:
+		scale, ok := decimalScale(v.Denom())
+		if !ok {
+			return "", fmt.Errorf("numeric value %s has no exact decimal representation", v.String())
+		}
+		return v.FloatString(scale), nil
+	case *big.Int:
+		return v.String(), nil
+	case *big.Float:
+		return v.Text('f', -1), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case string:
+		return v, nil
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+	return "", fmt.Errorf("numeric value of type %T is not supported", value)
+}
+
+// decimalScale returns the number of decimal places needed to represent a
+// fraction with denominator denom exactly, which is possible only if its
+// prime factors are 2 and 5.
+func decimalScale(denom *big.Int) (int, bool) {
+	d := new(big.Int).Set(denom)
+	two, five := big.NewInt(2), big.NewInt(5)
+	mod := new(big.Int)
+
+	twos, fives := 0, 0
+	for d.Cmp(big.NewInt(1)) > 0 {
+		switch {
+		case mod.Mod(d, two).Sign() == 0:
+			d.Quo(d, two)
+			twos++
+		case mod.Mod(d, five).Sign() == 0:
+			d.Quo(d, five)
+			fives++
+		default:
+			return 0, false
+		}
+	}
+	if twos > fives {
+		return twos, true
+	}
+	return fives, true
+}
+
+// Sum builds a sum of expr computed as NUMERIC, so summing float or money
+// columns does not lose precision.
+//
+// Ex:
+//     Select().Column(Alias(Sum("price * quantity"), "total")).From("order_items")
+//     == "SELECT (sum((price * quantity)::numeric)) AS total FROM order_items"
+func Sum(expr string) Sqlizer {
+	return Expr("sum((" + expr + ")::numeric)")
+}
+
+// Round rounds value, e.g. a Sum or Numeric, as NUMERIC to scale decimal
+// places.
+//
+// Ex:
+//     Round(Sum("amount"), 2) == "round((sum((amount)::numeric))::numeric, 2)"
+func Round(value Sqlizer, scale int) Sqlizer {
+	return Expr("round((?)::numeric, "+strconv.Itoa(scale)+")", value)
+}
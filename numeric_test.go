@@ -0,0 +1,49 @@
+package sqrl
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type decimalStub string
+
+func (d decimalStub) String() string { return string(d) }
+
+func TestNumeric(t *testing.T) {
+	sql, args, err := Insert("payments").
+		Columns("a", "b", "c", "d", "e", "f", "g").
+		Values(
+			Numeric(big.NewRat(1999, 100)),
+			Numeric(big.NewRat(1, 8)),
+			Numeric(new(big.Int).Exp(big.NewInt(10), big.NewInt(30), nil)),
+			Numeric(decimalStub("0.1")),
+			Numeric(0.1),
+			Numeric(42),
+			Numeric(nil),
+		).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO payments (a,b,c,d,e,f,g) VALUES "+
+		"($1::numeric,$2::numeric,$3::numeric,$4::numeric,$5::numeric,$6::numeric,$7::numeric)", sql)
+	assert.Equal(t, []interface{}{"19.99", "0.125", "1000000000000000000000000000000", "0.1", "0.1", "42", nil}, args)
+
+	_, _, err = Numeric(big.NewRat(1, 3)).ToSql()
+	assert.EqualError(t, err, "numeric value 1/3 has no exact decimal representation")
+
+	_, _, err = Numeric(true).ToSql()
+	assert.EqualError(t, err, "numeric value of type bool is not supported")
+}
+
+func TestSumRound(t *testing.T) {
+	sql, args, err := Select().
+		Column(Alias(Round(Sum("price * quantity"), 2), "total")).
+		From("order_items").
+		Where(Gt{"price": Numeric("9.99")}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT (round((sum((price * quantity)::numeric))::numeric, 2)) AS total FROM order_items WHERE price > ?::numeric", sql)
+	assert.Equal(t, []interface{}{"9.99"}, args)
+}
@@ -0,0 +1,191 @@
+package sqrl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"sort"
+	"strings"
+)
+
+type onConflict struct {
+	target      []string
+	constraint  string
+	whereParts  []Sqlizer
+	doNothing   bool
+	setClauses  []setClause
+	updateWhere []Sqlizer
+}
+
+func (c *onConflict) appendToSql(w *bytes.Buffer, args []interface{}) ([]interface{}, error) {
+	if !c.doNothing && len(c.setClauses) == 0 {
+		return nil, fmt.Errorf("on conflict clause requires DO NOTHING or DO UPDATE")
+	}
+	if c.doNothing && (len(c.setClauses) > 0 || len(c.updateWhere) > 0) {
+		return nil, fmt.Errorf("on conflict clause cannot have both DO NOTHING and DO UPDATE")
+	}
+	if len(c.whereParts) > 0 && len(c.target) == 0 {
+		return nil, fmt.Errorf("on conflict index predicate requires target columns")
+	}
+	if len(c.setClauses) > 0 && len(c.target) == 0 && len(c.constraint) == 0 {
+		return nil, fmt.Errorf("on conflict DO UPDATE requires a conflict target")
+	}
+
+	var err error
+	w.WriteString(" ON CONFLICT")
+	if len(c.constraint) > 0 {
+		w.WriteString(" ON CONSTRAINT ")
+		w.WriteString(c.constraint)
+	} else if len(c.target) > 0 {
+		w.WriteString(" (")
+		w.WriteString(strings.Join(c.target, ","))
+		w.WriteString(")")
+	}
+
+	if len(c.whereParts) > 0 {
+		args, err = appendConditionsToSql(c.whereParts, w, " WHERE ", args)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.doNothing {
+		w.WriteString(" DO NOTHING")
+		return args, nil
+	}
+
+	w.WriteString(" DO UPDATE SET ")
+	for i, setClause := range c.setClauses {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		w.WriteString(setClause.column)
+		w.WriteString(" = ")
+		val := setClause.value
+		if sb, ok := val.(*SelectBuilder); ok {
+			val = Subquery(sb)
+		}
+		switch typedVal := encodeArg(val).(type) {
+		case Sqlizer:
+			valSql, valArgs, err := typedVal.ToSql()
+			if err != nil {
+				return nil, err
+			}
+			w.WriteString(valSql)
+			args = append(args, valArgs...)
+		default:
+			w.WriteString("?")
+			args = append(args, typedVal)
+		}
+	}
+
+	if len(c.updateWhere) > 0 {
+		args, err = appendConditionsToSql(c.updateWhere, w, " WHERE ", args)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+// OnConflictBuilder builds the ON CONFLICT clause of an InsertBuilder.
+//
+// It embeds the InsertBuilder, so the statement can be finished or run
+// directly from it.
+type OnConflictBuilder struct {
+	*InsertBuilder
+}
+
+// OnConflict adds an ON CONFLICT clause with the given conflict target
+// columns to the query. Without columns any conflict is handled, which is
+// only valid with DoNothing.
+//
+// Ex:
+//     Insert("users").Columns("email", "name").Values("a@b.c", "moe").
+//         OnConflict("email").Where(Eq{"deleted_at": nil}).
+//         DoUpdateSet("name", Expr("EXCLUDED.name"))
+//     == "INSERT INTO users (email,name) VALUES (?,?) ON CONFLICT (email) WHERE deleted_at IS NULL DO UPDATE SET name = EXCLUDED.name"
+//
+// ON CONFLICT is PostgreSQL specific extension
+func (b *InsertBuilder) OnConflict(columns ...string) *OnConflictBuilder {
+	b.onConflict = &onConflict{target: columns}
+	return &OnConflictBuilder{b}
+}
+
+// OnConflictOnConstraint adds an ON CONFLICT ON CONSTRAINT clause to the
+// query.
+//
+// ON CONFLICT is PostgreSQL specific extension
+func (b *InsertBuilder) OnConflictOnConstraint(name string) *OnConflictBuilder {
+	b.onConflict = &onConflict{constraint: name}
+	return &OnConflictBuilder{b}
+}
+
+// Ignore adds an ON CONFLICT DO NOTHING clause to the query, skipping rows
+// which conflict with an existing row on any unique constraint.
+//
+// Ex:
+//     Insert("users").Columns("email").Values("a@b.c").Ignore()
+//     == "INSERT INTO users (email) VALUES (?) ON CONFLICT DO NOTHING"
+//
+// ON CONFLICT is PostgreSQL specific extension
+func (b *InsertBuilder) Ignore() *InsertBuilder {
+	return b.OnConflict().DoNothing()
+}
+
+// ExecInsertedContext builds and Execs the query using given context and
+// reports whether any row was inserted, e.g. to tell whether an insert with
+// Ignore was skipped.
+func (b *InsertBuilder) ExecInsertedContext(ctx context.Context, pool instapgxpool.Pool) (bool, error) {
+	tag, err := b.ExecContext(ctx, pool)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ExecInsertedAttached is like ExecInsertedContext, using the pool set with RunWithPool.
+func (b *InsertBuilder) ExecInsertedAttached(ctx context.Context) (bool, error) {
+	return b.ExecInsertedContext(ctx, nil)
+}
+
+// Where adds an index predicate to the conflict target, selecting a partial
+// unique index. Multiple calls are joined with AND.
+func (b *OnConflictBuilder) Where(pred interface{}, args ...interface{}) *OnConflictBuilder {
+	b.onConflict.whereParts = append(b.onConflict.whereParts, newWherePart(pred, args...))
+	return b
+}
+
+// DoNothing sets the conflict action to DO NOTHING.
+func (b *OnConflictBuilder) DoNothing() *InsertBuilder {
+	b.onConflict.doNothing = true
+	return b.InsertBuilder
+}
+
+// DoUpdateSet adds a SET clause to the DO UPDATE conflict action.
+func (b *OnConflictBuilder) DoUpdateSet(column string, value interface{}) *OnConflictBuilder {
+	b.onConflict.setClauses = append(b.onConflict.setClauses, setClause{column: column, value: value})
+	return b
+}
+
+// DoUpdateSetMap is a convenience method which calls .DoUpdateSet for each
+// key/value pair in clauses.
+func (b *OnConflictBuilder) DoUpdateSetMap(clauses map[string]interface{}) *OnConflictBuilder {
+	keys := make([]string, 0, len(clauses))
+	for key := range clauses {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		b = b.DoUpdateSet(key, clauses[key])
+	}
+	return b
+}
+
+// DoUpdateWhere adds a WHERE condition to the DO UPDATE conflict action.
+// Multiple calls are joined with AND.
+func (b *OnConflictBuilder) DoUpdateWhere(pred interface{}, args ...interface{}) *OnConflictBuilder {
+	b.onConflict.updateWhere = append(b.onConflict.updateWhere, newWherePart(pred, args...))
+	return b
+}
@@ -0,0 +1,90 @@
+package sqrl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertBuilderOnConflictPartialIndex(t *testing.T) {
+	sql, args, err := Insert("users").
+		Columns("email", "name").
+		Values("a@b.c", "moe").
+		OnConflict("email").
+		Where(Eq{"deleted_at": nil}).
+		Where("tenant = ?", 7).
+		DoUpdateSet("name", Expr("EXCLUDED.name")).
+		DoUpdateSet("visits", Expr("users.visits + ?", 1)).
+		DoUpdateWhere("users.locked = ?", false).
+		Returning("id").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"INSERT INTO users (email,name) VALUES ($1,$2) "+
+			"ON CONFLICT (email) WHERE deleted_at IS NULL AND tenant = $3 "+
+			"DO UPDATE SET name = EXCLUDED.name, visits = users.visits + $4 WHERE users.locked = $5 "+
+			"RETURNING id", sql)
+	assert.Equal(t, []interface{}{"a@b.c", "moe", 7, 1, false}, args)
+}
+
+func TestInsertBuilderOnConflictDoNothing(t *testing.T) {
+	sql, args, err := Insert("users").Columns("email").Values("a@b.c").
+		OnConflict().DoNothing().
+		Suffix("RETURNING id").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (email) VALUES (?) ON CONFLICT DO NOTHING RETURNING id", sql)
+	assert.Equal(t, []interface{}{"a@b.c"}, args)
+}
+
+func TestInsertBuilderOnConflictOnConstraint(t *testing.T) {
+	sql, args, err := Insert("users").Columns("email", "name").Values("a@b.c", "moe").
+		OnConflictOnConstraint("users_email_key").
+		DoUpdateSetMap(map[string]interface{}{"name": "larry", "email": Expr("EXCLUDED.email")}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (email,name) VALUES (?,?) ON CONFLICT ON CONSTRAINT users_email_key DO UPDATE SET email = EXCLUDED.email, name = ?", sql)
+	assert.Equal(t, []interface{}{"a@b.c", "moe", "larry"}, args)
+}
+
+func TestInsertBuilderOnConflictErrors(t *testing.T) {
+	_, _, err := Insert("users").Values(1).OnConflict("id").ToSql()
+	assert.EqualError(t, err, "on conflict clause requires DO NOTHING or DO UPDATE")
+
+	_, _, err = Insert("users").Values(1).OnConflict().DoUpdateSet("a", 1).ToSql()
+	assert.EqualError(t, err, "on conflict DO UPDATE requires a conflict target")
+
+	_, _, err = Insert("users").Values(1).OnConflict().Where("a").DoNothing().ToSql()
+	assert.EqualError(t, err, "on conflict index predicate requires target columns")
+
+	_, _, err = Insert("users").Values(1).OnConflict("id").DoUpdateSet("a", 1).DoNothing().ToSql()
+	assert.EqualError(t, err, "on conflict clause cannot have both DO NOTHING and DO UPDATE")
+
+	_, _, err = Insert("users").Values(1).OnConflict("id").Where(1).DoNothing().ToSql()
+	assert.Error(t, err)
+}
+
+func TestInsertBuilderIgnore(t *testing.T) {
+	sql, args, err := Insert("users").Columns("email").Values("a@b.c").Ignore().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (email) VALUES (?) ON CONFLICT DO NOTHING", sql)
+	assert.Equal(t, []interface{}{"a@b.c"}, args)
+}
+
+func TestInsertBuilderExecInsertedContext(t *testing.T) {
+	pool := newPoolStub()
+	b := Insert("users").Columns("email").Values("a@b.c").Ignore()
+
+	pool.stub.tag = pgconn.CommandTag("INSERT 0 1")
+	inserted, err := b.ExecInsertedContext(context.Background(), pool)
+	assert.NoError(t, err)
+	assert.True(t, inserted)
+
+	pool.stub.tag = pgconn.CommandTag("INSERT 0 0")
+	inserted, err = b.ExecInsertedContext(context.Background(), pool)
+	assert.NoError(t, err)
+	assert.False(t, inserted)
+}
@@ -19,10 +19,10 @@ func (p part) ToSql() (sql string, args []interface{}, err error) {
 	case nil:
 		// no-op
 	case Sqlizer:
-		sql, args, err = pred.ToSql()
+		sql, args, err = nested(pred).ToSql()
 	case string:
 		sql = pred
-		args = p.args
+		args = encodeArgs(p.args)
 	default:
 		err = fmt.Errorf("expected string or Sqlizer, not %T", pred)
 	}
@@ -30,15 +30,16 @@ func (p part) ToSql() (sql string, args []interface{}, err error) {
 }
 
 func appendToSql(parts []Sqlizer, w io.Writer, sep string, args []interface{}) ([]interface{}, error) {
-	for i, p := range parts {
-		partSql, partArgs, err := p.ToSql()
+	written := 0
+	for _, p := range parts {
+		partSql, partArgs, err := nested(p).ToSql()
 		if err != nil {
 			return nil, err
 		} else if len(partSql) == 0 {
 			continue
 		}
 
-		if i > 0 {
+		if written > 0 {
 			_, err := io.WriteString(w, sep)
 			if err != nil {
 				return nil, err
@@ -50,6 +51,7 @@ func appendToSql(parts []Sqlizer, w io.Writer, sep string, args []interface{}) (
 			return nil, err
 		}
 		args = append(args, partArgs...)
+		written++
 	}
 	return args, nil
 }
@@ -0,0 +1,76 @@
+package sqrl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RangeBound returns the bound of a range partition covering values from
+// (inclusive) to (exclusive). Values are rendered as literals, as partition
+// bounds can not be bound parameters; use Expr("MINVALUE") or Expr("MAXVALUE")
+// for unbounded ranges.
+//
+// Ex:
+//     .PartitionOf("events", RangeBound("2020-01-01", "2020-02-01"))
+func RangeBound(from, to interface{}) Sqlizer {
+	return rangeBound{from: from, to: to}
+}
+
+// ListBound returns the bound of a list partition holding the given values.
+func ListBound(values ...interface{}) Sqlizer {
+	return listBound(values)
+}
+
+// HashBound returns the bound of a hash partition.
+func HashBound(modulus, remainder int) Sqlizer {
+	return hashBound{modulus: modulus, remainder: remainder}
+}
+
+// DefaultBound returns the bound of a default partition.
+func DefaultBound() Sqlizer {
+	return Expr("DEFAULT")
+}
+
+type rangeBound struct {
+	from interface{}
+	to   interface{}
+}
+
+func (rb rangeBound) ToSql() (sql string, args []interface{}, err error) {
+	from, err := escapedLiteral(rb.from)
+	if err != nil {
+		return
+	}
+	to, err := escapedLiteral(rb.to)
+	if err != nil {
+		return
+	}
+	return fmt.Sprintf("FOR VALUES FROM (%s) TO (%s)", from, to), nil, nil
+}
+
+type listBound []interface{}
+
+func (lb listBound) ToSql() (sql string, args []interface{}, err error) {
+	if len(lb) == 0 {
+		return "", nil, fmt.Errorf("list partition bounds must have at least one value")
+	}
+	values := make([]string, len(lb))
+	for i, v := range lb {
+		if values[i], err = escapedLiteral(v); err != nil {
+			return
+		}
+	}
+	return fmt.Sprintf("FOR VALUES IN (%s)", strings.Join(values, ", ")), nil, nil
+}
+
+type hashBound struct {
+	modulus   int
+	remainder int
+}
+
+func (hb hashBound) ToSql() (sql string, args []interface{}, err error) {
+	if hb.modulus < 1 || hb.remainder < 0 || hb.remainder >= hb.modulus {
+		return "", nil, fmt.Errorf("invalid hash partition bound with modulus %d and remainder %d", hb.modulus, hb.remainder)
+	}
+	return fmt.Sprintf("FOR VALUES WITH (MODULUS %d, REMAINDER %d)", hb.modulus, hb.remainder), nil, nil
+}
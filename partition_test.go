@@ -0,0 +1,44 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartitionBounds(t *testing.T) {
+	sql, args, err := RangeBound(Expr("MINVALUE"), 100).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "FOR VALUES FROM (MINVALUE) TO (100)", sql)
+	assert.Empty(t, args)
+
+	sql, _, err = ListBound("it's", 1, true).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "FOR VALUES IN ('it''s', 1, TRUE)", sql)
+
+	sql, _, err = ListBound("a?b").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "FOR VALUES IN ('a??b')", sql)
+
+	sql, _, err = HashBound(4, 3).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "FOR VALUES WITH (MODULUS 4, REMAINDER 3)", sql)
+
+	sql, _, err = DefaultBound().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DEFAULT", sql)
+}
+
+func TestPartitionBoundsErr(t *testing.T) {
+	_, _, err := RangeBound(Expr("?", 1), 2).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = RangeBound(struct{}{}, 2).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = ListBound().ToSql()
+	assert.Error(t, err)
+
+	_, _, err = HashBound(4, 4).ToSql()
+	assert.Error(t, err)
+}
@@ -48,6 +48,41 @@ func Placeholders(count int) string {
 	return strings.Repeat(",?", count)[1:]
 }
 
+// PlaceholdersFrom returns a string with count dollar-prefixed positional
+// placeholders, starting at start, joined with commas. Unlike ? placeholders
+// these are not renumbered by Dollar, so they can line up with the args of
+// an outer statement.
+//
+// Ex:
+//     PlaceholdersFrom(3, 2) == "$3,$4"
+func PlaceholdersFrom(start, count int) string {
+	if count < 1 {
+		return ""
+	}
+
+	buf := &strings.Builder{}
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(buf, "$%d", start+i)
+	}
+	return buf.String()
+}
+
+// TypedPlaceholders returns a string with count ? placeholders, each
+// followed by cast, joined with commas.
+//
+// Ex:
+//     TypedPlaceholders(2, "::uuid") == "?::uuid,?::uuid"
+func TypedPlaceholders(count int, cast string) string {
+	if count < 1 {
+		return ""
+	}
+
+	return strings.Repeat(",?"+cast, count)[1:]
+}
+
 func replacePlaceholders(sql string, replace func(buf *bytes.Buffer, i int) error) (string, error) {
 	buf := &bytes.Buffer{}
 	i := 0
@@ -77,3 +112,23 @@ func replacePlaceholders(sql string, replace func(buf *bytes.Buffer, i int) erro
 	buf.WriteString(sql)
 	return buf.String(), nil
 }
+
+// statementSql builds s for sending it to the server. Question keeps ??
+// escapes in place so that its output can still be nested into statements
+// using another format; statements built with Question are sent with the
+// escapes turned back into literal question marks, like Dollar does.
+func statementSql(s Sqlizer) (string, []interface{}, error) {
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return sql, args, err
+	}
+	if d, ok := s.(dryRunStatement); ok {
+		if _, ok := d.statementBuilder().placeholderFormat.(questionFormat); ok {
+			sql, err = replacePlaceholders(sql, func(buf *bytes.Buffer, i int) error {
+				buf.WriteByte('?')
+				return nil
+			})
+		}
+	}
+	return sql, args, err
+}
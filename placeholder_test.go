@@ -23,6 +23,24 @@ func TestPlaceholders(t *testing.T) {
 	assert.Equal(t, Placeholders(2), "?,?")
 }
 
+func TestPlaceholdersFrom(t *testing.T) {
+	assert.Equal(t, "$3,$4", PlaceholdersFrom(3, 2))
+	assert.Equal(t, "$1", PlaceholdersFrom(1, 1))
+	assert.Equal(t, "", PlaceholdersFrom(1, 0))
+}
+
+func TestTypedPlaceholders(t *testing.T) {
+	assert.Equal(t, "?::uuid,?::uuid", TypedPlaceholders(2, "::uuid"))
+	assert.Equal(t, "", TypedPlaceholders(0, "::uuid"))
+
+	sql, _, err := Select("*").From("t").
+		Where("id IN ("+TypedPlaceholders(2, "::uuid")+")", "a", "b").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE id IN ($1::uuid,$2::uuid)", sql)
+}
+
 func TestEscape(t *testing.T) {
 	sql := "SELECT uuid, \"data\" #> '{tags}' AS tags FROM nodes WHERE  \"data\" -> 'tags' ??| array['?'] AND enabled = ?"
 	s, _ := Dollar.ReplacePlaceholders(sql)
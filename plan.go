@@ -0,0 +1,99 @@
+package sqrl
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"math/rand"
+)
+
+// QueryPlan is a plan captured by WithPlanCapture.
+type QueryPlan struct {
+	SQL  string
+	Args []interface{}
+	// Kind and Table are the statement kind and main table, as reported to
+	// the MetricsCollector.
+	Kind  string
+	Table string
+	// Plan is the output of EXPLAIN (FORMAT JSON).
+	Plan json.RawMessage
+	// Err is set if the plan could not be captured.
+	Err error
+}
+
+// PlanSampler selects the executions whose plan is captured and receives
+// the plans.
+type PlanSampler interface {
+	// Sample reports whether the plan of this execution of s is captured.
+	Sample(s Sqlizer) bool
+	// Report is called with the captured plan before the statement is run.
+	Report(ctx context.Context, plan *QueryPlan)
+}
+
+type ratePlanSampler struct {
+	rate   float64
+	report func(ctx context.Context, plan *QueryPlan)
+}
+
+// SamplePlans returns a PlanSampler capturing the plans of a random
+// fraction rate, between 0 and 1, of all executions and passing them to
+// report.
+//
+// Ex:
+//     sb := sqrl.StatementBuilder.PlaceholderFormat(sqrl.Dollar).
+//         WithPlanCapture(sqrl.SamplePlans(0.001, func(ctx context.Context, plan *sqrl.QueryPlan) {
+//             logger.Infow("query plan", "sql", plan.SQL, "plan", string(plan.Plan))
+//         }))
+func SamplePlans(rate float64, report func(ctx context.Context, plan *QueryPlan)) PlanSampler {
+	return ratePlanSampler{rate: rate, report: report}
+}
+
+func (s ratePlanSampler) Sample(Sqlizer) bool {
+	return rand.Float64() < s.rate
+}
+
+func (s ratePlanSampler) Report(ctx context.Context, plan *QueryPlan) {
+	s.report(ctx, plan)
+}
+
+// WithPlanCapture makes child builders run EXPLAIN (FORMAT JSON) for the
+// executions of select, insert, update and delete statements selected by
+// sampler and report the plan to it before running the statement, e.g. to
+// detect plan regressions in production. Failing to capture a plan does not
+// fail the statement; the error is reported with the plan instead.
+func (b StatementBuilderType) WithPlanCapture(sampler PlanSampler) StatementBuilderType {
+	b.planSampler = sampler
+	return b
+}
+
+// capturePlan captures and reports the plan of s if it is sampled.
+func (b StatementBuilderType) capturePlan(ctx context.Context, pool instapgxpool.Pool, s Sqlizer) {
+	if b.planSampler == nil {
+		return
+	}
+	switch Kind(s) {
+	case KindSelect, KindInsert, KindUpdate, KindDelete:
+	default:
+		return
+	}
+	if !b.planSampler.Sample(s) {
+		return
+	}
+
+	query, args, err := statementSql(s)
+	if err != nil {
+		// The statement fails with the same error when run.
+		return
+	}
+
+	plan := &QueryPlan{SQL: query, Args: args}
+	plan.Kind, plan.Table = statementInfo(s)
+
+	var data []byte
+	if err := pool.QueryRow(ctx, "EXPLAIN (FORMAT JSON) "+query, args...).Scan(&data); err != nil {
+		plan.Err = err
+	} else {
+		plan.Plan = json.RawMessage(data)
+	}
+	b.planSampler.Report(ctx, plan)
+}
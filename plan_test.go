@@ -0,0 +1,88 @@
+package sqrl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type planSamplerStub struct {
+	sample bool
+	plans  []*QueryPlan
+}
+
+func (s *planSamplerStub) Sample(Sqlizer) bool { return s.sample }
+
+func (s *planSamplerStub) Report(_ context.Context, plan *QueryPlan) {
+	s.plans = append(s.plans, plan)
+}
+
+func TestWithPlanCapture(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{[]byte(`[{"Plan": {"Node Type": "Seq Scan"}}]`)}}, {{"a"}}}
+
+	sampler := &planSamplerStub{sample: true}
+	sb := StatementBuilder.PlaceholderFormat(Dollar).WithPlanCapture(sampler)
+
+	var name string
+	err := sb.Select("name").From("users").Where("id = ?", 1).QueryRowContext(context.Background(), pool).Scan(&name)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", name)
+
+	assert.Equal(t, []string{
+		"EXPLAIN (FORMAT JSON) SELECT name FROM users WHERE id = $1",
+		"SELECT name FROM users WHERE id = $1",
+	}, pool.stub.sqls)
+	assert.Equal(t, []*QueryPlan{{
+		SQL:   "SELECT name FROM users WHERE id = $1",
+		Args:  []interface{}{1},
+		Kind:  "select",
+		Table: "users",
+		Plan:  []byte(`[{"Plan": {"Node Type": "Seq Scan"}}]`),
+	}}, sampler.plans)
+}
+
+func TestWithPlanCaptureNotSampled(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.tag = nil
+
+	sampler := &planSamplerStub{}
+	sb := StatementBuilder.WithPlanCapture(sampler)
+
+	_, err := sb.Delete("users").Where("id = ?", 1).ExecContext(context.Background(), pool)
+	assert.NoError(t, err)
+
+	sampler.sample = true
+	_, err = sb.DropTable("users").ExecContext(context.Background(), pool)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"DELETE FROM users WHERE id = ?", "DROP TABLE users"}, pool.stub.sqls)
+	assert.Empty(t, sampler.plans)
+}
+
+func TestWithPlanCaptureError(t *testing.T) {
+	pool := newPoolStub()
+	boom := errors.New("boom")
+	pool.stub.err = boom
+
+	sampler := &planSamplerStub{sample: true}
+	_, err := StatementBuilder.WithPlanCapture(sampler).Select("1").QueryContext(context.Background(), pool)
+	assert.True(t, errors.Is(err, boom))
+
+	assert.Len(t, sampler.plans, 1)
+	assert.Equal(t, boom, sampler.plans[0].Err)
+	assert.Nil(t, sampler.plans[0].Plan)
+}
+
+func TestSamplePlans(t *testing.T) {
+	var reported []*QueryPlan
+	report := func(_ context.Context, plan *QueryPlan) { reported = append(reported, plan) }
+
+	assert.True(t, SamplePlans(1, report).Sample(Select("1")))
+	assert.False(t, SamplePlans(0, report).Sample(Select("1")))
+
+	SamplePlans(1, report).Report(context.Background(), &QueryPlan{SQL: "SELECT 1"})
+	assert.Equal(t, []*QueryPlan{{SQL: "SELECT 1"}}, reported)
+}
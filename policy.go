@@ -0,0 +1,385 @@
+package sqrl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgconn"
+	"io"
+	"strings"
+)
+
+// PolicyCommand is the command a row level security policy applies to.
+type PolicyCommand string
+
+const (
+	AllCmd    PolicyCommand = "ALL"
+	SelectCmd PolicyCommand = "SELECT"
+	InsertCmd PolicyCommand = "INSERT"
+	UpdateCmd PolicyCommand = "UPDATE"
+	DeleteCmd PolicyCommand = "DELETE"
+)
+
+// policyClauses holds the TO, USING and WITH CHECK clauses shared by CREATE
+// and ALTER POLICY.
+//
+// Policy expressions can not use bound parameters, args of the USING and
+// WITH CHECK predicates are therefore inlined as literals.
+type policyClauses struct {
+	roles     []string
+	using     Sqlizer
+	withCheck Sqlizer
+}
+
+func (p *policyClauses) appendToSql(w io.Writer) error {
+	if len(p.roles) > 0 {
+		io.WriteString(w, " TO ")
+		io.WriteString(w, strings.Join(p.roles, ", "))
+	}
+
+	if p.using != nil {
+		if err := appendPolicyExpr(w, " USING ", p.using); err != nil {
+			return err
+		}
+	}
+
+	if p.withCheck != nil {
+		if err := appendPolicyExpr(w, " WITH CHECK ", p.withCheck); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendPolicyExpr(w io.Writer, clause string, pred Sqlizer) error {
+	sql, args, err := pred.ToSql()
+	if err != nil {
+		return err
+	}
+	if sql, err = inline(sql, args); err != nil {
+		return err
+	}
+	// The statement is passed through ReplacePlaceholders, so question marks
+	// of the inlined expression are escaped like Raw does.
+	sql = strings.Replace(sql, "?", "??", -1)
+	io.WriteString(w, clause)
+	io.WriteString(w, "(")
+	io.WriteString(w, sql)
+	io.WriteString(w, ")")
+	return nil
+}
+
+// CreatePolicyBuilder builds SQL CREATE POLICY statements.
+type CreatePolicyBuilder struct {
+	StatementBuilderType
+
+	policyClauses
+
+	name        string
+	table       string
+	restrictive bool
+	command     PolicyCommand
+}
+
+// NewCreatePolicyBuilder creates new instance of CreatePolicyBuilder
+func NewCreatePolicyBuilder(b StatementBuilderType) *CreatePolicyBuilder {
+	return &CreatePolicyBuilder{StatementBuilderType: b}
+}
+
+// ExecContext builds and Execs the statement using given context.
+func (b *CreatePolicyBuilder) ExecContext(ctx context.Context, pool instapgxpool.Pool) (pgconn.CommandTag, error) {
+	return b.execContext(ctx, pool, b)
+}
+
+// ExecAttached is like ExecContext, using the pool set with RunWithPool.
+func (b *CreatePolicyBuilder) ExecAttached(ctx context.Context) (pgconn.CommandTag, error) {
+	return b.ExecContext(ctx, nil)
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// statement.
+func (b *CreatePolicyBuilder) PlaceholderFormat(f PlaceholderFormat) *CreatePolicyBuilder {
+	b.placeholderFormat = f
+	return b
+}
+
+// ToSql builds the statement into a SQL string and bound args.
+func (b *CreatePolicyBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
+	if len(b.name) == 0 {
+		err = fmt.Errorf("create policy statements must specify a name")
+		return
+	}
+	if len(b.table) == 0 {
+		err = fmt.Errorf("create policy statements must specify a table")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	sql.WriteString("CREATE POLICY ")
+	sql.WriteString(b.name)
+	sql.WriteString(" ON ")
+	sql.WriteString(b.table)
+
+	if b.restrictive {
+		sql.WriteString(" AS RESTRICTIVE")
+	}
+
+	if len(b.command) > 0 {
+		sql.WriteString(" FOR ")
+		sql.WriteString(string(b.command))
+	}
+
+	if err = b.policyClauses.appendToSql(sql); err != nil {
+		return
+	}
+
+	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
+	return
+}
+
+// Name sets the name of the policy.
+func (b *CreatePolicyBuilder) Name(name string) *CreatePolicyBuilder {
+	b.name = name
+	return b
+}
+
+// On sets the table the policy applies to.
+func (b *CreatePolicyBuilder) On(table string) *CreatePolicyBuilder {
+	b.table = table
+	return b
+}
+
+// Restrictive creates a restrictive policy. Policies are permissive by default.
+func (b *CreatePolicyBuilder) Restrictive() *CreatePolicyBuilder {
+	b.restrictive = true
+	return b
+}
+
+// For sets the command the policy applies to.
+func (b *CreatePolicyBuilder) For(command PolicyCommand) *CreatePolicyBuilder {
+	b.command = command
+	return b
+}
+
+// To adds roles the policy applies to.
+func (b *CreatePolicyBuilder) To(roles ...string) *CreatePolicyBuilder {
+	b.roles = append(b.roles, roles...)
+	return b
+}
+
+// Using sets the USING expression of the policy.
+//
+// See SelectBuilder.Where for accepted predicates.
+func (b *CreatePolicyBuilder) Using(pred interface{}, args ...interface{}) *CreatePolicyBuilder {
+	b.using = newWherePart(pred, args...)
+	return b
+}
+
+// WithCheck sets the WITH CHECK expression of the policy.
+//
+// See SelectBuilder.Where for accepted predicates.
+func (b *CreatePolicyBuilder) WithCheck(pred interface{}, args ...interface{}) *CreatePolicyBuilder {
+	b.withCheck = newWherePart(pred, args...)
+	return b
+}
+
+// AlterPolicyBuilder builds SQL ALTER POLICY statements.
+type AlterPolicyBuilder struct {
+	StatementBuilderType
+
+	policyClauses
+
+	name     string
+	table    string
+	renameTo string
+}
+
+// NewAlterPolicyBuilder creates new instance of AlterPolicyBuilder
+func NewAlterPolicyBuilder(b StatementBuilderType) *AlterPolicyBuilder {
+	return &AlterPolicyBuilder{StatementBuilderType: b}
+}
+
+// ExecContext builds and Execs the statement using given context.
+func (b *AlterPolicyBuilder) ExecContext(ctx context.Context, pool instapgxpool.Pool) (pgconn.CommandTag, error) {
+	return b.execContext(ctx, pool, b)
+}
+
+// ExecAttached is like ExecContext, using the pool set with RunWithPool.
+func (b *AlterPolicyBuilder) ExecAttached(ctx context.Context) (pgconn.CommandTag, error) {
+	return b.ExecContext(ctx, nil)
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// statement.
+func (b *AlterPolicyBuilder) PlaceholderFormat(f PlaceholderFormat) *AlterPolicyBuilder {
+	b.placeholderFormat = f
+	return b
+}
+
+// ToSql builds the statement into a SQL string and bound args.
+func (b *AlterPolicyBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
+	if len(b.name) == 0 {
+		err = fmt.Errorf("alter policy statements must specify a name")
+		return
+	}
+	if len(b.table) == 0 {
+		err = fmt.Errorf("alter policy statements must specify a table")
+		return
+	}
+	hasClauses := len(b.roles) > 0 || b.using != nil || b.withCheck != nil
+	if len(b.renameTo) > 0 && hasClauses {
+		err = fmt.Errorf("alter policy can not rename and change a policy at the same time")
+		return
+	}
+	if len(b.renameTo) == 0 && !hasClauses {
+		err = fmt.Errorf("alter policy statements must rename or change the policy")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	sql.WriteString("ALTER POLICY ")
+	sql.WriteString(b.name)
+	sql.WriteString(" ON ")
+	sql.WriteString(b.table)
+
+	if len(b.renameTo) > 0 {
+		sql.WriteString(" RENAME TO ")
+		sql.WriteString(b.renameTo)
+	}
+
+	if err = b.policyClauses.appendToSql(sql); err != nil {
+		return
+	}
+
+	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
+	return
+}
+
+// Name sets the name of the policy.
+func (b *AlterPolicyBuilder) Name(name string) *AlterPolicyBuilder {
+	b.name = name
+	return b
+}
+
+// On sets the table of the policy.
+func (b *AlterPolicyBuilder) On(table string) *AlterPolicyBuilder {
+	b.table = table
+	return b
+}
+
+// RenameTo renames the policy.
+func (b *AlterPolicyBuilder) RenameTo(name string) *AlterPolicyBuilder {
+	b.renameTo = name
+	return b
+}
+
+// To replaces the roles the policy applies to, including those of earlier
+// calls.
+func (b *AlterPolicyBuilder) To(roles ...string) *AlterPolicyBuilder {
+	b.roles = append([]string(nil), roles...)
+	return b
+}
+
+// Using replaces the USING expression of the policy.
+//
+// See SelectBuilder.Where for accepted predicates.
+func (b *AlterPolicyBuilder) Using(pred interface{}, args ...interface{}) *AlterPolicyBuilder {
+	b.using = newWherePart(pred, args...)
+	return b
+}
+
+// WithCheck replaces the WITH CHECK expression of the policy.
+//
+// See SelectBuilder.Where for accepted predicates.
+func (b *AlterPolicyBuilder) WithCheck(pred interface{}, args ...interface{}) *AlterPolicyBuilder {
+	b.withCheck = newWherePart(pred, args...)
+	return b
+}
+
+// DropPolicyBuilder builds SQL DROP POLICY statements.
+type DropPolicyBuilder struct {
+	StatementBuilderType
+
+	name     string
+	table    string
+	ifExists bool
+	cascade  bool
+}
+
+// NewDropPolicyBuilder creates new instance of DropPolicyBuilder
+func NewDropPolicyBuilder(b StatementBuilderType) *DropPolicyBuilder {
+	return &DropPolicyBuilder{StatementBuilderType: b}
+}
+
+// ExecContext builds and Execs the statement using given context.
+func (b *DropPolicyBuilder) ExecContext(ctx context.Context, pool instapgxpool.Pool) (pgconn.CommandTag, error) {
+	return b.execContext(ctx, pool, b)
+}
+
+// ExecAttached is like ExecContext, using the pool set with RunWithPool.
+func (b *DropPolicyBuilder) ExecAttached(ctx context.Context) (pgconn.CommandTag, error) {
+	return b.ExecContext(ctx, nil)
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// statement.
+func (b *DropPolicyBuilder) PlaceholderFormat(f PlaceholderFormat) *DropPolicyBuilder {
+	b.placeholderFormat = f
+	return b
+}
+
+// ToSql builds the statement into a SQL string and bound args.
+func (b *DropPolicyBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
+	if len(b.name) == 0 {
+		err = fmt.Errorf("drop policy statements must specify a name")
+		return
+	}
+	if len(b.table) == 0 {
+		err = fmt.Errorf("drop policy statements must specify a table")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	sql.WriteString("DROP POLICY ")
+	if b.ifExists {
+		sql.WriteString("IF EXISTS ")
+	}
+	sql.WriteString(b.name)
+	sql.WriteString(" ON ")
+	sql.WriteString(b.table)
+
+	if b.cascade {
+		sql.WriteString(" CASCADE")
+	}
+
+	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
+	return
+}
+
+// Name sets the name of the policy.
+func (b *DropPolicyBuilder) Name(name string) *DropPolicyBuilder {
+	b.name = name
+	return b
+}
+
+// On sets the table of the policy.
+func (b *DropPolicyBuilder) On(table string) *DropPolicyBuilder {
+	b.table = table
+	return b
+}
+
+// IfExists adds IF EXISTS to the statement.
+func (b *DropPolicyBuilder) IfExists() *DropPolicyBuilder {
+	b.ifExists = true
+	return b
+}
+
+// Cascade adds CASCADE to the statement.
+func (b *DropPolicyBuilder) Cascade() *DropPolicyBuilder {
+	b.cascade = true
+	return b
+}
@@ -0,0 +1,68 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePolicyBuilderToSql(t *testing.T) {
+	b := CreatePolicy("tenant_isolation").
+		On("orders").
+		Restrictive().
+		For(SelectCmd).
+		To("app_user", "reporting").
+		Using("tenant_id = current_setting(?)::uuid", "app.tenant_id").
+		WithCheck(Eq{"archived": false})
+
+	sql, args, err := b.PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"CREATE POLICY tenant_isolation ON orders AS RESTRICTIVE FOR SELECT TO app_user, reporting "+
+			"USING (tenant_id = current_setting('app.tenant_id')::uuid) WITH CHECK (archived = FALSE)", sql)
+	assert.Empty(t, args)
+}
+
+func TestCreatePolicyBuilderQuestionMark(t *testing.T) {
+	sql, _, err := CreatePolicy("p").
+		On("docs").
+		Using("tags @> ARRAY[?]", "a?b").
+		WithCheck("data ?? 'owner'").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE POLICY p ON docs USING (tags @> ARRAY['a?b']) WITH CHECK (data ? 'owner')", sql)
+
+	sql, _, err = AlterPolicy("p").On("docs").Using(Eq{"name": "a?b"}).PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "ALTER POLICY p ON docs USING (name = 'a?b')", sql)
+}
+
+func TestAlterPolicyBuilderToSql(t *testing.T) {
+	sql, _, err := AlterPolicy("p").On("t").To("r").Using("owner = current_user").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "ALTER POLICY p ON t TO r USING (owner = current_user)", sql)
+
+	sql, _, err = AlterPolicy("p").On("t").To("r").To("s", "u").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "ALTER POLICY p ON t TO s, u", sql)
+
+	sql, _, err = AlterPolicy("p").On("t").RenameTo("q").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "ALTER POLICY p ON t RENAME TO q", sql)
+
+	_, _, err = AlterPolicy("p").On("t").RenameTo("q").To("r").ToSql()
+	assert.Error(t, err)
+
+	_, _, err = AlterPolicy("p").On("t").ToSql()
+	assert.Error(t, err)
+}
+
+func TestDropPolicyBuilderToSql(t *testing.T) {
+	sql, _, err := DropPolicy("p").On("t").IfExists().Cascade().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DROP POLICY IF EXISTS p ON t CASCADE", sql)
+
+	_, _, err = DropPolicy("p").ToSql()
+	assert.Error(t, err)
+}
@@ -0,0 +1,197 @@
+package sqrl
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgx/v4"
+)
+
+// pgxStub records the statements run through poolStub and txStub and serves
+// queued result sets to their Query and QueryRow calls. Result sets in
+// bySql are served by statement instead, for queries run concurrently.
+type pgxStub struct {
+	mu      sync.Mutex
+	sqls    []string
+	args    [][]interface{}
+	columns []string
+	results [][][]interface{}
+	bySql   map[string][][]interface{}
+	tag     pgconn.CommandTag
+	err     error
+
+	begun      int
+	committed  int
+	rolledBack int
+}
+
+func (s *pgxStub) record(sql string, args []interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sqls = append(s.sqls, sql)
+	s.args = append(s.args, args)
+}
+
+func (s *pgxStub) exec(sql string, args []interface{}) (pgconn.CommandTag, error) {
+	s.record(sql, args)
+	return s.tag, s.err
+}
+
+func (s *pgxStub) query(sql string, args []interface{}) (pgx.Rows, error) {
+	s.record(sql, args)
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rows := &rowsStub{columns: s.columns}
+	if values, ok := s.bySql[sql]; ok {
+		rows.values = values
+	} else if len(s.results) > 0 {
+		rows.values = s.results[0]
+		s.results = s.results[1:]
+	}
+	return rows, nil
+}
+
+func (s *pgxStub) queryRow(sql string, args []interface{}) pgx.Row {
+	rows, err := s.query(sql, args)
+	return &rowStub{rows: rows, err: err}
+}
+
+type poolStub struct {
+	instapgxpool.Pool
+	stub *pgxStub
+}
+
+func newPoolStub() *poolStub {
+	return &poolStub{stub: &pgxStub{}}
+}
+
+func (p *poolStub) Exec(_ context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return p.stub.exec(sql, args)
+}
+
+func (p *poolStub) Query(_ context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return p.stub.query(sql, args)
+}
+
+func (p *poolStub) QueryRow(_ context.Context, sql string, args ...interface{}) pgx.Row {
+	return p.stub.queryRow(sql, args)
+}
+
+func (p *poolStub) Begin(_ context.Context) (pgx.Tx, error) {
+	p.stub.begun++
+	return &txStub{stub: p.stub}, nil
+}
+
+type txStub struct {
+	pgx.Tx
+	stub   *pgxStub
+	closed bool
+}
+
+func (t *txStub) Exec(_ context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return t.stub.exec(sql, args)
+}
+
+func (t *txStub) Query(_ context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return t.stub.query(sql, args)
+}
+
+func (t *txStub) QueryRow(_ context.Context, sql string, args ...interface{}) pgx.Row {
+	return t.stub.queryRow(sql, args)
+}
+
+func (t *txStub) Begin(_ context.Context) (pgx.Tx, error) {
+	t.stub.begun++
+	return &txStub{stub: t.stub}, nil
+}
+
+func (t *txStub) Commit(_ context.Context) error {
+	if t.closed {
+		return pgx.ErrTxClosed
+	}
+	t.closed = true
+	t.stub.committed++
+	return nil
+}
+
+func (t *txStub) Rollback(_ context.Context) error {
+	if t.closed {
+		return pgx.ErrTxClosed
+	}
+	t.closed = true
+	t.stub.rolledBack++
+	return nil
+}
+
+type rowsStub struct {
+	columns []string
+	values  [][]interface{}
+	pos     int
+	closed  bool
+}
+
+func (r *rowsStub) Close()                        { r.closed = true }
+func (r *rowsStub) Err() error                    { return nil }
+func (r *rowsStub) CommandTag() pgconn.CommandTag { return nil }
+func (r *rowsStub) RawValues() [][]byte           { return nil }
+
+func (r *rowsStub) FieldDescriptions() []pgproto3.FieldDescription {
+	fields := make([]pgproto3.FieldDescription, len(r.columns))
+	for i, column := range r.columns {
+		fields[i] = pgproto3.FieldDescription{Name: []byte(column)}
+	}
+	return fields
+}
+
+func (r *rowsStub) Next() bool {
+	if r.closed || r.pos >= len(r.values) {
+		r.closed = true
+		return false
+	}
+	r.pos++
+	return true
+}
+
+func (r *rowsStub) Values() ([]interface{}, error) {
+	return r.values[r.pos-1], nil
+}
+
+func (r *rowsStub) Scan(dest ...interface{}) error {
+	row := r.values[r.pos-1]
+	if len(dest) != len(row) {
+		return fmt.Errorf("expected %d destinations, got %d", len(row), len(dest))
+	}
+	for i, d := range dest {
+		target := reflect.ValueOf(d).Elem()
+		if row[i] == nil {
+			target.Set(reflect.Zero(target.Type()))
+			continue
+		}
+		target.Set(reflect.ValueOf(row[i]).Convert(target.Type()))
+	}
+	return nil
+}
+
+type rowStub struct {
+	rows pgx.Rows
+	err  error
+}
+
+func (r *rowStub) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	defer r.rows.Close()
+	if !r.rows.Next() {
+		return pgx.ErrNoRows
+	}
+	return r.rows.Scan(dest...)
+}
@@ -0,0 +1,64 @@
+package sqrl
+
+import (
+	"context"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgx/v4"
+)
+
+// Preload loads the children of parents with a single query instead of one
+// query per parent.
+//
+// The keys returned by parentKey for parents are collected and added to the
+// query built by children as a fkColumn IN (...) condition. scan is called
+// for every row and returns the child along with its foreign key, by which
+// the children are grouped. Parents without children have no entry in the
+// returned map; no query is run if there are no parents.
+//
+// Ex:
+//     posts, err := Preload(ctx, pool, users,
+//         func(u User) int64 { return u.ID },
+//         "posts.user_id",
+//         func() *SelectBuilder { return Select("id", "user_id", "title").From("posts").OrderBy("id") },
+//         func(rows pgx.Rows) (p Post, userID int64, err error) {
+//             err = rows.Scan(&p.ID, &p.UserID, &p.Title)
+//             return p, p.UserID, err
+//         })
+func Preload[P interface{}, C interface{}, K comparable](
+	ctx context.Context,
+	pool instapgxpool.Pool,
+	parents []P,
+	parentKey func(P) K,
+	fkColumn string,
+	children func() *SelectBuilder,
+	scan func(rows pgx.Rows) (C, K, error),
+) (map[K][]C, error) {
+	grouped := make(map[K][]C)
+	if len(parents) == 0 {
+		return grouped, nil
+	}
+
+	keys := make([]K, 0, len(parents))
+	seen := make(map[K]bool, len(parents))
+	for _, parent := range parents {
+		key := parentKey(parent)
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	b := children().Where(Eq{fkColumn: keys})
+	err := QueryEach(ctx, pool, b, func(rows pgx.Rows) error {
+		child, key, err := scan(rows)
+		if err != nil {
+			return err
+		}
+		grouped[key] = append(grouped[key], child)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return grouped, nil
+}
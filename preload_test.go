@@ -0,0 +1,53 @@
+package sqrl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type preloadUser struct {
+	ID int64
+}
+
+type preloadPost struct {
+	ID     int64
+	UserID int64
+}
+
+func preloadPosts(ctx context.Context, pool *poolStub, users []preloadUser) (map[int64][]preloadPost, error) {
+	return Preload(ctx, pool, users,
+		func(u preloadUser) int64 { return u.ID },
+		"user_id",
+		func() *SelectBuilder { return Select("id", "user_id").From("posts").OrderBy("id") },
+		func(rows pgx.Rows) (p preloadPost, userID int64, err error) {
+			err = rows.Scan(&p.ID, &p.UserID)
+			return p, p.UserID, err
+		})
+}
+
+func TestPreload(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{int64(10), int64(1)}, {int64(11), int64(3)}, {int64(12), int64(1)}}}
+
+	posts, err := preloadPosts(context.Background(), pool, []preloadUser{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64][]preloadPost{
+		1: {{ID: 10, UserID: 1}, {ID: 12, UserID: 1}},
+		3: {{ID: 11, UserID: 3}},
+	}, posts)
+
+	assert.Equal(t, []string{"SELECT id, user_id FROM posts WHERE user_id IN (?,?,?) ORDER BY id"}, pool.stub.sqls)
+	assert.Equal(t, [][]interface{}{{int64(1), int64(2), int64(3)}}, pool.stub.args)
+}
+
+func TestPreloadNoParents(t *testing.T) {
+	pool := newPoolStub()
+
+	posts, err := preloadPosts(context.Background(), pool, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, posts)
+	assert.Empty(t, pool.stub.sqls)
+}
@@ -0,0 +1,48 @@
+package sqrl
+
+import "fmt"
+
+// QueryError is returned by the execution helpers when running a query
+// fails. It carries the generated statement and wraps the error returned by
+// pgx, usually a *pgconn.PgError.
+type QueryError struct {
+	SQL      string
+	Args     []interface{}
+	Table    string
+	StmtType string
+	Err      error
+}
+
+func (e *QueryError) Error() string {
+	if len(e.Table) == 0 {
+		return fmt.Sprintf("%s query failed: %v (sql: %s)", e.StmtType, e.Err, e.SQL)
+	}
+	return fmt.Sprintf("%s query on %s failed: %v (sql: %s)", e.StmtType, e.Table, e.Err, e.SQL)
+}
+
+// Unwrap returns the underlying error.
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+// OmitQueryErrorArgs makes the QueryErrors of statements run by child
+// builders leave out the args of the failed query. Set it if args may
+// contain personal data which must not end up in error reports. Other
+// Sqlizers run by the execution helpers use the setting of the
+// StatementBuilderType carried by their context, see FromContext.
+func (b StatementBuilderType) OmitQueryErrorArgs() StatementBuilderType {
+	b.omitErrorArgs = true
+	return b
+}
+
+func (o *queryObserver) wrapErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	stmtType, table := statementInfo(o.s)
+	qe := &QueryError{SQL: o.query, Table: table, StmtType: stmtType, Err: err}
+	if !o.omitArgs {
+		qe.Args = o.args
+	}
+	return qe
+}
@@ -0,0 +1,75 @@
+package sqrl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryErrorExec(t *testing.T) {
+	pool := newPoolStub()
+	pgErr := &pgconn.PgError{Severity: "ERROR", Code: "23505", Message: "duplicate key"}
+	pool.stub.err = pgErr
+
+	_, err := Insert("users").Columns("email").Values("a@b.c").PlaceholderFormat(Dollar).
+		ExecContext(context.Background(), pool)
+
+	var qe *QueryError
+	if assert.True(t, errors.As(err, &qe)) {
+		assert.Equal(t, "INSERT INTO users (email) VALUES ($1)", qe.SQL)
+		assert.Equal(t, []interface{}{"a@b.c"}, qe.Args)
+		assert.Equal(t, "users", qe.Table)
+		assert.Equal(t, "insert", qe.StmtType)
+	}
+	assert.EqualError(t, err, "insert query on users failed: ERROR: duplicate key (SQLSTATE 23505) (sql: INSERT INTO users (email) VALUES ($1))")
+
+	var target *pgconn.PgError
+	assert.True(t, errors.As(err, &target))
+	assert.Equal(t, pgErr, target)
+}
+
+func TestQueryErrorQuery(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.err = errors.New("conn closed")
+
+	_, err := QueryWithContext(context.Background(), pool, Expr("SELECT ?", 1))
+	assert.EqualError(t, err, "other query failed: conn closed (sql: SELECT ?)")
+
+	err = Select("id").From("users").Scan(context.Background(), pool, new(int))
+	assert.IsType(t, &QueryError{}, err)
+}
+
+func TestQueryErrorNoRows(t *testing.T) {
+	pool := newPoolStub()
+
+	err := Select("id").From("users").Scan(context.Background(), pool, new(int))
+	assert.Equal(t, pgx.ErrNoRows, err)
+}
+
+func TestQueryErrorOmitArgs(t *testing.T) {
+	sb := StatementBuilder.OmitQueryErrorArgs()
+	pool := newPoolStub()
+	pool.stub.err = errors.New("failed")
+
+	_, err := sb.Update("users").Set("email", "a@b.c").ExecContext(context.Background(), pool)
+	var qe *QueryError
+	if assert.True(t, errors.As(err, &qe)) {
+		assert.Nil(t, qe.Args)
+		assert.Equal(t, "UPDATE users SET email = ?", qe.SQL)
+	}
+
+	ctx := WithStatementBuilder(context.Background(), sb)
+	_, err = ExecWithContext(ctx, pool, Expr("UPDATE users SET email = ?", "a@b.c"))
+	if assert.True(t, errors.As(err, &qe)) {
+		assert.Nil(t, qe.Args)
+	}
+
+	_, err = Update("users").Set("email", "a@b.c").ExecContext(ctx, pool)
+	if assert.True(t, errors.As(err, &qe)) {
+		assert.Equal(t, []interface{}{"a@b.c"}, qe.Args)
+	}
+}
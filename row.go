@@ -1,5 +1,11 @@
 package sqrl
 
+import (
+	"fmt"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgx/v4"
+)
+
 // RowScanner is the interface that wraps the Scan method.
 //
 // Scan behaves like database/sql.Row.Scan.
@@ -10,7 +16,13 @@ type RowScanner interface {
 // Row wraps database/sql.Row to let squirrel return new errors on Scan.
 type Row struct {
 	RowScanner
-	err error
+	err  error
+	rows *rowsRow
+}
+
+// Err returns the error which occurred while building the query, if any.
+func (r *Row) Err() error {
+	return r.err
 }
 
 // Scan returns Row.err or calls RowScanner.Scan.
@@ -20,3 +32,86 @@ func (r *Row) Scan(dest ...interface{}) error {
 	}
 	return r.RowScanner.Scan(dest...)
 }
+
+// Values reads the row like Scan, returning its values as decoded by pgx
+// instead of scanning them, e.g. for generic tooling like CSV exports. It
+// is only supported by Rows returned by QueryRowWithContext and the
+// QueryRowContext methods of the builders.
+//
+// Ex:
+//     row := sqrl.QueryRowWithContext(ctx, pool, sqrl.Select("*").From("users").Where(sqrl.Eq{"id": id})).(*sqrl.Row)
+//     values, err := row.Values()
+//     for i, field := range row.FieldDescriptions() {
+//         fmt.Println(string(field.Name), values[i])
+//     }
+func (r *Row) Values() ([]interface{}, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.rows == nil {
+		return nil, errValuesNotSupported
+	}
+	values := &rowValues{}
+	if err := r.RowScanner.Scan(values); err != nil {
+		return nil, err
+	}
+	return values.values, nil
+}
+
+// FieldDescriptions returns the field descriptions of the row once it has
+// been read with Scan or Values, or nil if it has not been read or the Row
+// was not returned by QueryRowWithContext.
+func (r *Row) FieldDescriptions() []pgproto3.FieldDescription {
+	if r.rows == nil {
+		return nil
+	}
+	return r.rows.fields
+}
+
+var errValuesNotSupported = fmt.Errorf("row does not support Values")
+
+// rowValues is passed to rowsRow.Scan by Row.Values to read the values of
+// the row through the wrapping RowScanners.
+type rowValues struct {
+	values []interface{}
+}
+
+// rowsRow reads a single row from rows, like pgx's QueryRow, keeping its
+// field descriptions.
+type rowsRow struct {
+	rows   pgx.Rows
+	err    error
+	fields []pgproto3.FieldDescription
+}
+
+func (r *rowsRow) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	rows := r.rows
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return pgx.ErrNoRows
+	}
+	r.fields = rows.FieldDescriptions()
+
+	if len(dest) == 1 {
+		if values, ok := dest[0].(*rowValues); ok {
+			var err error
+			if values.values, err = rows.Values(); err != nil {
+				return err
+			}
+			rows.Close()
+			return rows.Err()
+		}
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return err
+	}
+	rows.Close()
+	return rows.Err()
+}
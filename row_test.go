@@ -1,9 +1,12 @@
 package sqrl
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"testing"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -32,3 +35,47 @@ func TestRowScanErr(t *testing.T) {
 	assert.False(t, stub.Scanned, "row was scanned")
 	assert.Equal(t, rowErr, err)
 }
+
+func TestRowErr(t *testing.T) {
+	row := &Row{RowScanner: &RowStub{}}
+	assert.NoError(t, row.Err())
+
+	rowErr := fmt.Errorf("build err")
+	row = &Row{err: rowErr}
+	assert.Equal(t, rowErr, row.Err())
+	assert.Equal(t, rowErr, row.Scan())
+}
+
+func TestRowValuesAndFieldDescriptions(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.columns = []string{"id", "name"}
+	pool.stub.results = [][][]interface{}{{{1, "moe"}}}
+
+	row := QueryRowWithContext(context.Background(), pool, Select("id", "name").From("users")).(*Row)
+	assert.Nil(t, row.FieldDescriptions())
+
+	values, err := row.Values()
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{1, "moe"}, values)
+	if fields := row.FieldDescriptions(); assert.Len(t, fields, 2) {
+		assert.Equal(t, "id", string(fields[0].Name))
+		assert.Equal(t, "name", string(fields[1].Name))
+	}
+	assert.Equal(t, []string{"SELECT id, name FROM users"}, pool.stub.sqls)
+}
+
+func TestRowValuesErrors(t *testing.T) {
+	pool := newPoolStub()
+
+	row := QueryRowWithContext(context.Background(), pool, Select("id").From("users")).(*Row)
+	_, err := row.Values()
+	assert.True(t, errors.Is(err, pgx.ErrNoRows))
+
+	pool.stub.err = errors.New("query failed")
+	row = QueryRowWithContext(context.Background(), pool, Select("id").From("users")).(*Row)
+	_, err = row.Values()
+	assert.True(t, errors.Is(err, pool.stub.err))
+
+	_, err = (&Row{RowScanner: &RowStub{}}).Values()
+	assert.Equal(t, errValuesNotSupported, err)
+}
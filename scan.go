@@ -0,0 +1,100 @@
+package sqrl
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// NullTo returns a destination for RowScanner.Scan which stores the scanned
+// value in dest, or fallback if the column is NULL.
+//
+// Ex:
+//     var name string
+//     var age int
+//     err := row.Scan(sqrl.NullTo(&name, "anonymous"), sqrl.NullTo(&age, 0))
+func NullTo[T interface{}](dest *T, fallback T) sql.Scanner {
+	return &nullTo[T]{dest: dest, fallback: fallback}
+}
+
+type nullTo[T interface{}] struct {
+	dest     *T
+	fallback T
+}
+
+// Scan implements sql.Scanner.
+func (n *nullTo[T]) Scan(src interface{}) error {
+	if src == nil {
+		*n.dest = n.fallback
+		return nil
+	}
+	return assignScanned(n.dest, src)
+}
+
+// OptionalScan returns a destination for RowScanner.Scan which sets *dest to
+// nil if the column is NULL and to a pointer to the scanned value otherwise.
+//
+// Ex:
+//     var deletedAt *time.Time
+//     err := row.Scan(sqrl.OptionalScan(&deletedAt))
+func OptionalScan[T interface{}](dest **T) sql.Scanner {
+	return &optionalScan[T]{dest: dest}
+}
+
+type optionalScan[T interface{}] struct {
+	dest **T
+}
+
+// Scan implements sql.Scanner.
+func (o *optionalScan[T]) Scan(src interface{}) error {
+	if src == nil {
+		*o.dest = nil
+		return nil
+	}
+	v := new(T)
+	if err := assignScanned(v, src); err != nil {
+		return err
+	}
+	*o.dest = v
+	return nil
+}
+
+// assignScanned stores src, a value as passed to sql.Scanner.Scan, in dest.
+// dest is scanned into if it implements sql.Scanner itself; otherwise src
+// must be assignable to it, text for strings or a number for numbers.
+func assignScanned(dest interface{}, src interface{}) error {
+	if scanner, ok := dest.(sql.Scanner); ok {
+		return scanner.Scan(src)
+	}
+
+	d := reflect.ValueOf(dest).Elem()
+	s := reflect.ValueOf(src)
+	switch {
+	case s.Type().AssignableTo(d.Type()):
+		d.Set(s)
+		return nil
+	case d.Kind() == reflect.String:
+		switch src := src.(type) {
+		case string:
+			d.SetString(src)
+			return nil
+		case []byte:
+			d.SetString(string(src))
+			return nil
+		}
+	case isNumberKind(d.Kind()) && isNumberKind(s.Kind()):
+		d.Set(s.Convert(d.Type()))
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into %s", src, d.Type())
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
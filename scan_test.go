@@ -0,0 +1,45 @@
+package sqrl
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullTo(t *testing.T) {
+	var name string
+	var age int
+	assert.NoError(t, NullTo(&name, "anonymous").Scan(nil))
+	assert.NoError(t, NullTo(&age, 18).Scan(int64(42)))
+	assert.Equal(t, "anonymous", name)
+	assert.Equal(t, 42, age)
+
+	assert.NoError(t, NullTo(&name, "anonymous").Scan([]byte("moe")))
+	assert.Equal(t, "moe", name)
+
+	var ns sql.NullString
+	assert.NoError(t, NullTo(&ns, sql.NullString{String: "-", Valid: true}).Scan("larry"))
+	assert.Equal(t, sql.NullString{String: "larry", Valid: true}, ns)
+
+	assert.EqualError(t, NullTo(&name, "").Scan(int64(1)), "cannot scan int64 into string")
+}
+
+func TestOptionalScan(t *testing.T) {
+	now := time.Now()
+	deletedAt := &now
+	assert.NoError(t, OptionalScan(&deletedAt).Scan(nil))
+	assert.Nil(t, deletedAt)
+
+	assert.NoError(t, OptionalScan(&deletedAt).Scan(now))
+	if assert.NotNil(t, deletedAt) {
+		assert.Equal(t, now, *deletedAt)
+	}
+
+	var score *float64
+	assert.NoError(t, OptionalScan(&score).Scan(int64(3)))
+	if assert.NotNil(t, score) {
+		assert.Equal(t, 3.0, *score)
+	}
+}
@@ -0,0 +1,284 @@
+package sqrl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Schema is a registry of tables and their known columns. Builders created
+// from a StatementBuilderType with WithSchema validate the column names they
+// are given against it in ToSql, catching typos in tests instead of at
+// runtime.
+//
+// Only plain column names are validated: result columns of Select and
+// Column, columns of Insert, SetMap and Update Set clauses and the keys of
+// Eq, NotEq, EqNotZero, EqNotNil, Lt, LtOrEq, Gt, GtOrEq and OrderedEq
+// conditions in WHERE clauses. Expressions and columns of tables which are not registered are
+// ignored.
+type Schema struct {
+	mu     sync.RWMutex
+	tables map[string]map[string]bool
+}
+
+// NewSchema returns an empty Schema.
+func NewSchema() *Schema {
+	return &Schema{tables: make(map[string]map[string]bool)}
+}
+
+// Table registers table with its columns, adding to the columns already
+// registered for it.
+//
+// Ex:
+//     schema := sqrl.NewSchema().
+//         Table("users", "id", "name", "created_at").
+//         Table("posts", "id", "user_id", "title")
+func (s *Schema) Table(table string, columns ...string) *Schema {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	known, ok := s.tables[table]
+	if !ok {
+		known = make(map[string]bool, len(columns))
+		s.tables[table] = known
+	}
+	for _, column := range columns {
+		known[column] = true
+	}
+	return s
+}
+
+// Has reports whether column is registered for table.
+func (s *Schema) Has(table, column string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.tables[table][column]
+}
+
+func (s *Schema) known(table string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.tables[table]
+	return ok
+}
+
+// WithSchema makes child builders validate their column names against
+// schema in ToSql, failing with an error naming the unknown column. It is
+// meant for tests and development, where the cost of validation does not
+// matter.
+//
+// Ex:
+//     sb := sqrl.StatementBuilder.WithSchema(schema)
+//     _, _, err := sb.Select("id").From("users").Where(sqrl.Eq{"craeted_at": t}).ToSql()
+//     // err: unknown column "craeted_at" of table users
+func (b StatementBuilderType) WithSchema(schema *Schema) StatementBuilderType {
+	b.schema = schema
+	return b
+}
+
+// Validate checks the column names used by a SelectBuilder, InsertBuilder,
+// UpdateBuilder or DeleteBuilder against s. Subqueries are not validated,
+// they are validated by their own ToSql if built with the Schema.
+func (s *Schema) Validate(b Sqlizer) error {
+	if s == nil {
+		return nil
+	}
+
+	v := &schemaValidator{schema: s, relations: map[string]string{}}
+	switch b := b.(type) {
+	case *SelectBuilder:
+		v.addFroms(b.fromParts)
+		for _, j := range b.joins {
+			v.addJoin(j)
+		}
+		for _, c := range b.columns {
+			if p, ok := c.(*part); ok {
+				if column, ok := p.pred.(string); ok {
+					v.check(column)
+				}
+			}
+		}
+		v.checkConditions(b.whereParts)
+	case *InsertBuilder:
+		v.addRelation(b.into)
+		for _, column := range b.columns {
+			v.check(column)
+		}
+	case *UpdateBuilder:
+		v.addRelation(b.table)
+		v.addFroms(b.fromParts)
+		for _, set := range b.setClauses {
+			v.check(set.column)
+		}
+		v.checkConditions(b.whereParts)
+	case *DeleteBuilder:
+		v.addRelation(b.from)
+		for _, j := range b.joins {
+			v.addJoinClause(j)
+		}
+		v.addFroms(b.usingParts)
+		v.checkConditions(b.whereParts)
+	}
+	return v.err
+}
+
+var schemaColumnRegexp = regexp.MustCompile(`^(?:([A-Za-z_][A-Za-z0-9_]*)\.)?([A-Za-z_][A-Za-z0-9_]*)$`)
+
+// schemaValidator validates the columns of a single statement. relations
+// maps the names and aliases of the relations of the statement to their
+// tables, or to "" for subqueries.
+type schemaValidator struct {
+	schema    *Schema
+	relations map[string]string
+	tables    []string
+	err       error
+}
+
+// addRelation adds a relation like "users", "users u" or "users AS u".
+func (v *schemaValidator) addRelation(relation string) {
+	fields := strings.Fields(relation)
+	if len(fields) == 0 {
+		return
+	}
+	table := fields[0]
+	if strings.HasPrefix(table, "(") {
+		table = ""
+	}
+	v.tables = append(v.tables, table)
+	v.relations[table] = table
+	if len(fields) > 2 && strings.EqualFold(fields[1], "AS") {
+		v.relations[fields[2]] = table
+	} else if len(fields) > 1 && !strings.EqualFold(fields[1], "ON") && !strings.EqualFold(fields[1], "USING") {
+		v.relations[fields[1]] = table
+	}
+}
+
+func (v *schemaValidator) addFroms(parts []Sqlizer) {
+	for _, p := range parts {
+		switch p := p.(type) {
+		case *part:
+			if from, ok := p.pred.(string); ok {
+				for _, relation := range strings.Split(from, ",") {
+					v.addRelation(relation)
+				}
+				continue
+			}
+			v.tables = append(v.tables, "")
+		case aliasExpr:
+			v.tables = append(v.tables, "")
+			v.relations[p.alias] = ""
+		default:
+			v.tables = append(v.tables, "")
+		}
+	}
+}
+
+func (v *schemaValidator) addJoin(j Sqlizer) {
+	if p, ok := j.(*part); ok {
+		if join, ok := p.pred.(string); ok {
+			v.addJoinClause(join)
+			return
+		}
+	}
+	v.tables = append(v.tables, "")
+}
+
+// addJoinClause adds the relation of a JOIN clause like
+// "LEFT JOIN emails e ON ...".
+func (v *schemaValidator) addJoinClause(join string) {
+	if table := joinTable(join); len(table) > 0 {
+		fields := strings.Fields(join)
+		for i, f := range fields {
+			if f == table {
+				v.addRelation(strings.Join(fields[i:], " "))
+				return
+			}
+		}
+	}
+	v.tables = append(v.tables, "")
+}
+
+// check validates a column name, qualified or not. Anything else than a
+// plain column name, e.g. an expression or "*", is ignored.
+func (v *schemaValidator) check(column string) {
+	if v.err != nil {
+		return
+	}
+
+	m := schemaColumnRegexp.FindStringSubmatch(strings.TrimSpace(column))
+	if m == nil {
+		return
+	}
+	qualifier, name := m[1], m[2]
+
+	if len(qualifier) > 0 {
+		table, ok := v.relations[qualifier]
+		if !ok || !v.schema.known(table) {
+			return
+		}
+		if !v.schema.Has(table, name) {
+			v.err = fmt.Errorf("unknown column %q of table %s", m[0], table)
+		}
+		return
+	}
+
+	if len(v.tables) == 0 {
+		return
+	}
+	for _, table := range v.tables {
+		if !v.schema.known(table) || v.schema.Has(table, name) {
+			return
+		}
+	}
+	v.err = fmt.Errorf("unknown column %q of table %s", name, strings.Join(v.tables, ", "))
+}
+
+func (v *schemaValidator) checkConditions(parts []Sqlizer) {
+	for _, p := range parts {
+		v.checkCondition(p)
+	}
+}
+
+func (v *schemaValidator) checkCondition(c interface{}) {
+	switch c := c.(type) {
+	case *wherePart:
+		v.checkCondition(c.pred)
+	case *Group:
+		v.checkConditions(c.parts)
+	case And:
+		v.checkConditions(c)
+	case Or:
+		v.checkConditions(c)
+	case map[string]interface{}:
+		v.checkKeys(c)
+	case Eq:
+		v.checkKeys(c)
+	case NotEq:
+		v.checkKeys(c)
+	case EqNotZero:
+		v.checkKeys(c)
+	case EqNotNil:
+		v.checkKeys(c)
+	case Lt:
+		v.checkKeys(c)
+	case LtOrEq:
+		v.checkKeys(c)
+	case Gt:
+		v.checkKeys(c)
+	case GtOrEq:
+		v.checkKeys(c)
+	case OrderedEq:
+		for _, pair := range c {
+			v.check(pair.Column)
+		}
+	}
+}
+
+func (v *schemaValidator) checkKeys(m map[string]interface{}) {
+	for _, key := range sortedKeys(m) {
+		v.check(key)
+	}
+}
@@ -0,0 +1,74 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testSchema() *Schema {
+	return NewSchema().
+		Table("users", "id", "name", "created_at").
+		Table("posts", "id", "user_id", "title")
+}
+
+func TestSchemaSelect(t *testing.T) {
+	sb := StatementBuilder.WithSchema(testSchema())
+
+	_, _, err := sb.Select("u.id", "p.title", "count(*)").
+		From("users u").
+		LeftJoin("posts AS p ON p.user_id = u.id").
+		Where(Eq{"u.created_at": 1}).
+		Where(Or{Lt{"user_id": 2}, OrderedEq{{"name", "moe"}}}).
+		ToSql()
+	assert.NoError(t, err)
+
+	_, _, err = sb.Select("id", "title").From("users").ToSql()
+	assert.EqualError(t, err, `unknown column "title" of table users`)
+
+	_, _, err = sb.Select("u.id").From("users u").Where(Eq{"u.craeted_at": 1}).ToSql()
+	assert.EqualError(t, err, `unknown column "u.craeted_at" of table users`)
+
+	_, _, err = sb.Select("id").From("users").Join("posts p USING (id)").Where((&Group{}).Where(map[string]interface{}{"craeted_at": 1})).ToSql()
+	assert.EqualError(t, err, `unknown column "craeted_at" of table users, posts`)
+}
+
+func TestSchemaUnknownTables(t *testing.T) {
+	sb := StatementBuilder.WithSchema(testSchema())
+
+	_, _, err := sb.Select("whatever").From("events").ToSql()
+	assert.NoError(t, err)
+
+	_, _, err = sb.Select("id", "total").From("users").Join("orders o ON o.user_id = users.id").ToSql()
+	assert.NoError(t, err)
+
+	_, _, err = sb.Select("s.total").FromSelect(Select("1 AS total"), "s").ToSql()
+	assert.NoError(t, err)
+}
+
+func TestSchemaInsertUpdateDelete(t *testing.T) {
+	sb := StatementBuilder.WithSchema(testSchema())
+
+	_, _, err := sb.Insert("users").Columns("id", "name").Values(1, "moe").ToSql()
+	assert.NoError(t, err)
+
+	_, _, err = sb.Insert("users").SetMap(map[string]interface{}{"nmae": "moe"}).ToSql()
+	assert.EqualError(t, err, `unknown column "nmae" of table users`)
+
+	_, _, err = sb.Update("users").Set("name", "moe").Where(Eq{"id": 1}).ToSql()
+	assert.NoError(t, err)
+
+	_, _, err = sb.Update("users").Set("title", "moe").ToSql()
+	assert.EqualError(t, err, `unknown column "title" of table users`)
+
+	_, _, err = sb.Delete("posts").Where(GtOrEq{"user_id": 1}).ToSql()
+	assert.NoError(t, err)
+
+	_, _, err = sb.Delete("posts").Where(NotEq{"name": "moe"}).ToSql()
+	assert.EqualError(t, err, `unknown column "name" of table posts`)
+}
+
+func TestSchemaNotSet(t *testing.T) {
+	_, _, err := Select("title").From("users").Where(Eq{"craeted_at": 1}).ToSql()
+	assert.NoError(t, err)
+}
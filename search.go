@@ -0,0 +1,153 @@
+package sqrl
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SearchOptions configures the translation of user search input by
+// Tsquery.
+type SearchOptions struct {
+	// Or joins terms with OR instead of AND unless the input says otherwise.
+	Or bool
+	// Prefix matches the last word of every unquoted term as a prefix.
+	Prefix bool
+}
+
+type searchTerm struct {
+	words  []string
+	negate bool
+	quoted bool
+	op     string
+}
+
+// Tsquery translates free-form user search input into to_tsquery input.
+//
+// The input follows the websearch_to_tsquery syntax: "quoted text" is a
+// phrase, a leading - negates a term and the words or/and combine terms
+// explicitly. Unlike websearch_to_tsquery the result supports prefix
+// matching. Every character which is not a letter or digit is dropped, so
+// the result is always valid to_tsquery input. An input without words
+// yields an empty string.
+//
+// Ex:
+//     Tsquery(`"sql builder" -orm go or golang`, SearchOptions{Prefix: true})
+//     == "(sql <-> builder) & !orm:* & go:* | golang:*"
+func Tsquery(search string, opts SearchOptions) string {
+	defaultOp := " & "
+	if opts.Or {
+		defaultOp = " | "
+	}
+
+	var terms []searchTerm
+	op, negate := "", false
+	rest := search
+	for len(rest) > 0 {
+		r, size := utf8.DecodeRuneInString(rest)
+		switch {
+		case unicode.IsSpace(r):
+			rest = rest[size:]
+			continue
+		case r == '-':
+			negate = true
+			rest = rest[1:]
+			continue
+		}
+
+		var text string
+		quoted := r == '"'
+		if quoted {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				text, rest = rest[1:], ""
+			} else {
+				text, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			end := strings.IndexFunc(rest, unicode.IsSpace)
+			if end < 0 {
+				end = len(rest)
+			}
+			text, rest = rest[:end], rest[end:]
+			switch strings.ToLower(text) {
+			case "or":
+				if len(terms) > 0 {
+					op = " | "
+				}
+				negate = false
+				continue
+			case "and":
+				if len(terms) > 0 {
+					op = " & "
+				}
+				negate = false
+				continue
+			}
+		}
+
+		words := strings.FieldsFunc(text, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if len(words) == 0 {
+			continue
+		}
+		if len(op) == 0 {
+			op = defaultOp
+		}
+		terms = append(terms, searchTerm{words: words, negate: negate, quoted: quoted, op: op})
+		op, negate = "", false
+	}
+
+	buf := &strings.Builder{}
+	for i, term := range terms {
+		if i > 0 {
+			buf.WriteString(term.op)
+		}
+		if term.negate {
+			buf.WriteRune('!')
+		}
+		if len(term.words) > 1 {
+			buf.WriteRune('(')
+		}
+		buf.WriteString(strings.Join(term.words, " <-> "))
+		if opts.Prefix && !term.quoted {
+			buf.WriteString(":*")
+		}
+		if len(term.words) > 1 {
+			buf.WriteRune(')')
+		}
+	}
+	return buf.String()
+}
+
+type textSearch struct {
+	vector string
+	config string
+	search string
+	opts   SearchOptions
+}
+
+// TextSearch matches the tsvector expression vector against free-form user
+// search input, translated with Tsquery. If config is empty the server's
+// default text search configuration is used.
+//
+// Ex:
+//     .Where(TextSearch("document", "english", "sql -orm", SearchOptions{Prefix: true}))
+//     == "document @@ to_tsquery(?::regconfig, ?)" with args ["english", "sql:* & !orm:*"]
+func TextSearch(vector, config, search string, opts SearchOptions) Sqlizer {
+	return textSearch{vector: vector, config: config, search: search, opts: opts}
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (ts textSearch) ToSql() (string, []interface{}, error) {
+	if len(ts.vector) == 0 {
+		return "", nil, fmt.Errorf("text search requires a tsvector expression")
+	}
+	query := Tsquery(ts.search, ts.opts)
+	if len(ts.config) == 0 {
+		return ts.vector + " @@ to_tsquery(?)", []interface{}{query}, nil
+	}
+	return ts.vector + " @@ to_tsquery(?::regconfig, ?)", []interface{}{ts.config, query}, nil
+}
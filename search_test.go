@@ -0,0 +1,49 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTsquery(t *testing.T) {
+	cases := []struct {
+		search string
+		opts   SearchOptions
+		want   string
+	}{
+		{"", SearchOptions{}, ""},
+		{"  ", SearchOptions{}, ""},
+		{"sql builder", SearchOptions{}, "sql & builder"},
+		{"sql builder", SearchOptions{Or: true}, "sql | builder"},
+		{"sql builder", SearchOptions{Prefix: true}, "sql:* & builder:*"},
+		{`"sql builder" -orm`, SearchOptions{Prefix: true}, "(sql <-> builder) & !orm:*"},
+		{`"sql builder" -orm go or golang`, SearchOptions{Prefix: true}, "(sql <-> builder) & !orm:* & go:* | golang:*"},
+		{"go and rust", SearchOptions{Or: true}, "go & rust"},
+		{"or and go", SearchOptions{}, "go"},
+		{"e-mail", SearchOptions{Prefix: true}, "(e <-> mail:*)"},
+		{`a'); DROP TABLE x; -- & | ! ( ) : *`, SearchOptions{}, "a & DROP & TABLE & x"},
+		{`"unterminated phrase`, SearchOptions{}, "(unterminated <-> phrase)"},
+		{"grüße\u00a0straße", SearchOptions{}, "grüße & straße"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, Tsquery(c.search, c.opts), c.search)
+	}
+}
+
+func TestTextSearch(t *testing.T) {
+	sql, args, err := Select("id").From("docs").
+		Where(TextSearch("document", "english", "sql -orm", SearchOptions{Prefix: true})).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM docs WHERE document @@ to_tsquery(?::regconfig, ?)", sql)
+	assert.Equal(t, []interface{}{"english", "sql:* & !orm:*"}, args)
+
+	sql, args, err = TextSearch("document", "", "sql", SearchOptions{}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "document @@ to_tsquery(?)", sql)
+	assert.Equal(t, []interface{}{"sql"}, args)
+
+	_, _, err = TextSearch("", "", "sql", SearchOptions{}).ToSql()
+	assert.Error(t, err)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/clevabit/utils-go/instapgxpool"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
+	"regexp"
 	"strconv"
 	"strings"
 )
@@ -16,6 +17,7 @@ type SelectBuilder struct {
 	StatementBuilderType
 
 	prefixes    exprs
+	hints       []string
 	distinct    bool
 	options     []string
 	columns     []Sqlizer
@@ -34,6 +36,9 @@ type SelectBuilder struct {
 	offsetValid bool
 
 	suffixes exprs
+
+	fragments []string
+	joinKeys  []string
 }
 
 // NewSelectBuilder creates new instance of SelectBuilder
@@ -45,6 +50,7 @@ func (b *SelectBuilder) Clone() *SelectBuilder {
 	return &SelectBuilder{
 		StatementBuilderType: b.StatementBuilderType,
 		prefixes:             b.prefixes,
+		hints:                b.hints,
 		distinct:             b.distinct,
 		options:              b.options,
 		columns:              b.columns,
@@ -61,21 +67,39 @@ func (b *SelectBuilder) Clone() *SelectBuilder {
 		offset:               b.offset,
 		offsetValid:          b.offsetValid,
 		suffixes:             b.suffixes,
+		fragments:            b.fragments,
+		joinKeys:             b.joinKeys,
 	}
 }
 
-// ExecContext builds and Execs the query with the Runner set by RunWith using given context.
+// ExecContext builds and Execs the query using given context.
 func (b *SelectBuilder) ExecContext(ctx context.Context, pool instapgxpool.Pool) (pgconn.CommandTag, error) {
-	return ExecWithContext(ctx, pool, b)
+	return b.execContext(ctx, pool, b)
+}
+
+// ExecAttached is like ExecContext, using the pool set with RunWithPool.
+func (b *SelectBuilder) ExecAttached(ctx context.Context) (pgconn.CommandTag, error) {
+	return b.ExecContext(ctx, nil)
 }
 
-// QueryContext builds and Querys the query with the Runner set by RunWith in given context.
+// QueryContext builds and Querys the query using given context.
 func (b *SelectBuilder) QueryContext(ctx context.Context, pool instapgxpool.Pool) (pgx.Rows, error) {
-	return QueryWithContext(ctx, pool, b)
+	return b.queryContext(ctx, pool, b)
+}
+
+// QueryAttached is like QueryContext, using the pool set with RunWithPool.
+func (b *SelectBuilder) QueryAttached(ctx context.Context) (pgx.Rows, error) {
+	return b.QueryContext(ctx, nil)
 }
 
+// QueryRowContext builds and runs the query using given context.
 func (b *SelectBuilder) QueryRowContext(ctx context.Context, pool instapgxpool.Pool) RowScanner {
-	return QueryRowWithContext(ctx, pool, b)
+	return b.queryRowContext(ctx, pool, b)
+}
+
+// QueryRowAttached is like QueryRowContext, using the pool set with RunWithPool.
+func (b *SelectBuilder) QueryRowAttached(ctx context.Context) RowScanner {
+	return b.QueryRowContext(ctx, nil)
 }
 
 // Scan is a shortcut for QueryRow().Scan.
@@ -83,6 +107,47 @@ func (b *SelectBuilder) Scan(ctx context.Context, pool instapgxpool.Pool, dest .
 	return b.QueryRowContext(ctx, pool).Scan(dest...)
 }
 
+// ScanContext is a shortcut for QueryRowAttached(ctx).Scan.
+func (b *SelectBuilder) ScanContext(ctx context.Context, dest ...interface{}) error {
+	return b.QueryRowAttached(ctx).Scan(dest...)
+}
+
+// RunWithPool sets the pool used by ExecContext, QueryContext and
+// QueryRowContext when none is passed explicitly.
+func (b *SelectBuilder) RunWithPool(pool instapgxpool.Pool) *SelectBuilder {
+	b.runWithPool = pool
+	return b
+}
+
+// DryRun makes ExecContext, QueryContext and QueryRowContext return a
+// DryRunResult with the SQL and args instead of running the query.
+func (b *SelectBuilder) DryRun() *SelectBuilder {
+	b.dryRun = dryRunSQL
+	return b
+}
+
+// DryRunExplain is like DryRun, but also runs EXPLAIN for the query and
+// returns the plan with the DryRunResult.
+func (b *SelectBuilder) DryRunExplain() *SelectBuilder {
+	b.dryRun = dryRunExplain
+	return b
+}
+
+// SimpleProtocol makes ExecContext, QueryContext and QueryRowContext run the
+// query using the simple query protocol, with all args inlined as literals
+// (see ToSqlInlined).
+func (b *SelectBuilder) SimpleProtocol() *SelectBuilder {
+	b.simpleProtocol = true
+	return b
+}
+
+// Strict makes ToSql reject suspicious content in the strings interpolated
+// verbatim, see StatementBuilderType.Strict.
+func (b *SelectBuilder) Strict() *SelectBuilder {
+	b.strict = true
+	return b
+}
+
 // PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
 // query.
 func (b *SelectBuilder) PlaceholderFormat(f PlaceholderFormat) *SelectBuilder {
@@ -90,6 +155,25 @@ func (b *SelectBuilder) PlaceholderFormat(f PlaceholderFormat) *SelectBuilder {
 	return b
 }
 
+// MustSql builds the query like ToSql, but panics on errors. It is meant
+// for queries which are known to be valid, e.g. package level statements.
+func (b *SelectBuilder) MustSql() (string, []interface{}) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// ToSqlInlined builds the query into a SQL string with all args inlined as
+// literals, e.g. for migration files or seed scripts. It fails for args
+// which can not be rendered as literals.
+func (b *SelectBuilder) ToSqlInlined() (string, error) {
+	c := *b
+	c.placeholderFormat = Question
+	return toSqlInlined(&c)
+}
+
 // ToSql builds the query into a SQL string and bound args.
 func (b *SelectBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 	if len(b.columns) == 0 {
@@ -97,15 +181,45 @@ func (b *SelectBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 		return
 	}
 
+	if b.strict {
+		if err = checkStrict(b); err != nil {
+			return
+		}
+	}
+	if err = b.schema.Validate(b); err != nil {
+		return
+	}
+	if err = b.whereConflicts.check(b.whereParts); err != nil {
+		return
+	}
+	if b.auditColumns {
+		if err = b.checkResultColumns(); err != nil {
+			return
+		}
+	}
+
 	sql := &bytes.Buffer{}
 
 	if len(b.prefixes) > 0 {
-		args, _ = b.prefixes.AppendToSql(sql, " ", args)
+		if args, err = b.prefixes.AppendToSql(sql, " ", args); err != nil {
+			return
+		}
 		sql.WriteString(" ")
 	}
 
 	sql.WriteString("SELECT ")
 
+	if len(b.hints) > 0 {
+		hints := strings.Join(b.hints, " ")
+		if strings.Contains(hints, "*/") {
+			err = fmt.Errorf("hints must not contain */")
+			return
+		}
+		sql.WriteString("/*+ ")
+		sql.WriteString(strings.Replace(hints, "?", "??", -1))
+		sql.WriteString(" */ ")
+	}
+
 	if b.distinct {
 		sql.WriteString("DISTINCT ")
 	}
@@ -132,15 +246,16 @@ func (b *SelectBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 
 	if len(b.joins) > 0 {
 		sql.WriteString(" ")
-		args, err = appendToSql(b.joins, sql, " ", args)
+		// Joins rendering the same, e.g. added twice by a helper, would
+		// make the query fail, so they are only written once.
+		args, err = appendToSql(mergeParts(nil, b.joins), sql, " ", args)
 		if err != nil {
 			return
 		}
 	}
 
 	if len(b.whereParts) > 0 {
-		sql.WriteString(" WHERE ")
-		args, err = appendToSql(b.whereParts, sql, " AND ", args)
+		args, err = appendConditionsToSql(b.whereParts, sql, " WHERE ", args)
 		if err != nil {
 			return
 		}
@@ -168,16 +283,19 @@ func (b *SelectBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 	}
 
 	if len(b.havingParts) > 0 {
-		sql.WriteString(" HAVING ")
-		args, err = appendToSql(b.havingParts, sql, " AND ", args)
+		args, err = appendConditionsToSql(b.havingParts, sql, " HAVING ", args)
 		if err != nil {
 			return
 		}
 	}
 
-	if len(b.orderBys) > 0 {
+	orderBys := b.orderBys
+	if len(orderBys) == 0 && b.defaultOrderable() {
+		orderBys = b.defaultOrderBys
+	}
+	if len(orderBys) > 0 {
 		sql.WriteString(" ORDER BY ")
-		sql.WriteString(strings.Join(b.orderBys, ", "))
+		sql.WriteString(strings.Join(orderBys, ", "))
 	}
 
 	// TODO: limit == 0 and offswt == 0 are valid. Need to go dbr way and implement offsetValid and limitValid
@@ -193,7 +311,9 @@ func (b *SelectBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 
 	if len(b.suffixes) > 0 {
 		sql.WriteString(" ")
-		args, _ = b.suffixes.AppendToSql(sql, " ", args)
+		if args, err = b.suffixes.AppendToSql(sql, " ", args); err != nil {
+			return
+		}
 	}
 
 	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
@@ -201,12 +321,81 @@ func (b *SelectBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 
 }
 
+// aggregateColumnRegexp matches result columns which are aggregate calls,
+// e.g. count(*) or max(id) AS last_id.
+var aggregateColumnRegexp = regexp.MustCompile(`(?i)^\s*(count|sum|avg|min|max|every|bool_and|bool_or|bit_and|bit_or|array_agg|string_agg|json_agg|jsonb_agg|json_object_agg|jsonb_object_agg)\s*\(`)
+
+// defaultOrderable reports whether the default ORDER BY can be applied.
+// It refers to columns of a relation, so it is not valid for expression-only
+// selects like SELECT set_config(...), and Postgres rejects it for grouped,
+// aggregate-only and DISTINCT selects unless it is part of the result.
+func (b *SelectBuilder) defaultOrderable() bool {
+	if len(b.fromParts) == 0 || len(b.groupBys) > 0 || b.distinct {
+		return false
+	}
+	for _, option := range b.options {
+		if strings.Contains(strings.ToUpper(option), "DISTINCT") {
+			return false
+		}
+	}
+	if len(b.columns) == 0 {
+		return true
+	}
+	for _, column := range b.columns {
+		sql, _, err := column.ToSql()
+		if err != nil || !aggregateColumnRegexp.MatchString(sql) {
+			return true
+		}
+	}
+	return false
+}
+
+// nested returns s for use inside another statement. The default ORDER BY
+// of a SelectBuilder only applies to the outermost query, so it is built from
+// a copy without one.
+func nested(s Sqlizer) Sqlizer {
+	if sb, ok := s.(*SelectBuilder); ok && len(sb.defaultOrderBys) > 0 {
+		c := *sb
+		c.defaultOrderBys = nil
+		return &c
+	}
+	return s
+}
+
 // Prefix adds an expression to the beginning of the query
 func (b *SelectBuilder) Prefix(sql string, args ...interface{}) *SelectBuilder {
 	b.prefixes = append(b.prefixes, Expr(sql, args...))
 	return b
 }
 
+// PrefixExpr adds an expression built by a Sqlizer, e.g. a SelectBuilder
+// used as the body of a CTE, to the beginning of the query. Its args are
+// merged in order and its errors are returned by ToSql.
+func (b *SelectBuilder) PrefixExpr(e Sqlizer) *SelectBuilder {
+	b.prefixes = append(b.prefixes, Expr("?", e))
+	return b
+}
+
+// Hint adds a planner hint for pg_hint_plan to the query. All hints are
+// merged into a single /*+ ... */ comment placed directly after SELECT,
+// where pg_hint_plan looks for it. The hint may be given with or without
+// the surrounding comment markers. Question marks in the hint are not
+// placeholders and are sent verbatim, like the content of Raw.
+//
+// Ex:
+//     Select("*").From("t").Hint("/*+ IndexScan(t t_idx) */")
+//     == "SELECT /*+ IndexScan(t t_idx) */ * FROM t"
+func (b *SelectBuilder) Hint(hint string) *SelectBuilder {
+	hint = strings.TrimSpace(hint)
+	if strings.HasPrefix(hint, "/*+") && strings.HasSuffix(hint, "*/") {
+		hint = strings.TrimSpace(hint[3 : len(hint)-2])
+	}
+	if len(hint) > 0 {
+		b.hints = append(b.hints, hint)
+	}
+	return b
+}
+
 // Distinct adds a DISTINCT clause to the query.
 func (b *SelectBuilder) Distinct() *SelectBuilder {
 	b.distinct = true
@@ -231,14 +420,28 @@ func (b *SelectBuilder) Columns(columns ...string) *SelectBuilder {
 	return b
 }
 
+// ColumnExprs adds result columns built by Sqlizers to the query, binding
+// their args in order.
+func (b *SelectBuilder) ColumnExprs(columns ...Sqlizer) *SelectBuilder {
+	for _, column := range columns {
+		b.columns = append(b.columns, column)
+	}
+	return b
+}
+
 // Column adds a result column to the query.
 // Unlike Columns, Column accepts args which will be bound to placeholders in
 // the columns string, for example:
 //   Column("IF(col IN ("+Placeholders(3)+"), 1, 0) as col", 1, 2, 3)
+// A SelectBuilder column is added as a subquery, aliased by the first arg
+// if given.
 func (b *SelectBuilder) Column(column interface{}, args ...interface{}) *SelectBuilder {
 	if col, ok := column.(*SelectBuilder); ok == true {
-		sql, _, _ := col.ToSql()
-		column = fmt.Sprintf("(%s) AS %s", sql, args[0])
+		column = Subquery(col)
+		if len(args) > 0 {
+			column = Expr(fmt.Sprintf("? AS %s", args[0]), column)
+		}
+		args = nil
 	}
 
 	b.columns = append(b.columns, newPart(column, args...))
@@ -246,6 +449,14 @@ func (b *SelectBuilder) Column(column interface{}, args ...interface{}) *SelectB
 	return b
 }
 
+// Cols adds column references as result columns to the query.
+func (b *SelectBuilder) Cols(cols ...Col) *SelectBuilder {
+	for _, col := range cols {
+		b.columns = append(b.columns, col)
+	}
+	return b
+}
+
 func (b *SelectBuilder) Coalesce(column string, args ...string) *SelectBuilder {
 	b.columns = append(b.columns, coalesce{column: column, args: args})
 	return b
@@ -281,6 +492,37 @@ func (b *SelectBuilder) JoinClause(pred interface{}, args ...interface{}) *Selec
 	return b
 }
 
+// JoinClauseOnce adds a join clause to the query unless a join with the same
+// key was added before. Joins which render the same are written only once
+// anyway; the key identifies joins which differ in their SQL, e.g. in their
+// conditions, but join the same relation under the same alias.
+//
+// Ex:
+//     withAuthor := func(b *SelectBuilder) *SelectBuilder {
+//         return b.JoinClauseOnce("authors a", "JOIN authors a ON a.id = p.author_id")
+//     }
+func (b *SelectBuilder) JoinClauseOnce(key string, pred interface{}, args ...interface{}) *SelectBuilder {
+	for _, k := range b.joinKeys {
+		if k == key {
+			return b
+		}
+	}
+	b.joinKeys = append(b.joinKeys, key)
+	return b.JoinClause(pred, args...)
+}
+
+// JoinOnce adds a JOIN clause to the query unless a join with the same key
+// was added before, see JoinClauseOnce.
+func (b *SelectBuilder) JoinOnce(key string, join string, rest ...interface{}) *SelectBuilder {
+	return b.JoinClauseOnce(key, "JOIN "+join, rest...)
+}
+
+// LeftJoinOnce adds a LEFT JOIN clause to the query unless a join with the
+// same key was added before, see JoinClauseOnce.
+func (b *SelectBuilder) LeftJoinOnce(key string, join string, rest ...interface{}) *SelectBuilder {
+	return b.JoinClauseOnce(key, "LEFT JOIN "+join, rest...)
+}
+
 // InnerJoin adds a INNER JOIN clause to the query.
 func (b SelectBuilder) InnerJoin(join string, rest ...interface{}) *SelectBuilder {
 	return b.JoinClause("INNER JOIN "+join, rest...)
@@ -301,6 +543,22 @@ func (b *SelectBuilder) RightJoin(join string, rest ...interface{}) *SelectBuild
 	return b.JoinClause("RIGHT JOIN "+join, rest...)
 }
 
+// JoinOn adds a JOIN clause to the query joining table on left being equal
+// to right.
+//
+// Ex:
+//     usersID := NewCol("users", "id")
+//     Select().Cols(usersID).From("users").JoinOn("emails", NewCol("emails", "user_id"), usersID)
+func (b *SelectBuilder) JoinOn(table string, left, right Col) *SelectBuilder {
+	return b.Join(table + " ON " + left.EqCol(right))
+}
+
+// LeftJoinOn adds a LEFT JOIN clause to the query joining table on left
+// being equal to right, see JoinOn.
+func (b *SelectBuilder) LeftJoinOn(table string, left, right Col) *SelectBuilder {
+	return b.LeftJoin(table + " ON " + left.EqCol(right))
+}
+
 // Where adds an expression to the WHERE clause of the query.
 //
 // Expressions are ANDed together in the generated SQL.
@@ -326,6 +584,32 @@ func (b *SelectBuilder) Where(pred interface{}, args ...interface{}) *SelectBuil
 	return b
 }
 
+// WhereIf adds an expression to the WHERE clause of the query if cond is
+// true.
+func (b *SelectBuilder) WhereIf(cond bool, pred interface{}, args ...interface{}) *SelectBuilder {
+	if cond {
+		b.Where(pred, args...)
+	}
+	return b
+}
+
+// WhereGroup adds a nested group of conditions, built by fn, to the WHERE
+// clause of the query.
+//
+// Ex:
+//     .WhereGroup(func(g *Group) {
+//         g.Or(func(g *Group) {
+//             g.Where("owner_id = ?", 1)
+//             g.WhereIf(public, "public")
+//         })
+//     })
+func (b *SelectBuilder) WhereGroup(fn func(g *Group)) *SelectBuilder {
+	group := &Group{}
+	fn(group)
+	b.whereParts = append(b.whereParts, group)
+	return b
+}
+
 // GroupBy adds GROUP BY expressions to the query.
 func (b *SelectBuilder) GroupBy(groupBys ...string) *SelectBuilder {
 	b.groupBys = append(b.groupBys, groupBys...)
@@ -340,12 +624,59 @@ func (b *SelectBuilder) Having(pred interface{}, rest ...interface{}) *SelectBui
 	return b
 }
 
+// HavingIf adds an expression to the HAVING clause of the query if cond is
+// true.
+func (b *SelectBuilder) HavingIf(cond bool, pred interface{}, rest ...interface{}) *SelectBuilder {
+	if cond {
+		b.Having(pred, rest...)
+	}
+	return b
+}
+
+// HavingGroup adds a nested group of conditions, built by fn, to the HAVING
+// clause of the query.
+//
+// Ex:
+//     .HavingGroup(func(g *Group) {
+//         g.Or(func(g *Group) {
+//             g.WhereIf(withSum, "sum(amount) > ?", 100)
+//             g.WhereIf(withCount, "count(*) > ?", 10)
+//         })
+//     })
+func (b *SelectBuilder) HavingGroup(fn func(g *Group)) *SelectBuilder {
+	group := &Group{}
+	fn(group)
+	b.havingParts = append(b.havingParts, group)
+	return b
+}
+
 // OrderBy adds ORDER BY expressions to the query.
 func (b *SelectBuilder) OrderBy(orderBys ...string) *SelectBuilder {
 	b.orderBys = append(b.orderBys, orderBys...)
 	return b
 }
 
+// OrderByCols adds ORDER BY expressions ordering ascending by cols to the
+// query. Use OrderBy with Col.Desc to order descending.
+func (b *SelectBuilder) OrderByCols(cols ...Col) *SelectBuilder {
+	for _, col := range cols {
+		b.orderBys = append(b.orderBys, col.String())
+	}
+	return b
+}
+
+// DefaultOrderBy sets ORDER BY expressions used if the query has neither
+// ORDER BY nor GROUP BY expressions, replacing those set with
+// StatementBuilderType.DefaultOrderBy. Call it without expressions to leave
+// the query unordered.
+//
+// They are not applied to DISTINCT selects, selects of aggregates only like
+// count(*), and selects used inside another statement, e.g. as a subquery.
+func (b *SelectBuilder) DefaultOrderBy(orderBys ...string) *SelectBuilder {
+	b.defaultOrderBys = orderBys
+	return b
+}
+
 // Limit sets a LIMIT clause on the query.
 func (b *SelectBuilder) Limit(limit uint64) *SelectBuilder {
 	b.limit = limit
@@ -367,11 +698,19 @@ func (b *SelectBuilder) Suffix(sql string, args ...interface{}) *SelectBuilder {
 	return b
 }
 
+// SuffixExpr adds an expression built by a Sqlizer to the end of the query.
+// Its args are merged in order and its errors are returned by ToSql.
+func (b *SelectBuilder) SuffixExpr(e Sqlizer) *SelectBuilder {
+	b.suffixes = append(b.suffixes, Expr("?", e))
+	return b
+}
+
 // Build a COUNT of the current query, without limit and offset
 func (b *SelectBuilder) Count(alias string) *SelectBuilder {
 	b.columns = nil
 	b.Columns(fmt.Sprintf(`count(1) as %s`, alias))
 	b.orderBys = nil
+	b.defaultOrderBys = nil
 	b.limitValid = false
 	b.offsetValid = false
 	return b
@@ -1,6 +1,7 @@
 package sqrl
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -171,3 +172,300 @@ func TestSelectWithOptions(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "SELECT DISTINCT SQL_NO_CACHE * FROM foo", sql)
 }
+
+func TestSelectBuilderHavingIf(t *testing.T) {
+	build := func(withSum, withCount bool) (string, []interface{}) {
+		sql, args, err := Select("user_id").From("orders").GroupBy("user_id").
+			HavingIf(withSum, "sum(amount) > ?", 100).
+			HavingIf(withCount, "count(*) > ?", 10).
+			ToSql()
+		assert.NoError(t, err)
+		return sql, args
+	}
+
+	sql, args := build(true, true)
+	assert.Equal(t, "SELECT user_id FROM orders GROUP BY user_id HAVING sum(amount) > ? AND count(*) > ?", sql)
+	assert.Equal(t, []interface{}{100, 10}, args)
+
+	sql, args = build(false, true)
+	assert.Equal(t, "SELECT user_id FROM orders GROUP BY user_id HAVING count(*) > ?", sql)
+	assert.Equal(t, []interface{}{10}, args)
+
+	sql, args = build(false, false)
+	assert.Equal(t, "SELECT user_id FROM orders GROUP BY user_id", sql)
+	assert.Nil(t, args)
+}
+
+func TestSelectBuilderHavingGroup(t *testing.T) {
+	sql, args, err := Select("user_id").From("orders").GroupBy("user_id").
+		Having("max(amount) < ?", 1000).
+		HavingGroup(func(g *Group) {
+			g.Or(func(g *Group) {
+				g.Where("sum(amount) > ?", 100)
+				g.WhereIf(false, "avg(amount) > ?", 5)
+				g.And(func(g *Group) {
+					g.Where("count(*) > ?", 10)
+					g.Where(Eq{"min(status)": "paid"})
+				})
+			})
+		}).
+		HavingGroup(func(g *Group) {}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT user_id FROM orders GROUP BY user_id HAVING max(amount) < ? AND "+
+		"(sum(amount) > ? OR (count(*) > ? AND min(status) = ?))", sql)
+	assert.Equal(t, []interface{}{1000, 100, 10, "paid"}, args)
+}
+
+func TestSelectBuilderWhereIfGroup(t *testing.T) {
+	sql, args, err := Select("*").From("docs").
+		WhereIf(true, "deleted_at IS NULL").
+		WhereIf(false, "draft = ?", true).
+		WhereGroup(func(g *Group) {
+			g.Or(func(g *Group) {
+				g.Where("owner_id = ?", 1)
+				g.WhereIf(true, "public")
+			})
+		}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM docs WHERE deleted_at IS NULL AND (owner_id = ? OR public)", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	_, _, err = Select("*").From("docs").WhereGroup(func(g *Group) { g.Where(1) }).ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderHint(t *testing.T) {
+	sql, args, err := Select("*").From("t").
+		Prefix("WITH x AS (SELECT 1)").
+		Hint("/*+ IndexScan(t t_idx) */").
+		Hint("Leading(t x)").
+		Distinct().
+		Where("a = ?", 1).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "WITH x AS (SELECT 1) SELECT /*+ IndexScan(t t_idx) Leading(t x) */ DISTINCT * FROM t WHERE a = $1", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, _, err = Select("*").From("t").Hint("  ").Hint("/*+ */").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t", sql)
+
+	sql, _, err = Select("*").From("t").Hint("Set(x '?')").PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT /*+ Set(x '?') */ * FROM t", sql)
+
+	_, _, err = Select("*").From("t").Hint("SeqScan(t) */ DROP TABLE t; /*").ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderHintQuestion(t *testing.T) {
+	b := Select("*").From("t").Hint("Set(x '?')").Where("id = ?", 1)
+
+	sql, _, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT /*+ Set(x '??') */ * FROM t WHERE id = ?", sql)
+
+	pool := newPoolStub()
+	_, err = b.ExecContext(context.Background(), pool)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"SELECT /*+ Set(x '?') */ * FROM t WHERE id = ?"}, pool.stub.sqls)
+}
+
+func TestSelectBuilderToSqlInlined(t *testing.T) {
+	b := StatementBuilder.PlaceholderFormat(Dollar).
+		Select("id").
+		From("users").
+		Where("name = ? AND note = '??'", "O'Brien").
+		Where(Eq{"id": []int{1, 2}})
+
+	sql, err := b.ToSqlInlined()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE name = 'O''Brien' AND note = '?' AND id IN (1,2)", sql)
+
+	// The builder keeps its placeholder format.
+	sql, _, err = b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE name = $1 AND note = '?' AND id IN ($2,$3)", sql)
+
+	_, err = Select("id").From("users").Where("tags && ?", struct{}{}).ToSqlInlined()
+	assert.EqualError(t, err, "can not render value of type struct {} as a literal")
+}
+
+func TestSelectBuilderDefaultOrderBy(t *testing.T) {
+	sb := StatementBuilder.DefaultOrderBy("id")
+
+	sql, _, err := sb.Select("id").From("users").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users ORDER BY id", sql)
+
+	sql, _, err = sb.Select("id").From("users").OrderBy("name").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users ORDER BY name", sql)
+
+	sql, _, err = sb.Select("id").From("users").DefaultOrderBy("created_at DESC", "id DESC").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users ORDER BY created_at DESC, id DESC", sql)
+
+	sql, _, err = sb.Select("team_id", "count(*)").From("users").GroupBy("team_id").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT team_id, count(*) FROM users GROUP BY team_id", sql)
+
+	sql, _, err = sb.Select("id").From("users").Count("n").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT count(1) as n FROM users", sql)
+
+	sql, _, err = sb.Select("id").From("users").Union(sb.Select("id").From("admins")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users UNION SELECT id FROM admins ORDER BY id", sql)
+
+	sql, _, err = sb.Select("count(*)").From("users").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT count(*) FROM users", sql)
+
+	sql, _, err = sb.Select("count(*) AS n", "MAX(created_at)").From("users").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT count(*) AS n, MAX(created_at) FROM users", sql)
+
+	sql, _, err = sb.Select("name").Distinct().From("users").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT DISTINCT name FROM users", sql)
+
+	sql, _, err = sb.Select("name").Options("DISTINCT ON (name)").From("users").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT DISTINCT ON (name) name FROM users", sql)
+
+	sql, _, err = sb.Select("id").From("users").DefaultOrderBy().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users", sql)
+
+	teams := sb.Select("id").From("teams").Where("name = ?", "a")
+	sql, _, err = sb.Select("id").From("users").Where(Eq{"team_id": teams}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE team_id = (SELECT id FROM teams WHERE name = ?) ORDER BY id", sql)
+
+	sql, _, err = sb.Select("id").From("users").Where(Expr("EXISTS (?)", teams)).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE EXISTS (SELECT id FROM teams WHERE name = ?) ORDER BY id", sql)
+
+	sql, _, err = sb.Select("t.id").FromSelect(teams, "t").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT t.id FROM (SELECT id FROM teams WHERE name = ?) AS t ORDER BY id", sql)
+
+	sql, _, err = Insert("archived_teams").Select(teams).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO archived_teams SELECT id FROM teams WHERE name = ?", sql)
+}
+
+func TestSelectBuilderPrefixSuffixExpr(t *testing.T) {
+	active := Select("id").From("users").Where("active = ?", true)
+	sql, args, err := Select("*").
+		PrefixExpr(Expr("WITH active AS (?)", active)).
+		From("active").
+		Where("id > ?", 1).
+		SuffixExpr(Expr("FOR UPDATE OF ?", Raw("active"))).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "WITH active AS (SELECT id FROM users WHERE active = $1) SELECT * FROM active WHERE id > $2 FOR UPDATE OF active", sql)
+	assert.Equal(t, []interface{}{true, 1}, args)
+
+	_, _, err = Select("*").From("users").PrefixExpr(Select()).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Update("users").Set("a", 1).SuffixExpr(Expr("RETURNING ?")).ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderColumnSubquery(t *testing.T) {
+	posts := Select("count(*)").From("posts").Where("posts.user_id = users.id AND draft = ?", false)
+	sql, args, err := Select("id").
+		Column(posts, "post_count").
+		Column(Select("max(created_at)").From("logins").Where("logins.user_id = users.id")).
+		From("users").
+		Where("id = ?", 1).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, (SELECT count(*) FROM posts WHERE posts.user_id = users.id AND draft = $1) AS post_count, "+
+		"(SELECT max(created_at) FROM logins WHERE logins.user_id = users.id) FROM users WHERE id = $2", sql)
+	assert.Equal(t, []interface{}{false, 1}, args)
+
+	_, _, err = Select("id").Column(Select(), "x").From("users").ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderMustSql(t *testing.T) {
+	sql, args := Select("id").From("users").Where("id = ?", 1).MustSql()
+	assert.Equal(t, "SELECT id FROM users WHERE id = ?", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	assert.Panics(t, func() { Select().MustSql() })
+	assert.Panics(t, func() { Insert("users").MustSql() })
+	assert.Panics(t, func() { Update("users").MustSql() })
+	assert.Panics(t, func() { Delete("").MustSql() })
+}
+
+func TestSelectBuilderDuplicateJoins(t *testing.T) {
+	sql, args, err := Select("p.id").
+		From("posts p").
+		Join("authors a ON a.id = p.author_id").
+		LeftJoin("tags t ON t.post_id = p.id AND t.name = ?", "go").
+		Join("authors a ON a.id = p.author_id").
+		LeftJoin("tags t ON t.post_id = p.id AND t.name = ?", "go").
+		LeftJoin("tags t2 ON t2.post_id = p.id AND t2.name = ?", "sql").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT p.id FROM posts p JOIN authors a ON a.id = p.author_id "+
+		"LEFT JOIN tags t ON t.post_id = p.id AND t.name = ? LEFT JOIN tags t2 ON t2.post_id = p.id AND t2.name = ?", sql)
+	assert.Equal(t, []interface{}{"go", "sql"}, args)
+}
+
+type joinArg struct{ id int }
+
+func (joinArg) GoString() string { return "joinArg" }
+
+func TestSelectBuilderDuplicateJoinsArgs(t *testing.T) {
+	sql, args, err := Select("p.id").
+		From("posts p").
+		Join("tags t ON t.post_id = p.id AND t.name = ?", "go").
+		Join("tags t ON t.post_id = p.id AND t.name = ?", "sql").
+		Join("users u ON u.id = ?", joinArg{1}).
+		Join("users u ON u.id = ?", joinArg{2}).
+		Join("users u ON u.id = ?", joinArg{2}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT p.id FROM posts p JOIN tags t ON t.post_id = p.id AND t.name = ? "+
+		"JOIN tags t ON t.post_id = p.id AND t.name = ? JOIN users u ON u.id = ? JOIN users u ON u.id = ?", sql)
+	assert.Equal(t, []interface{}{"go", "sql", joinArg{1}, joinArg{2}}, args)
+}
+
+func TestSelectBuilderJoinOnce(t *testing.T) {
+	sql, _, err := Select("p.id").
+		From("posts p").
+		JoinOnce("authors a", "authors a ON a.id = p.author_id").
+		LeftJoinOnce("authors a", "authors a ON a.id = p.editor_id").
+		JoinClauseOnce("stats s", "CROSS JOIN stats s").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT p.id FROM posts p JOIN authors a ON a.id = p.author_id CROSS JOIN stats s", sql)
+}
+
+func TestSelectExpr(t *testing.T) {
+	sql, args, err := SelectExpr(Expr("set_config(?, ?, true)", "app.user_id", "42")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT set_config(?, ?, true)", sql)
+	assert.Equal(t, []interface{}{"app.user_id", "42"}, args)
+
+	sql, args, err = StatementBuilder.PlaceholderFormat(Dollar).DefaultOrderBy("id").
+		SelectExpr(Expr("?::jsonb", `{"a":1}`), Expr("now() AS ts")).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT $1::jsonb, now() AS ts", sql)
+	assert.Equal(t, []interface{}{`{"a":1}`}, args)
+
+	_, _, err = SelectExpr().ToSql()
+	assert.Error(t, err)
+}
@@ -0,0 +1,90 @@
+package sqrl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgconn"
+	"strings"
+)
+
+// ConstraintMode is the mode SET CONSTRAINTS switches constraints to.
+type ConstraintMode string
+
+const (
+	// Deferred checks constraints at commit.
+	Deferred ConstraintMode = "DEFERRED"
+	// Immediate checks constraints at the end of each statement.
+	Immediate ConstraintMode = "IMMEDIATE"
+)
+
+// SetConstraintsBuilder builds SQL SET CONSTRAINTS statements. They only
+// affect the current transaction, so the statement must be run inside one.
+//
+// Ex:
+//     sql, _, _ := SetConstraints(Deferred).ToSql()
+//     tx.Exec(ctx, sql) // SET CONSTRAINTS ALL DEFERRED
+type SetConstraintsBuilder struct {
+	StatementBuilderType
+
+	names []string
+	mode  ConstraintMode
+}
+
+// NewSetConstraintsBuilder creates new instance of SetConstraintsBuilder
+func NewSetConstraintsBuilder(b StatementBuilderType) *SetConstraintsBuilder {
+	return &SetConstraintsBuilder{StatementBuilderType: b}
+}
+
+// ExecContext builds and Execs the statement using given context.
+func (b *SetConstraintsBuilder) ExecContext(ctx context.Context, pool instapgxpool.Pool) (pgconn.CommandTag, error) {
+	return b.execContext(ctx, pool, b)
+}
+
+// ExecAttached is like ExecContext, using the pool set with RunWithPool.
+func (b *SetConstraintsBuilder) ExecAttached(ctx context.Context) (pgconn.CommandTag, error) {
+	return b.ExecContext(ctx, nil)
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// statement.
+func (b *SetConstraintsBuilder) PlaceholderFormat(f PlaceholderFormat) *SetConstraintsBuilder {
+	b.placeholderFormat = f
+	return b
+}
+
+// ToSql builds the statement into a SQL string and bound args.
+func (b *SetConstraintsBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
+	if b.mode != Deferred && b.mode != Immediate {
+		err = fmt.Errorf("set constraints mode must be DEFERRED or IMMEDIATE, got %q", b.mode)
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	sql.WriteString("SET CONSTRAINTS ")
+	if len(b.names) == 0 {
+		sql.WriteString("ALL")
+	} else {
+		sql.WriteString(strings.Join(b.names, ", "))
+	}
+	sql.WriteString(" ")
+	sql.WriteString(string(b.mode))
+
+	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
+	return
+}
+
+// Names restricts the statement to the given constraints. Without names,
+// all deferrable constraints are affected.
+func (b *SetConstraintsBuilder) Names(names ...string) *SetConstraintsBuilder {
+	b.names = append(b.names, names...)
+	return b
+}
+
+// Mode sets the mode the constraints are switched to.
+func (b *SetConstraintsBuilder) Mode(mode ConstraintMode) *SetConstraintsBuilder {
+	b.mode = mode
+	return b
+}
@@ -0,0 +1,23 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetConstraintsBuilderToSql(t *testing.T) {
+	sql, args, err := SetConstraints(Deferred).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SET CONSTRAINTS ALL DEFERRED", sql)
+	assert.Empty(t, args)
+
+	sql, _, err = SetConstraints(Immediate, "orders_user_fk", "items_order_fk").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SET CONSTRAINTS orders_user_fk, items_order_fk IMMEDIATE", sql)
+}
+
+func TestSetConstraintsBuilderToSqlErr(t *testing.T) {
+	_, _, err := SetConstraints("LATER").ToSql()
+	assert.EqualError(t, err, `set constraints mode must be DEFERRED or IMMEDIATE, got "LATER"`)
+}
@@ -0,0 +1,96 @@
+package sqrl
+
+import (
+	"fmt"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"hash/fnv"
+	"reflect"
+)
+
+// ShardedTable returns the name of the table for shardKey by formatting
+// pattern with it, for deployments with one table per shard key.
+//
+// Ex:
+//     Select("*").From(ShardedTable("events_%d", customerID))
+//     == "SELECT * FROM events_42"
+func ShardedTable(pattern string, shardKey interface{}) string {
+	return fmt.Sprintf(pattern, shardKey)
+}
+
+// ShardRouter maps shard keys to shards and routes them to the pool
+// holding the shard, so builders can run unchanged against sharded
+// deployments.
+//
+// Ex:
+//     router := NewShardRouter(pool0, pool1)
+//     pool, err := router.Pool(customerID)
+//     ...
+//     rows, err := Select("*").From(router.Table("events_%d", customerID)).QueryContext(ctx, pool)
+type ShardRouter struct {
+	pools []instapgxpool.Pool
+	shard func(key interface{}) int
+}
+
+// NewShardRouter creates a ShardRouter with one shard per pool. Integer
+// keys are mapped to shard key modulo the number of pools, all other keys
+// by their hash.
+func NewShardRouter(pools ...instapgxpool.Pool) *ShardRouter {
+	r := &ShardRouter{pools: pools}
+	r.shard = func(key interface{}) int {
+		return defaultShard(key, len(r.pools))
+	}
+	return r
+}
+
+// ShardFunc sets the function mapping shard keys to shards. Shards are
+// routed to pools by their index, so a pool may hold several shards if fn
+// returns more shards than there are pools.
+func (r *ShardRouter) ShardFunc(fn func(key interface{}) int) *ShardRouter {
+	r.shard = fn
+	return r
+}
+
+// Shard returns the shard of key.
+func (r *ShardRouter) Shard(key interface{}) int {
+	return r.shard(key)
+}
+
+// Table returns the name of the table for the shard of key by formatting
+// pattern with the shard.
+func (r *ShardRouter) Table(pattern string, key interface{}) string {
+	return fmt.Sprintf(pattern, r.Shard(key))
+}
+
+// Pool returns the pool holding the shard of key.
+func (r *ShardRouter) Pool(key interface{}) (instapgxpool.Pool, error) {
+	if len(r.pools) == 0 {
+		return nil, fmt.Errorf("shard router has no pools")
+	}
+	shard := r.Shard(key)
+	if shard < 0 {
+		return nil, fmt.Errorf("invalid shard %d for key %v", shard, key)
+	}
+	return r.pools[shard%len(r.pools)], nil
+}
+
+func defaultShard(key interface{}, shards int) int {
+	if shards == 0 {
+		return 0
+	}
+
+	v := reflect.ValueOf(key)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		shard := int(v.Int() % int64(shards))
+		if shard < 0 {
+			shard += shards
+		}
+		return shard
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(v.Uint() % uint64(shards))
+	}
+
+	h := fnv.New32a()
+	fmt.Fprint(h, key)
+	return int(h.Sum32() % uint32(shards))
+}
@@ -0,0 +1,56 @@
+package sqrl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardedTable(t *testing.T) {
+	sql, _, err := Select("*").From(ShardedTable("events_%d", 42)).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM events_42", sql)
+}
+
+func TestShardRouter(t *testing.T) {
+	pool0, pool1 := newPoolStub(), newPoolStub()
+	router := NewShardRouter(pool0, pool1)
+
+	assert.Equal(t, 1, router.Shard(3))
+	assert.Equal(t, 0, router.Shard(int64(4)))
+	assert.Equal(t, 1, router.Shard(-3))
+	assert.Equal(t, "events_1", router.Table("events_%d", uint(5)))
+	assert.Equal(t, router.Shard("acme"), router.Shard("acme"))
+
+	pool, err := router.Pool(3)
+	assert.NoError(t, err)
+	assert.True(t, pool == pool1)
+
+	pool0.stub.tag = nil
+	pool, err = router.Pool(2)
+	assert.NoError(t, err)
+	_, err = Delete(router.Table("events_%d", 2)).Where("id = ?", 1).ExecContext(context.Background(), pool)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"DELETE FROM events_0 WHERE id = ?"}, pool0.stub.sqls)
+	assert.Empty(t, pool1.stub.sqls)
+}
+
+func TestShardRouterShardFunc(t *testing.T) {
+	pool0, pool1 := newPoolStub(), newPoolStub()
+	router := NewShardRouter(pool0, pool1).ShardFunc(func(key interface{}) int {
+		return len(key.(string))
+	})
+
+	assert.Equal(t, "events_3", router.Table("events_%d", "abc"))
+	pool, err := router.Pool("abc")
+	assert.NoError(t, err)
+	assert.True(t, pool == pool1)
+
+	router.ShardFunc(func(key interface{}) int { return -1 })
+	_, err = router.Pool("abc")
+	assert.EqualError(t, err, "invalid shard -1 for key abc")
+
+	_, err = NewShardRouter().Pool(1)
+	assert.EqualError(t, err, "shard router has no pools")
+}
@@ -0,0 +1,57 @@
+package sqrl
+
+import (
+	"fmt"
+	"github.com/jackc/pgx/v4"
+)
+
+// SimpleProtocol makes child builders run their statements using the simple
+// query protocol, with all args inlined as literals (see
+// SelectBuilder.ToSqlInlined). This saves the parse and bind round trip of
+// the extended protocol, which dominates for statements that are run once
+// with many args, e.g. huge IN lists. Values are rendered so they can not
+// change the meaning of the statement; values which can not be rendered
+// safely, like strings with NUL bytes, make the statement fail.
+func (b StatementBuilderType) SimpleProtocol() StatementBuilderType {
+	b.simpleProtocol = true
+	return b
+}
+
+// sqlInliner is implemented by builders which can render their args as
+// literals.
+type sqlInliner interface {
+	ToSqlInlined() (string, error)
+}
+
+// simpleProtocolQuery runs s with args inlined using the simple protocol.
+type simpleProtocolQuery struct {
+	s     Sqlizer
+	query bool
+}
+
+// withProtocol wraps s to be run using the simple protocol if enabled.
+func (b StatementBuilderType) withProtocol(s Sqlizer, query bool) Sqlizer {
+	if !b.simpleProtocol {
+		return s
+	}
+	return simpleProtocolQuery{s: s, query: query}
+}
+
+// ToSql builds s with its args inlined. Queries get the QuerySimpleProtocol
+// option as only arg; pgx runs Execs without args using the simple protocol
+// anyway.
+func (q simpleProtocolQuery) ToSql() (sql string, args []interface{}, err error) {
+	if inliner, ok := q.s.(sqlInliner); ok {
+		sql, err = inliner.ToSqlInlined()
+	} else if sql, args, err = q.s.ToSql(); err == nil && len(args) > 0 {
+		err = fmt.Errorf("%T can not be run using the simple protocol as it has bound args", q.s)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	if q.query {
+		return sql, []interface{}{pgx.QuerySimpleProtocol(true)}, nil
+	}
+	return sql, nil, nil
+}
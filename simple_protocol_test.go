@@ -0,0 +1,73 @@
+package sqrl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimpleProtocolQuery(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{"a"}}}
+
+	b := StatementBuilder.PlaceholderFormat(Dollar).Select("name").From("users").
+		Where(Eq{"id": []int{1, 2, 3}}).
+		SimpleProtocol()
+
+	var name string
+	assert.NoError(t, b.QueryRowContext(context.Background(), pool).Scan(&name))
+	assert.Equal(t, "a", name)
+
+	assert.Equal(t, []string{"SELECT name FROM users WHERE id IN (1,2,3)"}, pool.stub.sqls)
+	assert.Equal(t, [][]interface{}{{pgx.QuerySimpleProtocol(true)}}, pool.stub.args)
+}
+
+func TestSimpleProtocolExec(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.tag = nil
+
+	sb := StatementBuilder.PlaceholderFormat(Dollar).SimpleProtocol()
+	_, err := sb.Delete("users").Where("name = ?", "O'Brien").ExecContext(context.Background(), pool)
+	assert.NoError(t, err)
+
+	_, err = sb.DropTable("users").ExecContext(context.Background(), pool)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"DELETE FROM users WHERE name = 'O''Brien'", "DROP TABLE users"}, pool.stub.sqls)
+	assert.Equal(t, [][]interface{}{nil, nil}, pool.stub.args)
+}
+
+func TestSimpleProtocolHostileValues(t *testing.T) {
+	pool := newPoolStub()
+	sb := StatementBuilder.PlaceholderFormat(Dollar).SimpleProtocol()
+
+	_, err := sb.Delete("users").Where("balance-? = 0", -1).Where("id = ?", 7).ExecContext(context.Background(), pool)
+	assert.NoError(t, err)
+
+	_, err = sb.Update("users").Set("name", `\'; DROP TABLE users; --`).Where("id = ?", 7).ExecContext(context.Background(), pool)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"DELETE FROM users WHERE balance-(-1) = 0 AND id = 7",
+		`UPDATE users SET name = E'\\''; DROP TABLE users; --' WHERE id = 7`,
+	}, pool.stub.sqls)
+
+	_, err = sb.Delete("users").Where("name = ?", "a\x00").ExecContext(context.Background(), pool)
+	assert.Error(t, err)
+	_, err = sb.Delete("users").Where("id = ?", 1, 2).ExecContext(context.Background(), pool)
+	assert.Error(t, err)
+	assert.Len(t, pool.stub.sqls, 2)
+}
+
+func TestSimpleProtocolError(t *testing.T) {
+	pool := newPoolStub()
+
+	_, err := Select("id").From("users").Where("data = ?", struct{}{}).SimpleProtocol().QueryContext(context.Background(), pool)
+	assert.Error(t, err)
+	assert.Empty(t, pool.stub.sqls)
+
+	_, _, err = simpleProtocolQuery{s: Expr("id = ?", 1)}.ToSql()
+	assert.EqualError(t, err, "sqrl.expr can not be run using the simple protocol as it has bound args")
+}
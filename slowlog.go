@@ -0,0 +1,176 @@
+package sqrl
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Logger is the logging interface used by SlowQueryLogger. It is
+// implemented by zap's SugaredLogger.
+type Logger interface {
+	Warnw(msg string, keysAndValues ...interface{})
+}
+
+// SlowQueryLogger logs queries which take longer than Threshold.
+//
+// Every entry contains the fingerprint of the statement, i.e. its SQL with
+// all literals and placeholders normalized to ?, and a short hash of it, so
+// slow queries can be grouped. Args are only logged if LogArgs is set; args
+// bound to one of the Redact columns are masked. If Redact is not empty, args
+// whose column can not be determined are masked as well.
+//
+// Ex:
+//     sqrl.SetSlowQueryLogger(&sqrl.SlowQueryLogger{
+//         Logger:    zapLogger.Sugar(),
+//         Threshold: 200 * time.Millisecond,
+//         LogArgs:   true,
+//         Redact:    []string{"email", "password_hash"},
+//     })
+type SlowQueryLogger struct {
+	Logger    Logger
+	Threshold time.Duration
+	LogArgs   bool
+	Redact    []string
+}
+
+type slowQueryLoggerHolder struct {
+	logger *SlowQueryLogger
+}
+
+var slowLog atomic.Value
+
+// SetSlowQueryLogger sets the SlowQueryLogger for all queries. Passing nil
+// disables slow query logging.
+func SetSlowQueryLogger(l *SlowQueryLogger) {
+	slowLog.Store(slowQueryLoggerHolder{logger: l})
+}
+
+func slowQueryLogger() *SlowQueryLogger {
+	holder, _ := slowLog.Load().(slowQueryLoggerHolder)
+	return holder.logger
+}
+
+// Redacted replaces redacted args in logs.
+const Redacted = "[REDACTED]"
+
+func (l *SlowQueryLogger) observe(s Sqlizer, query string, args []interface{}, duration time.Duration, err error) {
+	if duration < l.Threshold {
+		return
+	}
+
+	fingerprint := Fingerprint(query)
+	hash := fnv.New64a()
+	hash.Write([]byte(fingerprint))
+
+	keysAndValues := []interface{}{
+		"sql", fingerprint,
+		"fingerprint", fmt.Sprintf("%016x", hash.Sum64()),
+		"duration", duration,
+	}
+	if l.LogArgs {
+		keysAndValues = append(keysAndValues, "args", l.redact(s, query, args))
+	}
+	if err != nil {
+		keysAndValues = append(keysAndValues, "error", err)
+	}
+	l.Logger.Warnw("slow query", keysAndValues...)
+}
+
+func (l *SlowQueryLogger) redact(s Sqlizer, query string, args []interface{}) []interface{} {
+	if len(l.Redact) == 0 {
+		return args
+	}
+
+	columns := argColumns(s, query, len(args))
+	redacted := make([]interface{}, len(args))
+	for i, arg := range args {
+		column := columns[i]
+		if len(column) == 0 {
+			redacted[i] = Redacted
+			continue
+		}
+		redacted[i] = arg
+		if p := strings.LastIndexByte(column, '.'); p >= 0 {
+			column = column[p+1:]
+		}
+		for _, r := range l.Redact {
+			if strings.EqualFold(column, r) {
+				redacted[i] = Redacted
+				break
+			}
+		}
+	}
+	return redacted
+}
+
+var (
+	placeholderRegexp = regexp.MustCompile(`\$\d+|\?`)
+	argColumnRegexp   = regexp.MustCompile(`(?i)([\w.]+)\s*(?:=|<>|!=|<=|>=|<|>|(?:not\s+)?i?like|(?:not\s+)?in\s*\((?:\s*(?:\$\d+|\?)\s*,)*)\s*$`)
+)
+
+// argColumns guesses the column each of the n args of query is bound to.
+// Insert values are mapped by their position, all other args by the
+// column compared to in front of their placeholder. Unknown columns are
+// left empty.
+func argColumns(s Sqlizer, query string, n int) []string {
+	columns := make([]string, n)
+
+	if b, ok := s.(*InsertBuilder); ok && b.iselect == nil && len(b.columns) > 0 {
+		prefixArgs, _ := b.prefixes.AppendToSql(ioutil.Discard, " ", nil)
+		pos := len(prefixArgs)
+		for _, row := range b.values {
+			for c, val := range row {
+				count := 1
+				if sqlizer, ok := val.(Sqlizer); ok {
+					_, valArgs, _ := sqlizer.ToSql()
+					count = len(valArgs)
+				}
+				for ; count > 0 && pos < n; count-- {
+					if c < len(b.columns) {
+						columns[pos] = b.columns[c]
+					}
+					pos++
+				}
+			}
+		}
+	}
+
+	matches := placeholderRegexp.FindAllStringIndex(query, -1)
+	for i, match := range matches {
+		if i >= n {
+			break
+		}
+		if len(columns[i]) > 0 {
+			continue
+		}
+		if m := argColumnRegexp.FindStringSubmatch(query[:match[0]]); m != nil {
+			columns[i] = m[1]
+		}
+	}
+	return columns
+}
+
+var (
+	fingerprintStringRegexp = regexp.MustCompile(`'(?:[^']|'')*'`)
+	fingerprintValueRegexp  = regexp.MustCompile(`\$\d+|\b\d+(?:\.\d+)?\b`)
+	fingerprintListRegexp   = regexp.MustCompile(`\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+)
+
+// Fingerprint normalizes query for grouping: string and number literals as
+// well as placeholders are replaced by ?, lists of them are collapsed and
+// whitespace is condensed.
+//
+// Ex:
+//     Fingerprint("SELECT * FROM t WHERE id IN ($1,$2) AND name = 'x'")
+//     == "SELECT * FROM t WHERE id IN (?) AND name = ?"
+func Fingerprint(query string) string {
+	query = fingerprintStringRegexp.ReplaceAllString(query, "?")
+	query = fingerprintValueRegexp.ReplaceAllString(query, "?")
+	query = fingerprintListRegexp.ReplaceAllString(query, "(?)")
+	return strings.Join(strings.Fields(query), " ")
+}
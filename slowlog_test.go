@@ -0,0 +1,106 @@
+package sqrl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type loggerStub struct {
+	entries [][]interface{}
+}
+
+func (l *loggerStub) Warnw(msg string, keysAndValues ...interface{}) {
+	l.entries = append(l.entries, append([]interface{}{msg}, keysAndValues...))
+}
+
+func (l *loggerStub) value(entry int, key string) interface{} {
+	kv := l.entries[entry][1:]
+	for i := 0; i < len(kv); i += 2 {
+		if kv[i] == key {
+			return kv[i+1]
+		}
+	}
+	return nil
+}
+
+func withSlowLog(t *testing.T, l *SlowQueryLogger) *loggerStub {
+	logger := &loggerStub{}
+	l.Logger = logger
+	SetSlowQueryLogger(l)
+	t.Cleanup(func() { SetSlowQueryLogger(nil) })
+	return logger
+}
+
+func TestSlowQueryLogger(t *testing.T) {
+	logger := withSlowLog(t, &SlowQueryLogger{LogArgs: true, Redact: []string{"email", "password"}})
+	pool := newPoolStub()
+
+	_, err := Update("users").Set("name", "moe").Set("password", "secret").
+		Where(Eq{"users.email": "a@b.c"}).Where("id IN (?,?)", 1, 2).
+		PlaceholderFormat(Dollar).
+		ExecContext(context.Background(), pool)
+	assert.NoError(t, err)
+
+	_, err = Insert("users").Prefix("WITH x AS (SELECT ?)", 0).Columns("email", "name").
+		Values("a@b.c", "moe").Values(Expr("lower(?)", "X@Y.Z"), "larry").
+		ExecContext(context.Background(), pool)
+	assert.NoError(t, err)
+
+	if assert.Len(t, logger.entries, 2) {
+		assert.Equal(t, "slow query", logger.entries[0][0])
+		assert.Equal(t, "UPDATE users SET name = ?, password = ? WHERE users.email = ? AND id IN (?)", logger.value(0, "sql"))
+		assert.Len(t, logger.value(0, "fingerprint"), 16)
+		assert.Equal(t, []interface{}{"moe", Redacted, Redacted, 1, 2}, logger.value(0, "args"))
+		assert.Nil(t, logger.value(0, "error"))
+
+		assert.Equal(t, []interface{}{Redacted, Redacted, "moe", Redacted, "larry"}, logger.value(1, "args"))
+	}
+}
+
+func TestSlowQueryLoggerRedactUnknown(t *testing.T) {
+	logger := withSlowLog(t, &SlowQueryLogger{LogArgs: true, Redact: []string{"password"}})
+	pool := newPoolStub()
+
+	_, err := Select("id").From("users").
+		Where("crypt(?, salt) = hash", "secret").
+		Where("name = ?", "moe").
+		Where(Expr("? @> tags", "[1]")).
+		PlaceholderFormat(Dollar).
+		ExecContext(context.Background(), pool)
+	assert.NoError(t, err)
+
+	if assert.Len(t, logger.entries, 1) {
+		assert.Equal(t, []interface{}{Redacted, "moe", Redacted}, logger.value(0, "args"))
+	}
+}
+
+func TestSlowQueryLoggerThreshold(t *testing.T) {
+	logger := withSlowLog(t, &SlowQueryLogger{Threshold: time.Hour})
+	pool := newPoolStub()
+
+	_, err := Update("users").Set("a", 1).ExecContext(context.Background(), pool)
+	assert.NoError(t, err)
+	assert.Empty(t, logger.entries)
+}
+
+func TestSlowQueryLoggerNoArgs(t *testing.T) {
+	logger := withSlowLog(t, &SlowQueryLogger{})
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{1}}}
+
+	var id int
+	assert.NoError(t, Select("id").From("users").Where("email = ?", "a@b.c").Scan(context.Background(), pool, &id))
+	if assert.Len(t, logger.entries, 1) {
+		assert.Nil(t, logger.value(0, "args"))
+		assert.Equal(t, "SELECT id FROM users WHERE email = ?", logger.value(0, "sql"))
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	assert.Equal(t, "SELECT * FROM t1 WHERE id IN (?) AND name = ? AND n > ?",
+		Fingerprint("SELECT *\n  FROM t1 WHERE id IN ($1, $2,$3) AND name = 'it''s' AND n > 4.5"))
+	assert.Equal(t, Fingerprint("SELECT a FROM t WHERE id = $1"), Fingerprint("SELECT a FROM t WHERE id = 42"))
+}
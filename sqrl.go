@@ -5,6 +5,7 @@ package sqrl
 
 import (
 	"context"
+	"fmt"
 	"github.com/clevabit/utils-go/instapgxpool"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
@@ -18,27 +19,204 @@ type Sqlizer interface {
 	ToSql() (string, []interface{}, error)
 }
 
+// BuildError is returned by the execution helpers when s.ToSql fails. It
+// carries the type of the failing Sqlizer and the statement text, as far as
+// it was built.
+type BuildError struct {
+	Type string
+	SQL  string
+	Err  error
+}
+
+func newBuildError(s Sqlizer, query string, err error) *BuildError {
+	return &BuildError{Type: fmt.Sprintf("%T", s), SQL: query, Err: err}
+}
+
+func (e *BuildError) Error() string {
+	if len(e.SQL) == 0 {
+		return fmt.Sprintf("failed to build %s: %v", e.Type, e.Err)
+	}
+	return fmt.Sprintf("failed to build %s %q: %v", e.Type, e.SQL, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *BuildError) Unwrap() error {
+	return e.Err
+}
+
+// buildQuery builds s for execution with ctx, wrapping build errors,
+// counting it against the query budget of ctx and appending the query tags
+// of ctx.
+func buildQuery(ctx context.Context, s Sqlizer) (string, []interface{}, error) {
+	query, args, err := statementSql(s)
+	if err != nil {
+		return "", nil, newBuildError(s, query, err)
+	}
+	if err := spendQueryBudget(ctx, query); err != nil {
+		return "", nil, err
+	}
+	return tagQuery(ctx, query), args, nil
+}
+
 // ExecWithContext Execs the SQL returned by s with db.
 func ExecWithContext(ctx context.Context, pool instapgxpool.Pool, s Sqlizer) (cmtTag pgconn.CommandTag, err error) {
-	query, args, err := s.ToSql()
+	if err := checkDryRun(ctx, pool, s); err != nil {
+		return nil, err
+	}
+	pool = contextPool(ctx, pool)
+	query, args, err := buildQuery(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	observer := newQueryObserver(ctx, s, query, args)
+	tx, err := runOptions(ctx, s).beginDeadlineTx(ctx, pool)
+	if err != nil {
+		observer.observe(0, err)
+		return nil, observer.wrapErr(err)
+	}
+	if tx != nil {
+		cmtTag, err = tx.Exec(ctx, query, args...)
+		err = finishTx(ctx, tx, err)
+	} else {
+		cmtTag, err = pool.Exec(ctx, query, args...)
+	}
+	observer.observe(cmtTag.RowsAffected(), err)
+	return cmtTag, observer.wrapErr(err)
+}
+
+// ExecAffecting Execs the SQL returned by s with pool and returns the number
+// of rows affected.
+func ExecAffecting(ctx context.Context, pool instapgxpool.Pool, s Sqlizer) (int64, error) {
+	cmtTag, err := ExecWithContext(ctx, pool, s)
+	if err != nil {
+		return 0, err
+	}
+	return cmtTag.RowsAffected(), nil
+}
+
+// RowsAffectedError is returned by ExecExpectingOne if the statement
+// affected no or more than one row, e.g. an UPDATE by id of a row which
+// does not exist.
+type RowsAffectedError struct {
+	StmtType string
+	Table    string
+	Expected int64
+	Affected int64
+}
+
+func (e *RowsAffectedError) Error() string {
+	if len(e.Table) == 0 {
+		return fmt.Sprintf("%s query affected %d rows, expected %d", e.StmtType, e.Affected, e.Expected)
+	}
+	return fmt.Sprintf("%s query on %s affected %d rows, expected %d", e.StmtType, e.Table, e.Affected, e.Expected)
+}
+
+// ExecExpectingOne Execs the SQL returned by s with pool and returns a
+// *RowsAffectedError if it did not affect exactly one row. The statement
+// is not rolled back in that case; run it in a transaction if it must not
+// take effect.
+//
+// Ex:
+//     err := sqrl.ExecExpectingOne(ctx, pool, sqrl.Update("users").Set("name", name).Where(sqrl.Eq{"id": id}))
+//     var rae *sqrl.RowsAffectedError
+//     if errors.As(err, &rae) && rae.Affected == 0 {
+//         return ErrUserNotFound
+//     }
+func ExecExpectingOne(ctx context.Context, pool instapgxpool.Pool, s Sqlizer) error {
+	affected, err := ExecAffecting(ctx, pool, s)
 	if err != nil {
-		return
+		return err
+	}
+	if affected != 1 {
+		stmtType, table := statementInfo(s)
+		return &RowsAffectedError{StmtType: stmtType, Table: table, Expected: 1, Affected: affected}
 	}
-	return pool.Exec(ctx, query, args...)
+	return nil
 }
 
 // QueryWithContext Querys the SQL returned by s with db.
 func QueryWithContext(ctx context.Context, pool instapgxpool.Pool, s Sqlizer) (rows pgx.Rows, err error) {
-	query, args, err := s.ToSql()
+	if err := checkDryRun(ctx, pool, s); err != nil {
+		return nil, err
+	}
+	pool = contextPool(ctx, pool)
+	query, args, err := buildQuery(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	observer := newQueryObserver(ctx, s, query, args)
+	tx, err := runOptions(ctx, s).beginDeadlineTx(ctx, pool)
+	if err != nil {
+		observer.observe(0, err)
+		return nil, observer.wrapErr(err)
+	}
+	if tx != nil {
+		rows, err = tx.Query(ctx, query, args...)
+		if err == nil {
+			rows = &txRows{Rows: rows, ctx: ctx, tx: tx}
+		} else {
+			tx.Rollback(ctx)
+		}
+	} else {
+		rows, err = pool.Query(ctx, query, args...)
+	}
 	if err != nil {
-		return
+		observer.observe(0, err)
+		return nil, observer.wrapErr(err)
 	}
-	return pool.Query(ctx, query, args...)
+	return &observedRows{Rows: rows, observer: observer}, nil
 }
 
-// QueryRowWithContext QueryRows the SQL returned by s with db.
+// QueryRowWithContext QueryRows the SQL returned by s with db. The returned
+// RowScanner is a *Row, which also provides the values and field
+// descriptions of the row.
+//
+// If s fails to build, the query is not sent to the database and the
+// BuildError is returned by Scan and Err of the returned Row.
 func QueryRowWithContext(ctx context.Context, pool instapgxpool.Pool, s Sqlizer) RowScanner {
-	query, args, err := s.ToSql()
-	return &Row{RowScanner: pool.QueryRow(ctx, query, args...), err: err}
+	if err := checkDryRun(ctx, pool, s); err != nil {
+		return &Row{err: err}
+	}
+	pool = contextPool(ctx, pool)
+	query, args, err := buildQuery(ctx, s)
+	if err != nil {
+		return &Row{err: err}
+	}
+	observer := newQueryObserver(ctx, s, query, args)
+	tx, err := runOptions(ctx, s).beginDeadlineTx(ctx, pool)
+	if err != nil {
+		observer.observe(0, err)
+		return &Row{err: observer.wrapErr(err)}
+	}
+	// The row is read with Query, like pgx does for QueryRow, so the Row
+	// can expose the field descriptions and values.
+	rows := &rowsRow{}
+	var row RowScanner = rows
+	if tx != nil {
+		rows.rows, rows.err = tx.Query(ctx, query, args...)
+		row = &txRow{RowScanner: rows, ctx: ctx, tx: tx}
+	} else {
+		rows.rows, rows.err = pool.Query(ctx, query, args...)
+	}
+	return &Row{RowScanner: &observedRow{RowScanner: row, observer: observer}, rows: rows}
 }
 
+// QueryEach Querys the SQL returned by s with db and calls fn for every row.
+//
+// Iteration stops at the first error returned by fn, which is then returned
+// by QueryEach. The rows are always closed and their error is checked once
+// iteration has finished.
+func QueryEach(ctx context.Context, pool instapgxpool.Pool, s Sqlizer, fn func(rows pgx.Rows) error) error {
+	rows, err := QueryWithContext(ctx, pool, s)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := fn(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -3,8 +3,11 @@ package sqrl
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"testing"
 
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -121,3 +124,104 @@ func TestWithToSqlErr(t *testing.T) {
 	err = QueryRowWith(db, sqlizer).Scan()
 	assert.Error(t, err)
 }
+
+func TestQueryRowWithContextBuildErr(t *testing.T) {
+	pool := newPoolStub()
+	row := QueryRowWithContext(context.Background(), pool, Select().From("users"))
+
+	err := row.(*Row).Err()
+	if assert.Error(t, err) {
+		var buildErr *BuildError
+		assert.True(t, errors.As(err, &buildErr))
+		assert.Equal(t, "*sqrl.SelectBuilder", buildErr.Type)
+		assert.Equal(t, "failed to build *sqrl.SelectBuilder: select statements must have at least one result column", err.Error())
+	}
+	assert.Equal(t, err, row.Scan())
+	assert.Empty(t, pool.stub.sqls)
+}
+
+func TestWithContextBuildErr(t *testing.T) {
+	pool := newPoolStub()
+
+	_, err := ExecWithContext(context.Background(), pool, Insert("t"))
+	assert.IsType(t, &BuildError{}, err)
+
+	_, err = QueryWithContext(context.Background(), pool, Update("t"))
+	assert.IsType(t, &BuildError{}, err)
+	assert.Empty(t, pool.stub.sqls)
+}
+
+func TestQueryEach(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{1}, {2}, {3}}}
+
+	var ids []int
+	err := QueryEach(context.Background(), pool, Select("id").From("users"), func(rows pgx.Rows) error {
+		var id int
+		err := rows.Scan(&id)
+		ids = append(ids, id)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, ids)
+	assert.Equal(t, []string{"SELECT id FROM users"}, pool.stub.sqls)
+}
+
+func TestQueryEachCallbackErr(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{1}, {2}, {3}}}
+	stop := errors.New("stop")
+
+	calls := 0
+	err := QueryEach(context.Background(), pool, Select("id").From("users"), func(rows pgx.Rows) error {
+		calls++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestQueryEachQueryErr(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.err = errors.New("query failed")
+
+	err := QueryEach(context.Background(), pool, Select("id").From("users"), func(rows pgx.Rows) error {
+		t.Fatal("callback must not be called")
+		return nil
+	})
+	assert.True(t, errors.Is(err, pool.stub.err))
+}
+
+func TestExecAffecting(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.tag = pgconn.CommandTag("UPDATE 3")
+
+	affected, err := ExecAffecting(context.Background(), pool, Update("users").Set("active", false))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), affected)
+
+	pool.stub.err = errors.New("exec failed")
+	_, err = ExecAffecting(context.Background(), pool, Update("users").Set("active", false))
+	assert.True(t, errors.Is(err, pool.stub.err))
+}
+
+func TestExecExpectingOne(t *testing.T) {
+	pool := newPoolStub()
+	b := Update("users").Set("name", "moe").Where(Eq{"id": 1})
+
+	pool.stub.tag = pgconn.CommandTag("UPDATE 1")
+	assert.NoError(t, ExecExpectingOne(context.Background(), pool, b))
+
+	pool.stub.tag = pgconn.CommandTag("UPDATE 0")
+	err := ExecExpectingOne(context.Background(), pool, b)
+	var rae *RowsAffectedError
+	if assert.True(t, errors.As(err, &rae)) {
+		assert.Equal(t, int64(0), rae.Affected)
+	}
+	assert.EqualError(t, err, "update query on users affected 0 rows, expected 1")
+
+	pool.stub.tag = pgconn.CommandTag("DELETE 2")
+	err = ExecExpectingOne(context.Background(), pool, Expr("DELETE FROM users"))
+	assert.True(t, errors.As(err, &rae))
+	assert.Equal(t, int64(2), rae.Affected)
+}
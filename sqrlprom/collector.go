@@ -0,0 +1,75 @@
+// Package sqrlprom provides a Prometheus implementation of
+// sqrl.MetricsCollector.
+package sqrlprom
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"time"
+)
+
+// Collector records query latency, read or affected rows and errors per
+// statement type and table.
+//
+// Ex:
+//     c := sqrlprom.NewCollector("myservice")
+//     prometheus.MustRegister(c)
+//     sb := sqrl.StatementBuilder.WithMetrics(c)
+type Collector struct {
+	duration *prometheus.HistogramVec
+	rows     *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+// NewCollector creates a Collector with the metrics
+// <namespace>_sqrl_query_duration_seconds, <namespace>_sqrl_query_rows and
+// <namespace>_sqrl_query_errors_total, labeled with the statement type and
+// table. The <namespace>_ prefix is left out if namespace is empty.
+func NewCollector(namespace string) *Collector {
+	labels := []string{"type", "table"}
+	return &Collector{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "sqrl",
+			Name:      "query_duration_seconds",
+			Help:      "Duration of queries by statement type and table.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+		rows: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "sqrl",
+			Name:      "query_rows",
+			Help:      "Rows read or affected by queries by statement type and table.",
+			Buckets:   []float64{0, 1, 10, 100, 1000, 10000, 100000},
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "sqrl",
+			Name:      "query_errors_total",
+			Help:      "Failed queries by statement type and table.",
+		}, labels),
+	}
+}
+
+// ObserveQuery implements sqrl.MetricsCollector.
+func (c *Collector) ObserveQuery(stmtType, table string, duration time.Duration, rows int64, err error) {
+	c.duration.WithLabelValues(stmtType, table).Observe(duration.Seconds())
+	if err != nil {
+		c.errors.WithLabelValues(stmtType, table).Inc()
+		return
+	}
+	c.rows.WithLabelValues(stmtType, table).Observe(float64(rows))
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.duration.Describe(ch)
+	c.rows.Describe(ch)
+	c.errors.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.duration.Collect(ch)
+	c.rows.Collect(ch)
+	c.errors.Collect(ch)
+}
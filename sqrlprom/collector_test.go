@@ -0,0 +1,101 @@
+package sqrlprom
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+// gather returns the metrics of c by family name.
+func gather(t *testing.T, c *Collector) map[string]*dto.MetricFamily {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(c)
+	families, err := reg.Gather()
+	assert.NoError(t, err)
+
+	byName := map[string]*dto.MetricFamily{}
+	for _, f := range families {
+		byName[f.GetName()] = f
+	}
+	return byName
+}
+
+// metric returns the metric of f with the given type and table labels.
+func metric(f *dto.MetricFamily, stmtType, table string) *dto.Metric {
+	for _, m := range f.GetMetric() {
+		labels := map[string]string{}
+		for _, l := range m.GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+		if labels["type"] == stmtType && labels["table"] == table {
+			return m
+		}
+	}
+	return nil
+}
+
+func TestCollector(t *testing.T) {
+	c := NewCollector("app")
+	c.ObserveQuery("select", "users", 20*time.Millisecond, 3, nil)
+	c.ObserveQuery("select", "users", 40*time.Millisecond, 5, nil)
+	c.ObserveQuery("delete", "orders", time.Millisecond, 0, errors.New("failed"))
+
+	families := gather(t, c)
+	assert.Len(t, families, 3)
+
+	duration := families["app_sqrl_query_duration_seconds"]
+	if assert.NotNil(t, duration) {
+		assert.Equal(t, dto.MetricType_HISTOGRAM, duration.GetType())
+		assert.Len(t, duration.GetMetric(), 2)
+		if m := metric(duration, "select", "users"); assert.NotNil(t, m) {
+			assert.Equal(t, uint64(2), m.GetHistogram().GetSampleCount())
+			assert.InDelta(t, 0.06, m.GetHistogram().GetSampleSum(), 1e-9)
+		}
+		if m := metric(duration, "delete", "orders"); assert.NotNil(t, m) {
+			assert.Equal(t, uint64(1), m.GetHistogram().GetSampleCount())
+		}
+	}
+
+	rows := families["app_sqrl_query_rows"]
+	if assert.NotNil(t, rows) {
+		assert.Len(t, rows.GetMetric(), 1, "failed queries must not observe rows")
+		if m := metric(rows, "select", "users"); assert.NotNil(t, m) {
+			assert.Equal(t, uint64(2), m.GetHistogram().GetSampleCount())
+			assert.Equal(t, 8.0, m.GetHistogram().GetSampleSum())
+		}
+	}
+
+	errs := families["app_sqrl_query_errors_total"]
+	if assert.NotNil(t, errs) {
+		assert.Equal(t, dto.MetricType_COUNTER, errs.GetType())
+		assert.Len(t, errs.GetMetric(), 1)
+		if m := metric(errs, "delete", "orders"); assert.NotNil(t, m) {
+			assert.Equal(t, 1.0, m.GetCounter().GetValue())
+		}
+	}
+}
+
+func TestCollectorDescribe(t *testing.T) {
+	ch := make(chan *prometheus.Desc, 10)
+	NewCollector("app").Describe(ch)
+	close(ch)
+
+	var descs []string
+	for d := range ch {
+		descs = append(descs, d.String())
+	}
+	if assert.Len(t, descs, 3) {
+		assert.Contains(t, descs[0], `fqName: "app_sqrl_query_duration_seconds"`)
+		assert.Contains(t, descs[1], `fqName: "app_sqrl_query_rows"`)
+		assert.Contains(t, descs[2], `fqName: "app_sqrl_query_errors_total"`)
+	}
+
+	ch = make(chan *prometheus.Desc, 10)
+	NewCollector("").Describe(ch)
+	close(ch)
+	assert.Contains(t, (<-ch).String(), `fqName: "sqrl_query_duration_seconds"`)
+}
@@ -0,0 +1,113 @@
+// Package sqrltest provides helpers for testing queries built with sqrl
+// against a real database.
+package sqrltest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/clevabit/sqrl"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"sort"
+	"testing"
+)
+
+// PlanNode is a node of a query plan as returned by EXPLAIN (FORMAT JSON).
+type PlanNode struct {
+	NodeType     string      `json:"Node Type"`
+	RelationName string      `json:"Relation Name"`
+	IndexName    string      `json:"Index Name"`
+	Plans        []*PlanNode `json:"Plans"`
+}
+
+// ParsePlan parses the output of EXPLAIN (FORMAT JSON) and returns the root
+// node of the plan.
+func ParsePlan(data []byte) (*PlanNode, error) {
+	var plans []struct {
+		Plan *PlanNode `json:"Plan"`
+	}
+	if err := json.Unmarshal(data, &plans); err != nil {
+		return nil, err
+	}
+	if len(plans) == 0 || plans[0].Plan == nil {
+		return nil, fmt.Errorf("explain output contains no plan")
+	}
+	return plans[0].Plan, nil
+}
+
+// Explain runs EXPLAIN (FORMAT JSON) for the statement built by s and
+// returns the root node of its plan. EXPLAIN without ANALYZE does not
+// execute the statement.
+func Explain(ctx context.Context, pool instapgxpool.Pool, s sqrl.Sqlizer) (*PlanNode, error) {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if err := pool.QueryRow(ctx, "EXPLAIN (FORMAT JSON) "+query, args...).Scan(&data); err != nil {
+		return nil, err
+	}
+	return ParsePlan(data)
+}
+
+// Walk calls fn for n and all nodes below it, depth first.
+func (n *PlanNode) Walk(fn func(node *PlanNode)) {
+	fn(n)
+	for _, child := range n.Plans {
+		child.Walk(fn)
+	}
+}
+
+// Indexes returns the sorted names of all indexes scanned by the plan.
+func (n *PlanNode) Indexes() []string {
+	seen := make(map[string]bool)
+	var indexes []string
+	n.Walk(func(node *PlanNode) {
+		if len(node.IndexName) > 0 && !seen[node.IndexName] {
+			seen[node.IndexName] = true
+			indexes = append(indexes, node.IndexName)
+		}
+	})
+	sort.Strings(indexes)
+	return indexes
+}
+
+// UsesIndex reports whether the plan scans index.
+func (n *PlanNode) UsesIndex(index string) bool {
+	for _, name := range n.Indexes() {
+		if name == index {
+			return true
+		}
+	}
+	return false
+}
+
+// AssertUsesIndex explains the statement built by s and fails t unless its
+// plan scans index. Note that the planner may prefer sequential scans on
+// tables with few rows, so tests should ANALYZE representative data first
+// or disable sequential scans with SET enable_seqscan = off.
+//
+// Ex:
+//     sqrltest.AssertUsesIndex(t, pool, sqrl.StatementBuilder.PlaceholderFormat(sqrl.Dollar).
+//         Select("*").From("users").Where(sqrl.Eq{"email": "a@b.c"}), "users_email_idx")
+func AssertUsesIndex(t testing.TB, pool instapgxpool.Pool, s sqrl.Sqlizer, index string) bool {
+	t.Helper()
+
+	plan, err := Explain(context.Background(), pool, s)
+	if err != nil {
+		t.Errorf("explain failed: %v", err)
+		return false
+	}
+	return assertUsesIndex(t, plan, index)
+}
+
+func assertUsesIndex(t testing.TB, plan *PlanNode, index string) bool {
+	t.Helper()
+
+	if !plan.UsesIndex(index) {
+		t.Errorf("expected plan to use index %q, but it uses %v", index, plan.Indexes())
+		return false
+	}
+	return true
+}
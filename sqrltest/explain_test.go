@@ -0,0 +1,61 @@
+package sqrltest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const nestedLoopPlan = `[
+  {
+    "Plan": {
+      "Node Type": "Nested Loop",
+      "Plans": [
+        {"Node Type": "Index Scan", "Relation Name": "users", "Index Name": "users_email_idx"},
+        {"Node Type": "Bitmap Heap Scan", "Relation Name": "posts", "Plans": [
+          {"Node Type": "Bitmap Index Scan", "Index Name": "posts_user_id_idx"}
+        ]}
+      ]
+    }
+  }
+]`
+
+func TestParsePlan(t *testing.T) {
+	plan, err := ParsePlan([]byte(nestedLoopPlan))
+	assert.NoError(t, err)
+	assert.Equal(t, "Nested Loop", plan.NodeType)
+	assert.Equal(t, []string{"posts_user_id_idx", "users_email_idx"}, plan.Indexes())
+	assert.True(t, plan.UsesIndex("posts_user_id_idx"))
+	assert.False(t, plan.UsesIndex("posts_pkey"))
+
+	_, err = ParsePlan([]byte(`[]`))
+	assert.EqualError(t, err, "explain output contains no plan")
+}
+
+type tbStub struct {
+	testing.TB
+	errors []string
+}
+
+func (t *tbStub) Helper() {}
+
+func (t *tbStub) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertUsesIndex(t *testing.T) {
+	plan, err := ParsePlan([]byte(`[{"Plan": {"Node Type": "Seq Scan", "Relation Name": "users"}}]`))
+	assert.NoError(t, err)
+
+	tb := &tbStub{}
+	assert.False(t, assertUsesIndex(tb, plan, "users_email_idx"))
+	assert.Equal(t, []string{`expected plan to use index "users_email_idx", but it uses []`}, tb.errors)
+
+	plan, err = ParsePlan([]byte(nestedLoopPlan))
+	assert.NoError(t, err)
+
+	tb = &tbStub{}
+	assert.True(t, assertUsesIndex(tb, plan, "users_email_idx"))
+	assert.Empty(t, tb.errors)
+}
@@ -0,0 +1,306 @@
+package sqrltest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgx/v4"
+	"io/ioutil"
+	"sync"
+)
+
+// Interaction is a statement run against the database together with its
+// outcome, as recorded by a Recorder and served by a Replayer.
+type Interaction struct {
+	SQL     string          `json:"sql"`
+	Args    []interface{}   `json:"args,omitempty"`
+	Columns []string        `json:"columns,omitempty"`
+	Rows    [][]interface{} `json:"rows,omitempty"`
+	Tag     string          `json:"tag,omitempty"`
+	Err     string          `json:"err,omitempty"`
+}
+
+// Recorder is a pool recording the statements run through it and their
+// results, e.g. to capture the database interactions of a service test once
+// and replay them with a Replayer afterwards. Results of queries are read
+// completely before they are handed out.
+//
+// Only Exec, Query and QueryRow are recorded, other methods are passed to
+// the wrapped pool.
+//
+// Ex:
+//     rec := sqrltest.NewRecorder(pool)
+//     err := service.Run(ctx, rec)
+//     err = rec.Save("testdata/run.json")
+type Recorder struct {
+	instapgxpool.Pool
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecorder returns a Recorder running statements with pool.
+func NewRecorder(pool instapgxpool.Pool) *Recorder {
+	return &Recorder{Pool: pool}
+}
+
+func (r *Recorder) record(i Interaction, err error) {
+	if err != nil {
+		i.Err = err.Error()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, i)
+}
+
+// Exec runs sql with the wrapped pool and records its command tag.
+func (r *Recorder) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	tag, err := r.Pool.Exec(ctx, sql, args...)
+	r.record(Interaction{SQL: sql, Args: args, Tag: string(tag)}, err)
+	return tag, err
+}
+
+// Query runs sql with the wrapped pool and records its rows.
+func (r *Recorder) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	i := Interaction{SQL: sql, Args: args}
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		r.record(i, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for _, field := range rows.FieldDescriptions() {
+		i.Columns = append(i.Columns, string(field.Name))
+	}
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			r.record(i, err)
+			return nil, err
+		}
+		i.Rows = append(i.Rows, values)
+	}
+	rows.Close()
+	i.Tag = string(rows.CommandTag())
+	if err := rows.Err(); err != nil {
+		r.record(i, err)
+		return nil, err
+	}
+	r.record(i, nil)
+	return newReplayRows(i), nil
+}
+
+// QueryRow runs sql with the wrapped pool and records its rows.
+func (r *Recorder) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	rows, err := r.Query(ctx, sql, args...)
+	return &replayRow{rows: rows, err: err}
+}
+
+// Interactions returns the interactions recorded so far.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// Save writes the interactions recorded so far to path as JSON, to be loaded
+// with LoadReplayer.
+func (r *Recorder) Save(path string) error {
+	data, err := json.MarshalIndent(r.Interactions(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// Replayer is a pool serving recorded interactions instead of running
+// statements against a database. Statements must be run in the recorded
+// order with the recorded SQL and args, compared in their JSON form;
+// otherwise they fail. Values of recorded rows are scanned by decoding
+// their JSON form into the destinations, or by calling Scan of
+// destinations implementing sql.Scanner.
+//
+// Only Exec, Query and QueryRow are implemented, other methods panic.
+//
+// Ex:
+//     pool, err := sqrltest.LoadReplayer("testdata/run.json")
+//     err = service.Run(ctx, pool)
+//     assert.NoError(t, pool.Done())
+type Replayer struct {
+	instapgxpool.Pool
+
+	mu           sync.Mutex
+	interactions []Interaction
+	pos          int
+}
+
+// NewReplayer returns a Replayer serving interactions in order.
+func NewReplayer(interactions []Interaction) *Replayer {
+	return &Replayer{interactions: interactions}
+}
+
+// LoadReplayer returns a Replayer serving the interactions saved to path by
+// Recorder.Save.
+func LoadReplayer(path string) (*Replayer, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var interactions []Interaction
+	if err := json.Unmarshal(data, &interactions); err != nil {
+		return nil, fmt.Errorf("invalid recording %s: %v", path, err)
+	}
+	return NewReplayer(interactions), nil
+}
+
+// next returns the next interaction, which must match sql and args.
+func (r *Replayer) next(sql string, args []interface{}) (Interaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.pos >= len(r.interactions) {
+		return Interaction{}, fmt.Errorf("unexpected statement %q, all %d recorded statements have been run", sql, len(r.interactions))
+	}
+	i := r.interactions[r.pos]
+	if i.SQL != sql {
+		return Interaction{}, fmt.Errorf("unexpected statement %q, expected %q", sql, i.SQL)
+	}
+	got, err := json.Marshal(args)
+	if err != nil {
+		return Interaction{}, err
+	}
+	want, err := json.Marshal(i.Args)
+	if err != nil {
+		return Interaction{}, err
+	}
+	if len(args) == 0 && len(i.Args) == 0 {
+		got, want = nil, nil
+	}
+	if string(got) != string(want) {
+		return Interaction{}, fmt.Errorf("unexpected args %s for statement %q, expected %s", got, sql, want)
+	}
+	r.pos++
+	if len(i.Err) > 0 {
+		return i, errors.New(i.Err)
+	}
+	return i, nil
+}
+
+// Exec returns the recorded command tag or error of sql.
+func (r *Replayer) Exec(_ context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	i, err := r.next(sql, args)
+	if err != nil {
+		return nil, err
+	}
+	return pgconn.CommandTag(i.Tag), nil
+}
+
+// Query returns the recorded rows or error of sql.
+func (r *Replayer) Query(_ context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	i, err := r.next(sql, args)
+	if err != nil {
+		return nil, err
+	}
+	return newReplayRows(i), nil
+}
+
+// QueryRow returns the first recorded row or the error of sql.
+func (r *Replayer) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	rows, err := r.Query(ctx, sql, args...)
+	return &replayRow{rows: rows, err: err}
+}
+
+// Done returns an error if not all recorded interactions have been
+// replayed.
+func (r *Replayer) Done() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.pos < len(r.interactions) {
+		return fmt.Errorf("%d of %d recorded statements have not been run, next is %q", len(r.interactions)-r.pos, len(r.interactions), r.interactions[r.pos].SQL)
+	}
+	return nil
+}
+
+// replayRows serves the rows of an Interaction as pgx.Rows.
+type replayRows struct {
+	i      Interaction
+	pos    int
+	closed bool
+}
+
+func newReplayRows(i Interaction) *replayRows {
+	return &replayRows{i: i}
+}
+
+func (r *replayRows) Close()                        { r.closed = true }
+func (r *replayRows) Err() error                    { return nil }
+func (r *replayRows) CommandTag() pgconn.CommandTag { return pgconn.CommandTag(r.i.Tag) }
+func (r *replayRows) RawValues() [][]byte           { return nil }
+
+func (r *replayRows) FieldDescriptions() []pgproto3.FieldDescription {
+	fields := make([]pgproto3.FieldDescription, len(r.i.Columns))
+	for i, column := range r.i.Columns {
+		fields[i] = pgproto3.FieldDescription{Name: []byte(column)}
+	}
+	return fields
+}
+
+func (r *replayRows) Next() bool {
+	if r.closed || r.pos >= len(r.i.Rows) {
+		r.closed = true
+		return false
+	}
+	r.pos++
+	return true
+}
+
+func (r *replayRows) Values() ([]interface{}, error) {
+	return r.i.Rows[r.pos-1], nil
+}
+
+func (r *replayRows) Scan(dest ...interface{}) error {
+	row := r.i.Rows[r.pos-1]
+	if len(dest) != len(row) {
+		return fmt.Errorf("number of field descriptions must equal number of destinations, got %d and %d", len(row), len(dest))
+	}
+	for i, d := range dest {
+		if scanner, ok := d.(sql.Scanner); ok {
+			if err := scanner.Scan(row[i]); err != nil {
+				return err
+			}
+			continue
+		}
+		data, err := json.Marshal(row[i])
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, d); err != nil {
+			return fmt.Errorf("cannot scan column %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// replayRow reads the first row of rows, like pgx's QueryRow.
+type replayRow struct {
+	rows pgx.Rows
+	err  error
+}
+
+func (r *replayRow) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	defer r.rows.Close()
+	if !r.rows.Next() {
+		return pgx.ErrNoRows
+	}
+	return r.rows.Scan(dest...)
+}
@@ -0,0 +1,72 @@
+package sqrltest
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/clevabit/sqrl"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var recordedInteractions = []Interaction{
+	{SQL: "SELECT id, name FROM users WHERE active = $1", Args: []interface{}{true}, Columns: []string{"id", "name"}, Rows: [][]interface{}{{1, "moe"}, {2, "larry"}}},
+	{SQL: "UPDATE users SET name = $1 WHERE id = $2", Args: []interface{}{"curly", 2}, Tag: "UPDATE 1"},
+	{SQL: "SELECT name FROM users WHERE id = $1", Args: []interface{}{3}, Err: "connection reset"},
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	sb := sqrl.StatementBuilder.PlaceholderFormat(sqrl.Dollar)
+	rec := NewRecorder(NewReplayer(recordedInteractions))
+
+	type user struct {
+		ID   int64
+		Name string
+	}
+	var users []user
+	err := sqrl.QueryEach(ctx, rec, sb.Select("id", "name").From("users").Where(sqrl.Eq{"active": true}), func(rows pgx.Rows) error {
+		var u user
+		err := rows.Scan(&u.ID, &u.Name)
+		users = append(users, u)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []user{{1, "moe"}, {2, "larry"}}, users)
+
+	assert.NoError(t, sqrl.ExecExpectingOne(ctx, rec, sb.Update("users").Set("name", "curly").Where(sqrl.Eq{"id": 2})))
+
+	var name string
+	err = sqrl.QueryRowWithContext(ctx, rec, sb.Select("name").From("users").Where(sqrl.Eq{"id": 3})).Scan(&name)
+	assert.EqualError(t, errors.Unwrap(err), "connection reset")
+
+	dir, err := ioutil.TempDir("", "sqrltest")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "run.json")
+	assert.NoError(t, rec.Save(path))
+
+	replay, err := LoadReplayer(path)
+	assert.NoError(t, err)
+	names, err := sqrl.Pluck[string](ctx, replay, sb.Select("name").From("users").Where(sqrl.Eq{"active": true}))
+	assert.EqualError(t, errors.Unwrap(err), `unexpected statement "SELECT name FROM users WHERE active = $1", expected "SELECT id, name FROM users WHERE active = $1"`)
+	assert.Nil(t, names)
+
+	users = nil
+	err = sqrl.QueryEach(ctx, replay, sb.Select("id", "name").From("users").Where(sqrl.Eq{"active": true}), func(rows pgx.Rows) error {
+		var u user
+		err := rows.Scan(&u.ID, &u.Name)
+		users = append(users, u)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []user{{1, "moe"}, {2, "larry"}}, users)
+	assert.EqualError(t, replay.Done(), `2 of 3 recorded statements have not been run, next is "UPDATE users SET name = $1 WHERE id = $2"`)
+
+	_, err = sqrl.ExecWithContext(ctx, replay, sb.Update("users").Set("name", "shemp").Where(sqrl.Eq{"id": 2}))
+	assert.EqualError(t, errors.Unwrap(err), `unexpected args ["shemp",2] for statement "UPDATE users SET name = $1 WHERE id = $2", expected ["curly",2]`)
+}
@@ -1,8 +1,29 @@
 package sqrl
 
+import (
+	"context"
+	"fmt"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
 // StatementBuilderType is the type of StatementBuilder.
 type StatementBuilderType struct {
 	placeholderFormat PlaceholderFormat
+	runWith           BaseRunner
+	runWithPool       instapgxpool.Pool
+	dryRun            dryRunMode
+	simpleProtocol    bool
+	planSampler       PlanSampler
+	defaultOrderBys   []string
+	schema            *Schema
+	strict            bool
+	whereConflicts    *whereConflictDetector
+	auditColumns      bool
+	propagateDeadline bool
+	omitErrorArgs     bool
+	metrics           MetricsCollector
 }
 
 // Select returns a SelectBuilder for this StatementBuilder.
@@ -10,6 +31,12 @@ func (b StatementBuilderType) Select(columns ...string) *SelectBuilder {
 	return NewSelectBuilder(b).Columns(columns...)
 }
 
+// SelectExpr returns a SelectBuilder for this StatementBuilder with result
+// columns built by Sqlizers. See the package level SelectExpr.
+func (b StatementBuilderType) SelectExpr(columns ...Sqlizer) *SelectBuilder {
+	return NewSelectBuilder(b).ColumnExprs(columns...)
+}
+
 // Insert returns a InsertBuilder for this StatementBuilder.
 func (b StatementBuilderType) Insert(into string) *InsertBuilder {
 	return NewInsertBuilder(b).Into(into)
@@ -25,12 +52,196 @@ func (b StatementBuilderType) Delete(what ...string) *DeleteBuilder {
 	return NewDeleteBuilder(b).What(what...)
 }
 
+// CreateTable returns a CreateTableBuilder for this StatementBuilder.
+func (b StatementBuilderType) CreateTable(table string) *CreateTableBuilder {
+	return NewCreateTableBuilder(b).Table(table)
+}
+
+// AlterTable returns a AlterTableBuilder for this StatementBuilder.
+func (b StatementBuilderType) AlterTable(table string) *AlterTableBuilder {
+	return NewAlterTableBuilder(b).Table(table)
+}
+
+// DropTable returns a DropBuilder dropping tables for this StatementBuilder.
+func (b StatementBuilderType) DropTable(names ...string) *DropBuilder {
+	return NewDropBuilder(b, "TABLE").Names(names...)
+}
+
+// DropIndex returns a DropBuilder dropping indexes for this StatementBuilder.
+func (b StatementBuilderType) DropIndex(names ...string) *DropBuilder {
+	return NewDropBuilder(b, "INDEX").Names(names...)
+}
+
+// DropView returns a DropBuilder dropping views for this StatementBuilder.
+func (b StatementBuilderType) DropView(names ...string) *DropBuilder {
+	return NewDropBuilder(b, "VIEW").Names(names...)
+}
+
+// CreateMaterializedView returns a CreateMaterializedViewBuilder for this StatementBuilder.
+func (b StatementBuilderType) CreateMaterializedView(name string, query *SelectBuilder) *CreateMaterializedViewBuilder {
+	return NewCreateMaterializedViewBuilder(b).Name(name).As(query)
+}
+
+// RefreshMaterializedView returns a RefreshMaterializedViewBuilder for this StatementBuilder.
+func (b StatementBuilderType) RefreshMaterializedView(name string) *RefreshMaterializedViewBuilder {
+	return NewRefreshMaterializedViewBuilder(b).Name(name)
+}
+
+// CreatePolicy returns a CreatePolicyBuilder for this StatementBuilder.
+func (b StatementBuilderType) CreatePolicy(name string) *CreatePolicyBuilder {
+	return NewCreatePolicyBuilder(b).Name(name)
+}
+
+// AlterPolicy returns a AlterPolicyBuilder for this StatementBuilder.
+func (b StatementBuilderType) AlterPolicy(name string) *AlterPolicyBuilder {
+	return NewAlterPolicyBuilder(b).Name(name)
+}
+
+// DropPolicy returns a DropPolicyBuilder for this StatementBuilder.
+func (b StatementBuilderType) DropPolicy(name string) *DropPolicyBuilder {
+	return NewDropPolicyBuilder(b).Name(name)
+}
+
+// SetConstraints returns a SetConstraintsBuilder for this StatementBuilder.
+func (b StatementBuilderType) SetConstraints(mode ConstraintMode, names ...string) *SetConstraintsBuilder {
+	return NewSetConstraintsBuilder(b).Mode(mode).Names(names...)
+}
+
+// PrepareTransaction returns a TwoPhaseBuilder preparing the current
+// transaction for this StatementBuilder.
+func (b StatementBuilderType) PrepareTransaction(id string) *TwoPhaseBuilder {
+	return NewTwoPhaseBuilder(b, "PREPARE TRANSACTION").ID(id)
+}
+
+// CommitPrepared returns a TwoPhaseBuilder committing a prepared
+// transaction for this StatementBuilder.
+func (b StatementBuilderType) CommitPrepared(id string) *TwoPhaseBuilder {
+	return NewTwoPhaseBuilder(b, "COMMIT PREPARED").ID(id)
+}
+
+// RollbackPrepared returns a TwoPhaseBuilder rolling back a prepared
+// transaction for this StatementBuilder.
+func (b StatementBuilderType) RollbackPrepared(id string) *TwoPhaseBuilder {
+	return NewTwoPhaseBuilder(b, "ROLLBACK PREPARED").ID(id)
+}
+
+// Vacuum returns a VacuumBuilder for this StatementBuilder.
+func (b StatementBuilderType) Vacuum(tables ...string) *VacuumBuilder {
+	return NewVacuumBuilder(b).Tables(tables...)
+}
+
+// Analyze returns a AnalyzeBuilder for this StatementBuilder.
+func (b StatementBuilderType) Analyze(table string, columns ...string) *AnalyzeBuilder {
+	return NewAnalyzeBuilder(b).Tables(table).Columns(columns...)
+}
+
+// ReindexIndex returns a ReindexBuilder for this StatementBuilder.
+func (b StatementBuilderType) ReindexIndex(name string) *ReindexBuilder {
+	return NewReindexBuilder(b, "INDEX").Name(name)
+}
+
+// ReindexTable returns a ReindexBuilder for this StatementBuilder.
+func (b StatementBuilderType) ReindexTable(name string) *ReindexBuilder {
+	return NewReindexBuilder(b, "TABLE").Name(name)
+}
+
+// ReindexSchema returns a ReindexBuilder for this StatementBuilder.
+func (b StatementBuilderType) ReindexSchema(name string) *ReindexBuilder {
+	return NewReindexBuilder(b, "SCHEMA").Name(name)
+}
+
+// Cluster returns a ClusterBuilder for this StatementBuilder.
+func (b StatementBuilderType) Cluster(table, index string) *ClusterBuilder {
+	return NewClusterBuilder(b).Table(table).Using(index)
+}
+
 // PlaceholderFormat sets the PlaceholderFormat field for any child builders.
 func (b StatementBuilderType) PlaceholderFormat(f PlaceholderFormat) StatementBuilderType {
 	b.placeholderFormat = f
 	return b
 }
 
+// DefaultOrderBy sets ORDER BY expressions for child SelectBuilders which
+// have no ORDER BY of their own, e.g. to make paginated list queries
+// deterministic by policy. Queries with GROUP BY or DISTINCT, selects of
+// aggregates only, counts built with Count and selects used inside another
+// statement, e.g. subqueries and the parts of a UNION, are left unordered.
+func (b StatementBuilderType) DefaultOrderBy(orderBys ...string) StatementBuilderType {
+	b.defaultOrderBys = append([]string(nil), orderBys...)
+	return b
+}
+
+// RunWithPool sets the pool used by ExecContext, QueryContext and
+// QueryRowContext of child builders when none is passed explicitly.
+func (b StatementBuilderType) RunWithPool(pool instapgxpool.Pool) StatementBuilderType {
+	b.runWithPool = pool
+	return b
+}
+
+// ErrPoolNotSet is returned by methods that need a pool if neither one is
+// passed nor set with RunWithPool.
+var ErrPoolNotSet = fmt.Errorf("cannot run; no pool passed or set (RunWithPool)")
+
+// resolvePool returns pool, or the one set with RunWithPool if pool is nil.
+func (b StatementBuilderType) resolvePool(pool instapgxpool.Pool) (instapgxpool.Pool, error) {
+	switch {
+	case pool != nil:
+		return pool, nil
+	case b.runWithPool != nil:
+		return b.runWithPool, nil
+	}
+	return nil, ErrPoolNotSet
+}
+
+// runOptions returns the StatementBuilderType s is run with by the execution
+// helpers: that of the builder s, or the one carried by ctx for other
+// Sqlizers, see FromContext.
+func runOptions(ctx context.Context, s Sqlizer) StatementBuilderType {
+	if q, ok := s.(simpleProtocolQuery); ok {
+		s = q.s
+	}
+	if d, ok := s.(dryRunStatement); ok {
+		return d.statementBuilder()
+	}
+	return FromContext(ctx)
+}
+
+func (b StatementBuilderType) execContext(ctx context.Context, pool instapgxpool.Pool, s Sqlizer) (pgconn.CommandTag, error) {
+	if b.dryRun != dryRunOff {
+		return nil, b.dryRunResult(ctx, pool, s)
+	}
+	pool, err := b.resolvePool(pool)
+	if err != nil {
+		return nil, err
+	}
+	b.capturePlan(ctx, pool, s)
+	return ExecWithContext(ctx, pool, b.withProtocol(s, false))
+}
+
+func (b StatementBuilderType) queryContext(ctx context.Context, pool instapgxpool.Pool, s Sqlizer) (pgx.Rows, error) {
+	if b.dryRun != dryRunOff {
+		return nil, b.dryRunResult(ctx, pool, s)
+	}
+	pool, err := b.resolvePool(pool)
+	if err != nil {
+		return nil, err
+	}
+	b.capturePlan(ctx, pool, s)
+	return QueryWithContext(ctx, pool, b.withProtocol(s, true))
+}
+
+func (b StatementBuilderType) queryRowContext(ctx context.Context, pool instapgxpool.Pool, s Sqlizer) RowScanner {
+	if b.dryRun != dryRunOff {
+		return &Row{err: b.dryRunResult(ctx, pool, s)}
+	}
+	pool, err := b.resolvePool(pool)
+	if err != nil {
+		return &Row{err: err}
+	}
+	b.capturePlan(ctx, pool, s)
+	return QueryRowWithContext(ctx, pool, b.withProtocol(s, true))
+}
+
 // StatementBuilder is a basic statement builder, holds global configuration options
 // like placeholder format or SQL runner
 var StatementBuilder = StatementBuilderType{placeholderFormat: Question}
@@ -42,6 +253,19 @@ func Select(columns ...string) *SelectBuilder {
 	return StatementBuilder.Select(columns...)
 }
 
+// SelectExpr returns a new SelectBuilder with result columns built by
+// Sqlizers, e.g. for utility statements without FROM clause run through
+// the Query helpers.
+//
+// Ex:
+//     sqrl.SelectExpr(sqrl.Expr("set_config(?, ?, true)", "app.user_id", "42"))
+//     // SELECT set_config(?, ?, true)
+//     sqrl.SelectExpr(sqrl.Expr("?::jsonb", doc))
+//     // SELECT ?::jsonb
+func SelectExpr(columns ...Sqlizer) *SelectBuilder {
+	return StatementBuilder.SelectExpr(columns...)
+}
+
 // Insert returns a new InsertBuilder with the given table name.
 //
 // See InsertBuilder.Into.
@@ -63,6 +287,123 @@ func Delete(what ...string) *DeleteBuilder {
 	return StatementBuilder.Delete(what...)
 }
 
+// CreateTable returns a new CreateTableBuilder with the given table name.
+//
+// See CreateTableBuilder.Table.
+func CreateTable(table string) *CreateTableBuilder {
+	return StatementBuilder.CreateTable(table)
+}
+
+// AlterTable returns a new AlterTableBuilder with the given table name.
+//
+// See AlterTableBuilder.Table.
+func AlterTable(table string) *AlterTableBuilder {
+	return StatementBuilder.AlterTable(table)
+}
+
+// DropTable returns a new DropBuilder dropping the given tables.
+func DropTable(names ...string) *DropBuilder {
+	return StatementBuilder.DropTable(names...)
+}
+
+// DropIndex returns a new DropBuilder dropping the given indexes.
+func DropIndex(names ...string) *DropBuilder {
+	return StatementBuilder.DropIndex(names...)
+}
+
+// DropView returns a new DropBuilder dropping the given views.
+func DropView(names ...string) *DropBuilder {
+	return StatementBuilder.DropView(names...)
+}
+
+// CreateMaterializedView returns a new CreateMaterializedViewBuilder defining
+// the materialized view name by query.
+func CreateMaterializedView(name string, query *SelectBuilder) *CreateMaterializedViewBuilder {
+	return StatementBuilder.CreateMaterializedView(name, query)
+}
+
+// RefreshMaterializedView returns a new RefreshMaterializedViewBuilder for the
+// given materialized view.
+func RefreshMaterializedView(name string) *RefreshMaterializedViewBuilder {
+	return StatementBuilder.RefreshMaterializedView(name)
+}
+
+// CreatePolicy returns a new CreatePolicyBuilder with the given policy name.
+//
+// See CreatePolicyBuilder.On.
+func CreatePolicy(name string) *CreatePolicyBuilder {
+	return StatementBuilder.CreatePolicy(name)
+}
+
+// AlterPolicy returns a new AlterPolicyBuilder with the given policy name.
+func AlterPolicy(name string) *AlterPolicyBuilder {
+	return StatementBuilder.AlterPolicy(name)
+}
+
+// DropPolicy returns a new DropPolicyBuilder with the given policy name.
+func DropPolicy(name string) *DropPolicyBuilder {
+	return StatementBuilder.DropPolicy(name)
+}
+
+// SetConstraints returns a new SetConstraintsBuilder switching the given
+// constraints, or all deferrable constraints if none are given, to mode.
+func SetConstraints(mode ConstraintMode, names ...string) *SetConstraintsBuilder {
+	return StatementBuilder.SetConstraints(mode, names...)
+}
+
+// PrepareTransaction returns a new TwoPhaseBuilder preparing the current
+// transaction for two-phase commit under id.
+func PrepareTransaction(id string) *TwoPhaseBuilder {
+	return StatementBuilder.PrepareTransaction(id)
+}
+
+// CommitPrepared returns a new TwoPhaseBuilder committing the transaction
+// prepared under id.
+func CommitPrepared(id string) *TwoPhaseBuilder {
+	return StatementBuilder.CommitPrepared(id)
+}
+
+// RollbackPrepared returns a new TwoPhaseBuilder rolling back the
+// transaction prepared under id.
+func RollbackPrepared(id string) *TwoPhaseBuilder {
+	return StatementBuilder.RollbackPrepared(id)
+}
+
+// Vacuum returns a new VacuumBuilder vacuuming the given tables, or the
+// whole database if none are given.
+func Vacuum(tables ...string) *VacuumBuilder {
+	return StatementBuilder.Vacuum(tables...)
+}
+
+// Analyze returns a new AnalyzeBuilder collecting statistics for the given
+// columns of table, or all of its columns if none are given.
+func Analyze(table string, columns ...string) *AnalyzeBuilder {
+	return StatementBuilder.Analyze(table, columns...)
+}
+
+// ReindexIndex returns a new ReindexBuilder rebuilding the given index.
+func ReindexIndex(name string) *ReindexBuilder {
+	return StatementBuilder.ReindexIndex(name)
+}
+
+// ReindexTable returns a new ReindexBuilder rebuilding all indexes of the
+// given table.
+func ReindexTable(name string) *ReindexBuilder {
+	return StatementBuilder.ReindexTable(name)
+}
+
+// ReindexSchema returns a new ReindexBuilder rebuilding all indexes of the
+// given schema.
+func ReindexSchema(name string) *ReindexBuilder {
+	return StatementBuilder.ReindexSchema(name)
+}
+
+// Cluster returns a new ClusterBuilder physically reordering table by
+// index. Both may be empty, see ClusterBuilder.Table and ClusterBuilder.Using.
+func Cluster(table, index string) *ClusterBuilder {
+	return StatementBuilder.Cluster(table, index)
+}
+
 // Case returns a new CaseBuilder
 // "what" represents case value
 func Case(what ...interface{}) *CaseBuilder {
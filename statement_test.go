@@ -1,9 +1,13 @@
 package sqrl
 
 import (
+	"context"
 	"database/sql"
 	"testing"
 
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -15,6 +19,23 @@ func TestStatementBuilder(t *testing.T) {
 	assert.Equal(t, "SELECT test", db.LastExecSql)
 }
 
+func TestBuilderValueRunners(t *testing.T) {
+	type execer interface {
+		Exec() (sql.Result, error)
+	}
+	db := &DBStub{}
+	for _, e := range []execer{
+		*Select("test").RunWith(db),
+		*Insert("t").Values(1).RunWith(db),
+		*Update("t").Set("a", 1).RunWith(db),
+		*Delete("t").RunWith(db),
+	} {
+		_, err := e.Exec()
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, "DELETE FROM t", db.LastExecSql)
+}
+
 func TestStatementBuilderPlaceholderFormat(t *testing.T) {
 	db := &DBStub{}
 	sb := StatementBuilder.RunWith(db).PlaceholderFormat(Dollar)
@@ -43,3 +64,54 @@ func TestRunWithTx(t *testing.T) {
 		Delete("t").RunWith(tx)
 	}, "RunWith(*sql.Tx) should not panic")
 }
+
+func TestStatementBuilderRunWithPool(t *testing.T) {
+	pool := newPoolStub()
+	sb := StatementBuilder.PlaceholderFormat(Dollar).RunWithPool(pool)
+
+	_, err := sb.Update("a").Set("b", 1).ExecAttached(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE a SET b = $1", pool.stub.sqls[0])
+
+	_, err = sb.Select("test").Where("x = ?", 1).QueryAttached(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT test WHERE x = $1", pool.stub.sqls[1])
+
+	other := newPoolStub()
+	_, err = sb.Delete("a").ExecContext(context.Background(), other)
+	assert.NoError(t, err)
+	assert.Len(t, pool.stub.sqls, 2)
+	assert.Equal(t, "DELETE FROM a", other.stub.sqls[0])
+}
+
+func TestBuilderRunWithPool(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{42}}}
+
+	var n int
+	err := Select("n").From("t").RunWithPool(pool).ScanContext(context.Background(), &n)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, n)
+}
+
+func TestBuilderPoolSignatures(t *testing.T) {
+	type runner interface {
+		ExecContext(ctx context.Context, pool instapgxpool.Pool) (pgconn.CommandTag, error)
+		QueryContext(ctx context.Context, pool instapgxpool.Pool) (pgx.Rows, error)
+		QueryRowContext(ctx context.Context, pool instapgxpool.Pool) RowScanner
+	}
+	pool := newPoolStub()
+	for _, r := range []runner{Select("a"), Insert("a").Values(1), Update("a").Set("b", 1), Delete("a")} {
+		_, err := r.ExecContext(context.Background(), pool)
+		assert.NoError(t, err)
+	}
+	assert.Len(t, pool.stub.sqls, 4)
+}
+
+func TestStatementBuilderPoolNotSet(t *testing.T) {
+	_, err := Select("test").ExecAttached(context.Background())
+	assert.Equal(t, ErrPoolNotSet, err)
+
+	err = Insert("a").Values(1).ScanContext(context.Background())
+	assert.Equal(t, ErrPoolNotSet, err)
+}
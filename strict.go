@@ -0,0 +1,100 @@
+package sqrl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Strict makes child builders reject suspicious content in the strings they
+// interpolate verbatim: table names, result columns given as strings,
+// column names, ORDER BY and GROUP BY expressions and options. ToSql fails
+// if such a string contains a semicolon, a comment marker, a single quote,
+// a backslash or an unbalanced double quote, which are typical for SQL
+// injected through identifiers taken from user input, e.g. a sort column.
+//
+// Strings with legitimate literals, e.g. Columns("coalesce(name, '')"),
+// fail as well; use Column with args instead.
+func (b StatementBuilderType) Strict() StatementBuilderType {
+	b.strict = true
+	return b
+}
+
+// strictTokens are rejected in verbatim positions in strict mode.
+var strictTokens = []string{";", "--", "/*", "*/", "'", `\`}
+
+// checkStrictStrings returns an error if one of values contains suspicious
+// content, naming clause in the error.
+func checkStrictStrings(clause string, values ...string) error {
+	for _, value := range values {
+		for _, token := range strictTokens {
+			if strings.Contains(value, token) {
+				return fmt.Errorf("strict mode: suspicious %q in %s %q", token, clause, value)
+			}
+		}
+		if strings.Count(value, `"`)%2 != 0 {
+			return fmt.Errorf("strict mode: unbalanced %q in %s %q", `"`, clause, value)
+		}
+	}
+	return nil
+}
+
+// checkStrictParts is like checkStrictStrings for parts given as strings.
+// Parts given as Sqlizers are not checked.
+func checkStrictParts(clause string, parts []Sqlizer) error {
+	for _, p := range parts {
+		if p, ok := p.(*part); ok {
+			if s, ok := p.pred.(string); ok {
+				if err := checkStrictStrings(clause, s); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkStrict checks the verbatim positions of a SelectBuilder,
+// InsertBuilder, UpdateBuilder or DeleteBuilder.
+func checkStrict(s Sqlizer) error {
+	var checks []error
+	switch b := s.(type) {
+	case *SelectBuilder:
+		checks = []error{
+			checkStrictStrings("options", b.options...),
+			checkStrictParts("columns", b.columns),
+			checkStrictParts("FROM", b.fromParts),
+			checkStrictStrings("GROUP BY", b.groupBys...),
+			checkStrictStrings("ORDER BY", b.orderBys...),
+		}
+	case *InsertBuilder:
+		checks = []error{
+			checkStrictStrings("options", b.options...),
+			checkStrictStrings("INTO", b.into),
+			checkStrictStrings("columns", b.columns...),
+		}
+	case *UpdateBuilder:
+		columns := make([]string, len(b.setClauses))
+		for i, set := range b.setClauses {
+			columns[i] = set.column
+		}
+		checks = []error{
+			checkStrictStrings("table", b.table),
+			checkStrictStrings("SET", columns...),
+			checkStrictParts("FROM", b.fromParts),
+			checkStrictStrings("ORDER BY", b.orderBys...),
+		}
+	case *DeleteBuilder:
+		checks = []error{
+			checkStrictStrings("columns", b.what...),
+			checkStrictStrings("FROM", b.from),
+			checkStrictParts("USING", b.usingParts),
+			checkStrictStrings("ORDER BY", b.orderBys...),
+		}
+	}
+	for _, err := range checks {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrictSelect(t *testing.T) {
+	sb := StatementBuilder.Strict()
+
+	sql, _, err := sb.Select("id", `"user".name`).
+		Column("coalesce(nick, ?)", "").
+		From(`"user"`).
+		Where("name = 'moe'").
+		GroupBy("id").
+		OrderBy("name DESC").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT id, "user".name, coalesce(nick, ?) FROM "user" WHERE name = 'moe' GROUP BY id ORDER BY name DESC`, sql)
+
+	_, _, err = sb.Select("id").From("users").OrderBy("name; DROP TABLE users").ToSql()
+	assert.EqualError(t, err, `strict mode: suspicious ";" in ORDER BY "name; DROP TABLE users"`)
+
+	_, _, err = sb.Select("id").From("users").GroupBy("name--").ToSql()
+	assert.EqualError(t, err, `strict mode: suspicious "--" in GROUP BY "name--"`)
+
+	_, _, err = sb.Select("id, 'x'").From("users").ToSql()
+	assert.EqualError(t, err, `strict mode: suspicious "'" in columns "id, 'x'"`)
+
+	_, _, err = sb.Select("id").From(`users" /* x`).ToSql()
+	assert.EqualError(t, err, `strict mode: suspicious "/*" in FROM "users\" /* x"`)
+
+	_, _, err = sb.Select("id").From(`"users`).ToSql()
+	assert.EqualError(t, err, `strict mode: unbalanced "\"" in FROM "\"users"`)
+
+	_, _, err = Select("id").From("users").OrderBy("name; DROP TABLE users").Strict().ToSql()
+	assert.Error(t, err)
+}
+
+func TestStrictInsertUpdateDelete(t *testing.T) {
+	sb := StatementBuilder.Strict()
+
+	_, _, err := sb.Insert("users").Columns("name").Values("'; DROP TABLE users --").ToSql()
+	assert.NoError(t, err)
+
+	_, _, err = sb.Insert("users").Columns(`name) VALUES ('x')--`).Values(1).ToSql()
+	assert.EqualError(t, err, `strict mode: suspicious "--" in columns "name) VALUES ('x')--"`)
+
+	_, _, err = sb.Update("users").Set("name = 'x' --", 1).ToSql()
+	assert.EqualError(t, err, `strict mode: suspicious "--" in SET "name = 'x' --"`)
+
+	_, _, err = sb.Delete("users").Where("id = ?", 1).OrderBy(`id\`).ToSql()
+	assert.EqualError(t, err, `strict mode: suspicious "\\" in ORDER BY "id\\"`)
+}
+
+func TestStrictNotSet(t *testing.T) {
+	_, _, err := Select("id").From("users").OrderBy("name; DROP TABLE users").ToSql()
+	assert.NoError(t, err)
+}
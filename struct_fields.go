@@ -0,0 +1,58 @@
+package sqrl
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// StructField is a db tagged field of a struct.
+type StructField struct {
+	// Column is the column name of the db tag.
+	Column string
+	// Index is the index of the field in its struct.
+	Index int
+	// OmitEmpty is set for fields tagged omitempty.
+	OmitEmpty bool
+}
+
+// structFieldsCache maps struct types to their []StructField.
+var structFieldsCache sync.Map
+
+// StructFields returns the db tagged fields of typ, which must be a struct
+// type. Fields without a db tag or tagged "-" and unexported fields are
+// skipped. The fields are reflected once per type, so the returned slice is
+// shared and must not be modified.
+//
+// Ex:
+//     type User struct {
+//         ID   int64  `db:"id,omitempty"`
+//         Name string `db:"name"`
+//     }
+//
+//     StructFields(reflect.TypeOf(User{}))
+//     == []StructField{{"id", 0, true}, {"name", 1, false}}
+func StructFields(typ reflect.Type) []StructField {
+	if fields, ok := structFieldsCache.Load(typ); ok {
+		return fields.([]StructField)
+	}
+
+	var fields []StructField
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := strings.Split(field.Tag.Get("db"), ",")
+		if tag[0] == "" || tag[0] == "-" {
+			continue
+		}
+		omitEmpty := false
+		for _, option := range tag[1:] {
+			omitEmpty = omitEmpty || option == "omitempty"
+		}
+		fields = append(fields, StructField{Column: tag[0], Index: i, OmitEmpty: omitEmpty})
+	}
+	structFieldsCache.Store(typ, fields)
+	return fields
+}
@@ -0,0 +1,28 @@
+package sqrl
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructFields(t *testing.T) {
+	type user struct {
+		ID       int64  `db:"id,omitempty"`
+		Name     string `db:"name"`
+		Ignored  string `db:"-"`
+		Untagged string
+		secret   string `db:"secret"`
+		Email    string `db:"email,readonly,omitempty"`
+	}
+
+	fields := StructFields(reflect.TypeOf(user{}))
+	assert.Equal(t, []StructField{
+		{Column: "id", Index: 0, OmitEmpty: true},
+		{Column: "name", Index: 1},
+		{Column: "email", Index: 5, OmitEmpty: true},
+	}, fields)
+
+	assert.Equal(t, fields, StructFields(reflect.TypeOf(user{})))
+}
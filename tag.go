@@ -0,0 +1,108 @@
+package sqrl
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// QueryTags describe the origin of queries, e.g. service, handler and
+// request ID, so they can be attributed in pg_stat_activity and the logs.
+type QueryTags map[string]string
+
+type queryTagsKey struct{}
+
+// WithQueryTags returns a copy of ctx carrying tags, merged with the tags
+// ctx already carries. Queries run through the execution helpers with the
+// returned context get the tags appended as a trailing comment in the
+// sqlcommenter format:
+//     SELECT * FROM users /*handler='list_users',service='accounts'*/
+//
+// Tags with an empty value are removed.
+func WithQueryTags(ctx context.Context, tags QueryTags) context.Context {
+	merged := make(QueryTags, len(tags))
+	for key, value := range QueryTagsFromContext(ctx) {
+		merged[key] = value
+	}
+	for key, value := range tags {
+		if len(value) == 0 {
+			delete(merged, key)
+			continue
+		}
+		merged[key] = value
+	}
+	return context.WithValue(ctx, queryTagsKey{}, merged)
+}
+
+// QueryTagsFromContext returns the tags set with WithQueryTags.
+func QueryTagsFromContext(ctx context.Context) QueryTags {
+	tags, _ := ctx.Value(queryTagsKey{}).(QueryTags)
+	return tags
+}
+
+func (t QueryTags) keys() []string {
+	keys := make([]string, 0, len(t))
+	for key := range t {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Comment returns the tags as SQL comment. Keys and values are URL encoded,
+// so the comment can neither be terminated early nor contain placeholders.
+func (t QueryTags) Comment() string {
+	if len(t) == 0 {
+		return ""
+	}
+
+	parts := make([]string, 0, len(t))
+	for _, key := range t.keys() {
+		parts = append(parts, fmt.Sprintf("%s='%s'", escapeTag(key), escapeTag(t[key])))
+	}
+	return "/*" + strings.Join(parts, ",") + "*/"
+}
+
+// escapeTag URL encodes s, including $ so pgx's simple protocol cannot
+// mistake it for a positional parameter.
+func escapeTag(s string) string {
+	return strings.Replace(url.PathEscape(s), "$", "%24", -1)
+}
+
+// ApplicationName returns the tags as value for application_name, which
+// Postgres truncates to 63 bytes.
+func (t QueryTags) ApplicationName() string {
+	parts := make([]string, 0, len(t))
+	for _, key := range t.keys() {
+		parts = append(parts, key+"="+t[key])
+	}
+	name := strings.Join(parts, ",")
+	if len(name) > 63 {
+		name = name[:63]
+	}
+	return name
+}
+
+// SetApplicationName sets application_name to the tags of ctx for the rest
+// of tx. application_name cannot be set per query on a shared pool, as it
+// would stick to the pooled connection; SET LOCAL semantics scope it to tx.
+func SetApplicationName(ctx context.Context, tx pgx.Tx) error {
+	tags := QueryTagsFromContext(ctx)
+	if len(tags) == 0 {
+		return nil
+	}
+	_, err := tx.Exec(ctx, "SELECT set_config('application_name', $1, true)", tags.ApplicationName())
+	return err
+}
+
+// tagQuery appends the query tags of ctx to query.
+func tagQuery(ctx context.Context, query string) string {
+	comment := QueryTagsFromContext(ctx).Comment()
+	if len(comment) == 0 {
+		return query
+	}
+	return query + " " + comment
+}
@@ -0,0 +1,62 @@
+package sqrl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryTagsComment(t *testing.T) {
+	tags := QueryTags{"service": "accounts", "handler": "list users", "request_id": "*/ DROP TABLE x; /*?$1"}
+	assert.Equal(t, "/*handler='list%20users',request_id='%2A%2F%20DROP%20TABLE%20x%3B%20%2F%2A%3F%241',service='accounts'*/", tags.Comment())
+	assert.Equal(t, "", QueryTags{}.Comment())
+}
+
+func TestQueryTagsApplicationName(t *testing.T) {
+	assert.Equal(t, "handler=list,service=accounts", QueryTags{"service": "accounts", "handler": "list"}.ApplicationName())
+	assert.Len(t, QueryTags{"request_id": string(make([]byte, 100))}.ApplicationName(), 63)
+}
+
+func TestWithQueryTags(t *testing.T) {
+	ctx := WithQueryTags(context.Background(), QueryTags{"service": "accounts", "handler": "list"})
+	ctx = WithQueryTags(ctx, QueryTags{"request_id": "r1", "handler": ""})
+	assert.Equal(t, QueryTags{"service": "accounts", "request_id": "r1"}, QueryTagsFromContext(ctx))
+	assert.Nil(t, QueryTagsFromContext(context.Background()))
+}
+
+func TestQueryTagsExecution(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{1}}, {{2}}}
+	ctx := WithQueryTags(context.Background(), QueryTags{"service": "accounts"})
+
+	_, err := Update("users").Set("a", 1).ExecContext(ctx, pool)
+	assert.NoError(t, err)
+	rows, err := Select("id").From("users").QueryContext(ctx, pool)
+	assert.NoError(t, err)
+	rows.Close()
+	var id int
+	assert.NoError(t, Select("id").From("users").Scan(ctx, pool, &id))
+	_, err = Update("users").Set("a", 1).ExecContext(context.Background(), pool)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"UPDATE users SET a = ? /*service='accounts'*/",
+		"SELECT id FROM users /*service='accounts'*/",
+		"SELECT id FROM users /*service='accounts'*/",
+		"UPDATE users SET a = ?",
+	}, pool.stub.sqls)
+}
+
+func TestSetApplicationName(t *testing.T) {
+	pool := newPoolStub()
+	tx, _ := pool.Begin(context.Background())
+
+	assert.NoError(t, SetApplicationName(context.Background(), tx))
+	assert.Empty(t, pool.stub.sqls)
+
+	ctx := WithQueryTags(context.Background(), QueryTags{"service": "accounts"})
+	assert.NoError(t, SetApplicationName(ctx, tx))
+	assert.Equal(t, []string{"SELECT set_config('application_name', $1, true)"}, pool.stub.sqls)
+	assert.Equal(t, [][]interface{}{{"service=accounts"}}, pool.stub.args)
+}
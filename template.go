@@ -0,0 +1,170 @@
+package sqrl
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Param is a named parameter of a query template. It is used in place of a
+// value anywhere a builder accepts one and bound when the template is
+// instantiated with Bind.
+//
+// Params are bound as single values, so use "= ANY(?)" instead of Eq to
+// match a list.
+//
+// Ex:
+//     Select("*").From("users").Where(Eq{"id": Param("id")})
+type Param string
+
+// Template is a statement registered in a TemplateRegistry. Its SQL is
+// built once at registration, so it can be reviewed and cached.
+type Template struct {
+	// Name is the name the template is registered under.
+	Name string
+	// SQL is the SQL of the statement, built with its PlaceholderFormat.
+	SQL string
+	// Params are the declared parameters of the template.
+	Params []string
+
+	s    Sqlizer
+	args []interface{}
+}
+
+// Bind returns the statement with its parameters set to params. Every
+// declared parameter must be set and no other.
+func (t *Template) Bind(params map[string]interface{}) (Sqlizer, error) {
+	for name := range params {
+		if !t.hasParam(name) {
+			return nil, fmt.Errorf("unknown parameter %q for query %q", name, t.Name)
+		}
+	}
+
+	args := make([]interface{}, len(t.args))
+	for i, arg := range t.args {
+		p, ok := arg.(Param)
+		if !ok {
+			args[i] = arg
+			continue
+		}
+		v, ok := params[string(p)]
+		if !ok {
+			return nil, fmt.Errorf("missing parameter %q for query %q", p, t.Name)
+		}
+		args[i] = v
+	}
+	return boundTemplate{t: t, args: args}, nil
+}
+
+func (t *Template) hasParam(name string) bool {
+	for _, p := range t.Params {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// boundTemplate is a Template with its parameters bound.
+type boundTemplate struct {
+	t    *Template
+	args []interface{}
+}
+
+// ToSql returns the SQL of the template and the bound args.
+func (b boundTemplate) ToSql() (string, []interface{}, error) {
+	return b.t.SQL, b.args, nil
+}
+
+// TemplateRegistry holds named query templates. Templates are usually
+// registered at startup and bound per request, so all SQL a service can
+// emit is known up front.
+//
+// Ex:
+//     templates := NewTemplateRegistry()
+//     templates.MustRegister("user_by_id",
+//         Select("*").From("users").Where(Eq{"id": Param("id")}), "id")
+//     ...
+//     q, err := templates.Bind("user_by_id", map[string]interface{}{"id": id})
+//     rows, err := QueryWithContext(ctx, pool, q)
+type TemplateRegistry struct {
+	mu        sync.RWMutex
+	templates map[string]*Template
+}
+
+// NewTemplateRegistry creates an empty TemplateRegistry.
+func NewTemplateRegistry() *TemplateRegistry {
+	return &TemplateRegistry{templates: map[string]*Template{}}
+}
+
+// Register builds s and registers it as template name. params declares
+// the parameters of the template; every Param used in s must be declared
+// and every declared parameter must be used.
+func (r *TemplateRegistry) Register(name string, s Sqlizer, params ...string) error {
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return newBuildError(s, sql, err)
+	}
+
+	t := &Template{Name: name, SQL: sql, Params: params, s: s, args: args}
+	used := map[string]bool{}
+	for _, arg := range args {
+		if p, ok := arg.(Param); ok {
+			if !t.hasParam(string(p)) {
+				return fmt.Errorf("query %q uses undeclared parameter %q", name, p)
+			}
+			used[string(p)] = true
+		}
+	}
+	for _, p := range params {
+		if !used[p] {
+			return fmt.Errorf("query %q does not use parameter %q", name, p)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.templates[name]; ok {
+		return fmt.Errorf("query %q is already registered", name)
+	}
+	r.templates[name] = t
+	return nil
+}
+
+// MustRegister is like Register but panics on error.
+func (r *TemplateRegistry) MustRegister(name string, s Sqlizer, params ...string) {
+	if err := r.Register(name, s, params...); err != nil {
+		panic(err)
+	}
+}
+
+// Template returns the template registered as name, or nil.
+func (r *TemplateRegistry) Template(name string) *Template {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.templates[name]
+}
+
+// Templates returns all registered templates sorted by name.
+func (r *TemplateRegistry) Templates() []*Template {
+	r.mu.RLock()
+	templates := make([]*Template, 0, len(r.templates))
+	for _, t := range r.templates {
+		templates = append(templates, t)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates
+}
+
+// Bind binds params to the template registered as name.
+func (r *TemplateRegistry) Bind(name string, params map[string]interface{}) (Sqlizer, error) {
+	t := r.Template(name)
+	if t == nil {
+		return nil, fmt.Errorf("query %q is not registered", name)
+	}
+	return t.Bind(params)
+}
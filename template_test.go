@@ -0,0 +1,95 @@
+package sqrl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateRegistry(t *testing.T) {
+	r := NewTemplateRegistry()
+	r.MustRegister("user_by_id",
+		StatementBuilder.PlaceholderFormat(Dollar).Select("*").From("users").
+			Where(Eq{"id": Param("id")}).
+			Where("deleted = ?", false).
+			Where("team_id = ?", Param("team")),
+		"id", "team")
+
+	tmpl := r.Template("user_by_id")
+	assert.Equal(t, "SELECT * FROM users WHERE id = $1 AND deleted = $2 AND team_id = $3", tmpl.SQL)
+
+	q, err := r.Bind("user_by_id", map[string]interface{}{"id": 1, "team": 2})
+	assert.NoError(t, err)
+	sql, args, err := q.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, tmpl.SQL, sql)
+	assert.Equal(t, []interface{}{1, false, 2}, args)
+
+	assert.Equal(t, KindSelect, Kind(q))
+	assert.Equal(t, []string{"users"}, Tables(q))
+
+	// Bound args are independent between instantiations.
+	q2, err := r.Bind("user_by_id", map[string]interface{}{"id": 3, "team": 4})
+	assert.NoError(t, err)
+	_, args, _ = q2.ToSql()
+	assert.Equal(t, []interface{}{3, false, 4}, args)
+}
+
+func TestTemplateRegistryBindErrors(t *testing.T) {
+	r := NewTemplateRegistry()
+	r.MustRegister("user_by_id", Select("*").From("users").Where(Eq{"id": Param("id")}), "id")
+
+	_, err := r.Bind("user_by_id", map[string]interface{}{})
+	assert.EqualError(t, err, `missing parameter "id" for query "user_by_id"`)
+
+	_, err = r.Bind("user_by_id", map[string]interface{}{"id": 1, "name": "a"})
+	assert.EqualError(t, err, `unknown parameter "name" for query "user_by_id"`)
+
+	_, err = r.Bind("missing", nil)
+	assert.EqualError(t, err, `query "missing" is not registered`)
+}
+
+func TestTemplateRegistryRegisterErrors(t *testing.T) {
+	r := NewTemplateRegistry()
+
+	err := r.Register("a", Select("*").From("users").Where(Eq{"id": Param("id")}))
+	assert.EqualError(t, err, `query "a" uses undeclared parameter "id"`)
+
+	err = r.Register("a", Select("*").From("users"), "id")
+	assert.EqualError(t, err, `query "a" does not use parameter "id"`)
+
+	err = r.Register("a", Select())
+	assert.Error(t, err)
+
+	assert.NoError(t, r.Register("a", Select("*").From("users")))
+	err = r.Register("a", Select("*").From("users"))
+	assert.EqualError(t, err, `query "a" is already registered`)
+}
+
+func TestTemplateRegistryTemplates(t *testing.T) {
+	r := NewTemplateRegistry()
+	r.MustRegister("b", Select("*").From("b"))
+	r.MustRegister("a", Delete("a").Where(Eq{"id": Param("id")}), "id")
+
+	templates := r.Templates()
+	assert.Len(t, templates, 2)
+	assert.Equal(t, "a", templates[0].Name)
+	assert.Equal(t, "b", templates[1].Name)
+	assert.Panics(t, func() { r.MustRegister("a", Select("*").From("a")) })
+}
+
+func TestTemplateExec(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.tag = nil
+
+	r := NewTemplateRegistry()
+	r.MustRegister("delete_user", Delete("users").Where(Eq{"id": Param("id")}), "id")
+
+	q, err := r.Bind("delete_user", map[string]interface{}{"id": 7})
+	assert.NoError(t, err)
+	_, err = ExecWithContext(context.Background(), pool, q)
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users WHERE id = ?", pool.stub.sqls[0])
+	assert.Equal(t, []interface{}{7}, pool.stub.args[0])
+}
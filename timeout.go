@@ -0,0 +1,126 @@
+package sqrl
+
+import (
+	"context"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgx/v4"
+	"strconv"
+	"time"
+)
+
+// PropagateDeadline makes child builders send the deadline of the context
+// passed to ExecContext, QueryContext, QueryRowContext and the methods based
+// on them to the server as statement_timeout. If the context has a
+// deadline, the statement runs in a transaction with SET LOCAL
+// statement_timeout set to the time left, so the server gives up on the
+// statement when the client already has. ExecWithContext, QueryWithContext
+// and QueryRowWithContext run other Sqlizers with the setting of the
+// StatementBuilderType carried by their context, see FromContext.
+//
+// Pools not supporting transactions run statements unchanged, as do
+// statements run in a transaction, e.g. with the pool passed to fn by
+// RunInTx or a context set with ContextWithTx, since the timeout would stay
+// set for the rest of that transaction.
+func (b StatementBuilderType) PropagateDeadline() StatementBuilderType {
+	b.propagateDeadline = true
+	return b
+}
+
+// beginDeadlineTx begins a transaction on pool with statement_timeout set
+// to the time left until the deadline of ctx. It returns a nil transaction
+// if PropagateDeadline is not set, ctx has no deadline, pool already runs
+// statements in a transaction or does not support transactions.
+func (b StatementBuilderType) beginDeadlineTx(ctx context.Context, pool instapgxpool.Pool) (pgx.Tx, error) {
+	if !b.propagateDeadline {
+		return nil, nil
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil, nil
+	}
+	if _, ok := pool.(txBeginner); !ok {
+		return nil, nil
+	}
+	if _, ok := pool.(*txPool); ok {
+		return nil, nil
+	}
+
+	timeout := time.Until(deadline)
+	if timeout <= 0 {
+		return nil, context.DeadlineExceeded
+	}
+	ms := timeout.Milliseconds()
+	if ms < 1 {
+		ms = 1
+	}
+
+	tx, err := beginTx(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = tx.Exec(ctx, "SELECT set_config('statement_timeout', $1, true)", strconv.FormatInt(ms, 10)); err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+	return tx, nil
+}
+
+// finishTx commits tx if err is nil and rolls it back otherwise.
+func finishTx(ctx context.Context, tx pgx.Tx, err error) error {
+	if err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// txRows finishes the transaction of a deadline propagated query once its
+// rows are consumed or closed.
+type txRows struct {
+	pgx.Rows
+	ctx  context.Context
+	tx   pgx.Tx
+	done bool
+	err  error
+}
+
+func (r *txRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.finish()
+	return false
+}
+
+func (r *txRows) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Rows.Err()
+}
+
+func (r *txRows) Close() {
+	r.Rows.Close()
+	r.finish()
+}
+
+func (r *txRows) finish() {
+	if r.done {
+		return
+	}
+	r.done = true
+	r.Rows.Close()
+	r.err = finishTx(r.ctx, r.tx, r.Rows.Err())
+}
+
+// txRow finishes the transaction of a deadline propagated query once its
+// row is scanned.
+type txRow struct {
+	RowScanner
+	ctx context.Context
+	tx  pgx.Tx
+}
+
+func (r *txRow) Scan(dest ...interface{}) error {
+	return finishTx(r.ctx, r.tx, r.RowScanner.Scan(dest...))
+}
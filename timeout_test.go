@@ -0,0 +1,134 @@
+package sqrl
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/stretchr/testify/assert"
+)
+
+var deadlineBuilder = StatementBuilder.PropagateDeadline()
+
+func TestPropagateDeadlineExec(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.tag = nil
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	_, err := deadlineBuilder.Update("users").Set("a", 1).ExecContext(ctx, pool)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"SELECT set_config('statement_timeout', $1, true)",
+		"UPDATE users SET a = ?",
+	}, pool.stub.sqls)
+
+	ms, err := strconv.Atoi(pool.stub.args[0][0].(string))
+	assert.NoError(t, err)
+	assert.True(t, ms > 59000 && ms <= 60000)
+	assert.Equal(t, 1, pool.stub.begun)
+	assert.Equal(t, 1, pool.stub.committed)
+}
+
+func TestPropagateDeadlineQuery(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.tag = nil
+	pool.stub.results = [][][]interface{}{{{1}, {2}}, {{3}}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	ids, err := Pluck[int](ctx, pool, deadlineBuilder.Select("id").From("users"))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, ids)
+	assert.Equal(t, 1, pool.stub.committed)
+
+	var id int
+	assert.NoError(t, deadlineBuilder.Select("id").From("users").Scan(ctx, pool, &id))
+	assert.Equal(t, 3, id)
+	assert.Equal(t, 2, pool.stub.committed)
+
+	assert.Error(t, deadlineBuilder.Select("id").From("users").Scan(ctx, pool, &id))
+	assert.Equal(t, 2, pool.stub.committed)
+	assert.Equal(t, 1, pool.stub.rolledBack)
+}
+
+func TestPropagateDeadlineDisabled(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.tag = nil
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	_, err := Update("users").Set("a", 1).ExecContext(ctx, pool)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"UPDATE users SET a = ?"}, pool.stub.sqls)
+	assert.Equal(t, 0, pool.stub.begun)
+}
+
+func TestPropagateDeadlineNoDeadline(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.tag = nil
+
+	_, err := deadlineBuilder.Update("users").Set("a", 1).ExecContext(context.Background(), pool)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, pool.stub.begun)
+}
+
+func TestPropagateDeadlineInTx(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.tag = nil
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	err := RunInTx(ctx, pool, TxOptions{}, func(pool instapgxpool.Pool) error {
+		_, err := deadlineBuilder.Update("users").Set("a", 1).ExecContext(ctx, pool)
+		return err
+	})
+	assert.NoError(t, err)
+
+	tx, err := pool.Begin(ctx)
+	assert.NoError(t, err)
+	_, err = deadlineBuilder.Update("users").Set("b", 2).ExecContext(ContextWithTx(ctx, tx), pool)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"UPDATE users SET a = ?", "UPDATE users SET b = ?"}, pool.stub.sqls)
+	assert.Equal(t, 2, pool.stub.begun)
+	assert.Equal(t, 1, pool.stub.committed)
+}
+
+func TestPropagateDeadlineExceeded(t *testing.T) {
+	pool := newPoolStub()
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	_, err := deadlineBuilder.Update("users").Set("a", 1).ExecContext(ctx, pool)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Empty(t, pool.stub.sqls)
+}
+
+func TestPropagateDeadlineFromContext(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.tag = nil
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	ctx = WithStatementBuilder(ctx, deadlineBuilder)
+
+	_, err := FromContext(ctx).Update("users").Set("a", 1).ExecContext(ctx, pool)
+	assert.NoError(t, err)
+	_, err = ExecWithContext(ctx, pool, Expr("SELECT pg_sleep(?)", 1))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, pool.stub.begun)
+	assert.Equal(t, 2, pool.stub.committed)
+
+	_, err = Update("users").Set("a", 1).ExecContext(ctx, pool)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, pool.stub.begun, "builders keep their own settings")
+}
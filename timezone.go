@@ -0,0 +1,22 @@
+package sqrl
+
+// AtTimeZone converts the timestamptz column col to the local time in the
+// time zone tz, which is bound as a parameter.
+//
+// Ex:
+//     Select().Column(Alias(AtTimeZone("created_at", "Europe/Berlin"), "local_created_at")).From("orders")
+//     == "SELECT (created_at AT TIME ZONE ?) AS local_created_at FROM orders", "Europe/Berlin"
+func AtTimeZone(col string, tz string) Sqlizer {
+	return Expr(col+" AT TIME ZONE ?", tz)
+}
+
+// ToTimestamptz interprets value, a timestamp without time zone like a
+// time.Time or a string, as local time in the time zone tz and converts it
+// to a timestamptz. Both are bound as parameters.
+//
+// Ex:
+//     .Where(GtOrEq{"created_at": ToTimestamptz("2020-05-01 00:00", "Europe/Berlin")})
+//     == "created_at >= ?::timestamp AT TIME ZONE ?", "2020-05-01 00:00", "Europe/Berlin"
+func ToTimestamptz(value interface{}, tz string) Sqlizer {
+	return Expr("?::timestamp AT TIME ZONE ?", value, tz)
+}
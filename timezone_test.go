@@ -0,0 +1,20 @@
+package sqrl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtTimeZone(t *testing.T) {
+	sql, args, err := Select().
+		Column(Alias(AtTimeZone("created_at", "Europe/Berlin"), "local_created_at")).
+		From("orders").
+		Where(GtOrEq{"created_at": ToTimestamptz("2020-05-01 00:00", "Europe/Berlin")}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT (created_at AT TIME ZONE $1) AS local_created_at FROM orders "+
+		"WHERE created_at >= $2::timestamp AT TIME ZONE $3", sql)
+	assert.Equal(t, []interface{}{"Europe/Berlin", "2020-05-01 00:00", "Europe/Berlin"}, args)
+}
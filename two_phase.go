@@ -0,0 +1,77 @@
+package sqrl
+
+import (
+	"context"
+	"fmt"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgconn"
+	"strings"
+)
+
+// maxTransactionIDLen is the limit Postgres puts on the length of prepared
+// transaction ids.
+const maxTransactionIDLen = 199
+
+// TwoPhaseBuilder builds SQL PREPARE TRANSACTION, COMMIT PREPARED and
+// ROLLBACK PREPARED statements for two-phase commit. PREPARE TRANSACTION
+// must be run inside the transaction to prepare, the others from any
+// session.
+//
+// The transaction id is inlined as a string literal, as these statements
+// can not use bound parameters.
+type TwoPhaseBuilder struct {
+	StatementBuilderType
+
+	kind string
+	id   string
+}
+
+// NewTwoPhaseBuilder creates new instance of TwoPhaseBuilder for the given
+// kind of statement, e.g. "PREPARE TRANSACTION" or "COMMIT PREPARED".
+func NewTwoPhaseBuilder(b StatementBuilderType, kind string) *TwoPhaseBuilder {
+	return &TwoPhaseBuilder{StatementBuilderType: b, kind: kind}
+}
+
+// ExecContext builds and Execs the statement using given context.
+func (b *TwoPhaseBuilder) ExecContext(ctx context.Context, pool instapgxpool.Pool) (pgconn.CommandTag, error) {
+	return b.execContext(ctx, pool, b)
+}
+
+// ExecAttached is like ExecContext, using the pool set with RunWithPool.
+func (b *TwoPhaseBuilder) ExecAttached(ctx context.Context) (pgconn.CommandTag, error) {
+	return b.ExecContext(ctx, nil)
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// statement.
+func (b *TwoPhaseBuilder) PlaceholderFormat(f PlaceholderFormat) *TwoPhaseBuilder {
+	b.placeholderFormat = f
+	return b
+}
+
+// ToSql builds the statement into a SQL string and bound args.
+func (b *TwoPhaseBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
+	if len(b.id) == 0 {
+		err = fmt.Errorf("%s statements must specify a transaction id", strings.ToLower(b.kind))
+		return
+	}
+	if len(b.id) > maxTransactionIDLen {
+		err = fmt.Errorf("transaction id must be at most %d bytes, got %d", maxTransactionIDLen, len(b.id))
+		return
+	}
+
+	// The statement has no placeholders, so it is not passed through
+	// ReplacePlaceholders, which would mistake a ? in the id for one.
+	id, err := quoteString(b.id)
+	if err != nil {
+		return
+	}
+	sqlStr = b.kind + " " + id
+	return
+}
+
+// ID sets the id of the prepared transaction.
+func (b *TwoPhaseBuilder) ID(id string) *TwoPhaseBuilder {
+	b.id = id
+	return b
+}
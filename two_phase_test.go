@@ -0,0 +1,31 @@
+package sqrl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTwoPhaseBuilderToSql(t *testing.T) {
+	sql, args, err := PrepareTransaction("saga-42").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "PREPARE TRANSACTION 'saga-42'", sql)
+	assert.Empty(t, args)
+
+	sql, _, err = CommitPrepared("saga-42").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "COMMIT PREPARED 'saga-42'", sql)
+
+	sql, _, err = RollbackPrepared("it's?").PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "ROLLBACK PREPARED 'it''s?'", sql)
+}
+
+func TestTwoPhaseBuilderToSqlErr(t *testing.T) {
+	_, _, err := CommitPrepared("").ToSql()
+	assert.EqualError(t, err, "commit prepared statements must specify a transaction id")
+
+	_, _, err = PrepareTransaction(strings.Repeat("a", 200)).ToSql()
+	assert.EqualError(t, err, "transaction id must be at most 199 bytes, got 200")
+}
@@ -0,0 +1,284 @@
+package sqrl
+
+import (
+	"context"
+	"fmt"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"regexp"
+	"strings"
+)
+
+// txBeginner is implemented by pools and transactions which are able to
+// start a (nested) transaction.
+type txBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// beginTx starts a new transaction on pool.
+func beginTx(ctx context.Context, pool instapgxpool.Pool) (pgx.Tx, error) {
+	beginner, ok := pool.(txBeginner)
+	if !ok {
+		return nil, fmt.Errorf("pool of type %T does not support transactions", pool)
+	}
+	return beginner.Begin(ctx)
+}
+
+// txOptionsBeginner is implemented by pools which are able to start a
+// transaction with options, like pgxpool.Pool.
+type txOptionsBeginner interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+// beginTxWithOptions starts a new transaction with opts on pool. Pools
+// without BeginTx get the options set with SET TRANSACTION.
+func beginTxWithOptions(ctx context.Context, pool instapgxpool.Pool, opts pgx.TxOptions) (pgx.Tx, error) {
+	if beginner, ok := pool.(txOptionsBeginner); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	tx, err := beginTx(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	var modes []string
+	if len(opts.IsoLevel) > 0 {
+		modes = append(modes, "ISOLATION LEVEL "+strings.ToUpper(string(opts.IsoLevel)))
+	}
+	if len(opts.AccessMode) > 0 {
+		modes = append(modes, strings.ToUpper(string(opts.AccessMode)))
+	}
+	if len(opts.DeferrableMode) > 0 {
+		modes = append(modes, strings.ToUpper(string(opts.DeferrableMode)))
+	}
+	if len(modes) > 0 {
+		if _, err := tx.Exec(ctx, "SET TRANSACTION "+strings.Join(modes, " ")); err != nil {
+			tx.Rollback(ctx)
+			return nil, err
+		}
+	}
+	return tx, nil
+}
+
+// txPool runs the statements of the execution helpers in tx. Other methods
+// of the pool, e.g. Close, are passed to the pool tx was started on.
+type txPool struct {
+	instapgxpool.Pool
+	tx pgx.Tx
+}
+
+func (p *txPool) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return p.tx.Exec(ctx, sql, args...)
+}
+
+func (p *txPool) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return p.tx.Query(ctx, sql, args...)
+}
+
+func (p *txPool) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return p.tx.QueryRow(ctx, sql, args...)
+}
+
+func (p *txPool) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	return p.tx.SendBatch(ctx, b)
+}
+
+// Begin starts a pseudo nested transaction, i.e. a savepoint, in tx.
+func (p *txPool) Begin(ctx context.Context) (pgx.Tx, error) {
+	return p.tx.Begin(ctx)
+}
+
+// runInTx runs fn with a pool running its statements in tx, committing tx
+// if fn succeeds and rolling it back otherwise, also if fn panics.
+func runInTx(ctx context.Context, pool instapgxpool.Pool, tx pgx.Tx, fn func(pool instapgxpool.Pool) error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback(ctx)
+			panic(p)
+		}
+	}()
+
+	if err = fn(&txPool{Pool: pool, tx: tx}); err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// TxOptions are the options of transactions started by RunInTx. The zero
+// value starts a READ WRITE transaction with the default isolation level of
+// the server, usually READ COMMITTED.
+type TxOptions struct {
+	// IsoLevel is the isolation level, e.g. pgx.Serializable.
+	IsoLevel pgx.TxIsoLevel
+	// AccessMode is pgx.ReadWrite or pgx.ReadOnly.
+	AccessMode pgx.TxAccessMode
+	// DeferrableMode is pgx.Deferrable or pgx.NotDeferrable. It only has an
+	// effect on SERIALIZABLE READ ONLY transactions.
+	DeferrableMode pgx.TxDeferrableMode
+}
+
+func (o TxOptions) validate() error {
+	switch o.IsoLevel {
+	case "", pgx.Serializable, pgx.RepeatableRead, pgx.ReadCommitted, pgx.ReadUncommitted:
+	default:
+		return fmt.Errorf("invalid transaction isolation level %q", o.IsoLevel)
+	}
+	switch o.AccessMode {
+	case "", pgx.ReadWrite, pgx.ReadOnly:
+	default:
+		return fmt.Errorf("invalid transaction access mode %q", o.AccessMode)
+	}
+	switch o.DeferrableMode {
+	case "", pgx.Deferrable, pgx.NotDeferrable:
+	default:
+		return fmt.Errorf("invalid transaction deferrable mode %q", o.DeferrableMode)
+	}
+	return nil
+}
+
+// pgxOptions returns o as pgx.TxOptions.
+func (o TxOptions) pgxOptions() pgx.TxOptions {
+	return pgx.TxOptions{IsoLevel: o.IsoLevel, AccessMode: o.AccessMode, DeferrableMode: o.DeferrableMode}
+}
+
+// RunInTx runs fn in a transaction started with opts. Statements run with
+// the pool passed to fn are run in the transaction, which is committed if
+// fn succeeds and rolled back otherwise, also if fn panics.
+//
+// If pool is the pool passed to fn by an enclosing RunInTx or ctx carries a
+// transaction set with ContextWithTx, fn is run within a savepoint of that
+// transaction instead, which is released if fn succeeds and rolled back to
+// otherwise, so functions using RunInTx compose without knowing whether
+// they are called in a transaction. The enclosing transaction determines the
+// options in this case; if it runs with a weaker isolation level or another
+// access mode than requested by opts, an error is returned instead of
+// running fn. Empty options accept any enclosing transaction.
+//
+// Ex:
+//     err := sqrl.RunInTx(ctx, pool, sqrl.TxOptions{IsoLevel: pgx.Serializable}, func(pool instapgxpool.Pool) error {
+//         var balance int64
+//         if err := sqrl.Select("balance").From("accounts").Where(sqrl.Eq{"id": from}).Scan(ctx, pool, &balance); err != nil {
+//             return err
+//         }
+//         ...
+//     })
+func RunInTx(ctx context.Context, pool instapgxpool.Pool, opts TxOptions, fn func(pool instapgxpool.Pool) error) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	if outer, ok := contextPool(ctx, pool).(*txPool); ok {
+		if err := checkNestedTxOptions(ctx, outer.tx, opts); err != nil {
+			return err
+		}
+		tx, err := outer.tx.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		return runInTx(ctx, outer.Pool, tx, fn)
+	}
+	tx, err := beginTxWithOptions(ctx, pool, opts.pgxOptions())
+	if err != nil {
+		return err
+	}
+	return runInTx(ctx, pool, tx, fn)
+}
+
+// isoLevelStrength orders the isolation levels by their guarantees. READ
+// UNCOMMITTED behaves like READ COMMITTED in PostgreSQL.
+var isoLevelStrength = map[pgx.TxIsoLevel]int{
+	pgx.ReadUncommitted: 1,
+	pgx.ReadCommitted:   1,
+	pgx.RepeatableRead:  2,
+	pgx.Serializable:    3,
+}
+
+// checkNestedTxOptions returns an error if tx, which a nested RunInTx runs
+// in, does not provide the isolation level and access mode of opts.
+func checkNestedTxOptions(ctx context.Context, tx pgx.Tx, opts TxOptions) error {
+	if opts.IsoLevel == "" && opts.AccessMode == "" {
+		return nil
+	}
+	var isoLevel, readOnly string
+	err := tx.QueryRow(ctx, "SELECT current_setting('transaction_isolation'), current_setting('transaction_read_only')").
+		Scan(&isoLevel, &readOnly)
+	if err != nil {
+		return err
+	}
+	if isoLevelStrength[pgx.TxIsoLevel(isoLevel)] < isoLevelStrength[opts.IsoLevel] {
+		return fmt.Errorf("nested transaction requires isolation level %s, but the enclosing transaction runs with %s", opts.IsoLevel, isoLevel)
+	}
+	accessMode := pgx.ReadWrite
+	if readOnly == "on" {
+		accessMode = pgx.ReadOnly
+	}
+	if opts.AccessMode != "" && opts.AccessMode != accessMode {
+		return fmt.Errorf("nested transaction requires %s access, but the enclosing transaction is %s", opts.AccessMode, accessMode)
+	}
+	return nil
+}
+
+// RunInReadTx runs fn in a READ ONLY transaction with isolation level
+// isolation, or the default level of the server if empty. Statements run
+// with the pool passed to fn see a consistent snapshot of the database with
+// pgx.RepeatableRead or pgx.Serializable, e.g. a page of results and their
+// total count. The transaction is committed if fn succeeds and rolled back
+// otherwise.
+//
+// Ex:
+//     err := sqrl.RunInReadTx(ctx, pool, pgx.RepeatableRead, func(pool instapgxpool.Pool) error {
+//         if err := q.Clone().Count("n").Scan(ctx, pool, &total); err != nil {
+//             return err
+//         }
+//         rows, err := q.Limit(50).QueryContext(ctx, pool)
+//         ...
+//     })
+func RunInReadTx(ctx context.Context, pool instapgxpool.Pool, isolation pgx.TxIsoLevel, fn func(pool instapgxpool.Pool) error) error {
+	return RunInTx(ctx, pool, TxOptions{IsoLevel: isolation, AccessMode: pgx.ReadOnly}, fn)
+}
+
+var savepointNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// WithSavepoint runs fn within savepoint name of tx. If fn returns an
+// error, the changes made by fn are rolled back to the savepoint and the
+// error is returned, while tx stays usable. Otherwise the savepoint is
+// released.
+//
+// Ex:
+//     for _, item := range batch {
+//         err := WithSavepoint(ctx, tx, "item", func(tx pgx.Tx) error {
+//             return importItem(ctx, tx, item)
+//         })
+//         if err != nil {
+//             failed = append(failed, item)
+//         }
+//     }
+func WithSavepoint(ctx context.Context, tx pgx.Tx, name string, fn func(tx pgx.Tx) error) (err error) {
+	if !savepointNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid savepoint name %q", name)
+	}
+	if _, err = tx.Exec(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Exec(ctx, "ROLLBACK TO SAVEPOINT "+name)
+			panic(p)
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		if _, rbErr := tx.Exec(ctx, "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+			return fmt.Errorf("failed to roll back to savepoint %s: %v (after: %v)", name, rbErr, err)
+		}
+		if _, rlErr := tx.Exec(ctx, "RELEASE SAVEPOINT "+name); rlErr != nil {
+			return fmt.Errorf("failed to release savepoint %s: %v (after: %v)", name, rlErr, err)
+		}
+		return err
+	}
+
+	_, err = tx.Exec(ctx, "RELEASE SAVEPOINT "+name)
+	return err
+}
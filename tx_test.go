@@ -0,0 +1,227 @@
+package sqrl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/clevabit/utils-go/instapgxpool"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithSavepoint(t *testing.T) {
+	stub := &pgxStub{}
+	tx := &txStub{stub: stub}
+
+	err := WithSavepoint(context.Background(), tx, "item", func(tx pgx.Tx) error {
+		_, err := tx.Exec(context.Background(), "INSERT INTO items VALUES (1)")
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"SAVEPOINT item",
+		"INSERT INTO items VALUES (1)",
+		"RELEASE SAVEPOINT item",
+	}, stub.sqls)
+}
+
+func TestWithSavepointRollback(t *testing.T) {
+	stub := &pgxStub{}
+	tx := &txStub{stub: stub}
+	fnErr := errors.New("duplicate item")
+
+	err := WithSavepoint(context.Background(), tx, "item", func(tx pgx.Tx) error {
+		return fnErr
+	})
+	assert.Equal(t, fnErr, err)
+	assert.Equal(t, []string{
+		"SAVEPOINT item",
+		"ROLLBACK TO SAVEPOINT item",
+		"RELEASE SAVEPOINT item",
+	}, stub.sqls)
+	assert.False(t, tx.closed)
+}
+
+func TestWithSavepointPanic(t *testing.T) {
+	stub := &pgxStub{}
+	tx := &txStub{stub: stub}
+
+	assert.Panics(t, func() {
+		WithSavepoint(context.Background(), tx, "item", func(tx pgx.Tx) error {
+			panic("boom")
+		})
+	})
+	assert.Equal(t, []string{"SAVEPOINT item", "ROLLBACK TO SAVEPOINT item"}, stub.sqls)
+}
+
+func TestWithSavepointInvalidName(t *testing.T) {
+	stub := &pgxStub{}
+	err := WithSavepoint(context.Background(), &txStub{stub: stub}, "a; DROP TABLE users", func(tx pgx.Tx) error {
+		return nil
+	})
+	assert.EqualError(t, err, `invalid savepoint name "a; DROP TABLE users"`)
+	assert.Empty(t, stub.sqls)
+}
+
+type txOptionsPoolStub struct {
+	*poolStub
+	opts []pgx.TxOptions
+}
+
+func (p *txOptionsPoolStub) BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	p.opts = append(p.opts, opts)
+	return p.Begin(ctx)
+}
+
+func TestRunInReadTx(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{2}}, {{1}, {2}}}
+
+	var total int
+	var ids []int
+	err := RunInReadTx(context.Background(), pool, pgx.RepeatableRead, func(pool instapgxpool.Pool) error {
+		q := Select("id").From("users")
+		if err := QueryRowWithContext(context.Background(), pool, q.Clone().Count("n")).Scan(&total); err != nil {
+			return err
+		}
+		var err error
+		ids, err = Pluck[int](context.Background(), pool, q.Limit(50))
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, []int{1, 2}, ids)
+	assert.Equal(t, []string{
+		"SET TRANSACTION ISOLATION LEVEL REPEATABLE READ READ ONLY",
+		"SELECT count(1) as n FROM users",
+		"SELECT id FROM users LIMIT 50",
+	}, pool.stub.sqls)
+	assert.Equal(t, 1, pool.stub.begun)
+	assert.Equal(t, 1, pool.stub.committed)
+}
+
+func TestRunInReadTxBeginTx(t *testing.T) {
+	pool := &txOptionsPoolStub{poolStub: newPoolStub()}
+	fnErr := errors.New("failed")
+
+	err := RunInReadTx(context.Background(), pool, "", func(pool instapgxpool.Pool) error {
+		_, err := ExecWithContext(context.Background(), pool, Expr("SELECT 1"))
+		assert.NoError(t, err)
+		return fnErr
+	})
+	assert.Equal(t, fnErr, err)
+	assert.Equal(t, []pgx.TxOptions{{AccessMode: pgx.ReadOnly}}, pool.opts)
+	assert.Equal(t, []string{"SELECT 1"}, pool.stub.sqls)
+	assert.Equal(t, 0, pool.stub.committed)
+	assert.Equal(t, 1, pool.stub.rolledBack)
+}
+
+func TestRunInReadTxPanic(t *testing.T) {
+	pool := newPoolStub()
+	assert.Panics(t, func() {
+		RunInReadTx(context.Background(), pool, pgx.Serializable, func(pool instapgxpool.Pool) error {
+			panic("boom")
+		})
+	})
+	assert.Equal(t, 1, pool.stub.rolledBack)
+}
+
+func TestRunInTx(t *testing.T) {
+	pool := &txOptionsPoolStub{poolStub: newPoolStub()}
+	opts := TxOptions{IsoLevel: pgx.Serializable, AccessMode: pgx.ReadOnly, DeferrableMode: pgx.Deferrable}
+
+	err := RunInTx(context.Background(), pool, opts, func(pool instapgxpool.Pool) error {
+		_, err := ExecWithContext(context.Background(), pool, Update("accounts").Set("balance", 0))
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []pgx.TxOptions{{IsoLevel: pgx.Serializable, AccessMode: pgx.ReadOnly, DeferrableMode: pgx.Deferrable}}, pool.opts)
+	assert.Equal(t, 1, pool.stub.committed)
+
+	plain := newPoolStub()
+	err = RunInTx(context.Background(), plain, opts, func(pool instapgxpool.Pool) error { return nil })
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"SET TRANSACTION ISOLATION LEVEL SERIALIZABLE READ ONLY DEFERRABLE"}, plain.stub.sqls)
+
+	plain = newPoolStub()
+	err = RunInTx(context.Background(), plain, TxOptions{}, func(pool instapgxpool.Pool) error { return nil })
+	assert.NoError(t, err)
+	assert.Empty(t, plain.stub.sqls)
+	assert.Equal(t, 1, plain.stub.committed)
+}
+
+func TestRunInTxInvalidOptions(t *testing.T) {
+	pool := newPoolStub()
+	err := RunInTx(context.Background(), pool, TxOptions{IsoLevel: "serializable; DROP TABLE users"}, func(pool instapgxpool.Pool) error {
+		t.Fatal("fn must not be called")
+		return nil
+	})
+	assert.EqualError(t, err, `invalid transaction isolation level "serializable; DROP TABLE users"`)
+	assert.Equal(t, 0, pool.stub.begun)
+
+	err = RunInTx(context.Background(), pool, TxOptions{AccessMode: "read"}, func(pool instapgxpool.Pool) error { return nil })
+	assert.EqualError(t, err, `invalid transaction access mode "read"`)
+}
+
+func TestRunInTxNested(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{{{"serializable", "off"}}}
+	fnErr := errors.New("duplicate item")
+
+	err := RunInTx(context.Background(), pool, TxOptions{}, func(outer instapgxpool.Pool) error {
+		err := RunInTx(context.Background(), outer, TxOptions{IsoLevel: pgx.RepeatableRead}, func(inner instapgxpool.Pool) error {
+			_, err := ExecWithContext(context.Background(), inner, Insert("items").Values(1))
+			return err
+		})
+		assert.NoError(t, err)
+
+		err = RunInTx(context.Background(), outer, TxOptions{}, func(inner instapgxpool.Pool) error {
+			return fnErr
+		})
+		assert.Equal(t, fnErr, err)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"SELECT current_setting('transaction_isolation'), current_setting('transaction_read_only')",
+		"INSERT INTO items VALUES (?)",
+	}, pool.stub.sqls)
+	assert.Equal(t, 3, pool.stub.begun)
+	assert.Equal(t, 2, pool.stub.committed)
+	assert.Equal(t, 1, pool.stub.rolledBack)
+}
+
+func TestRunInTxNestedStricterOptions(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.results = [][][]interface{}{
+		{{"read committed", "off"}},
+		{{"read committed", "off"}},
+		{{"repeatable read", "on"}},
+	}
+
+	err := RunInTx(context.Background(), pool, TxOptions{}, func(outer instapgxpool.Pool) error {
+		err := RunInTx(context.Background(), outer, TxOptions{IsoLevel: pgx.Serializable}, func(inner instapgxpool.Pool) error {
+			t.Fatal("fn must not be called")
+			return nil
+		})
+		assert.EqualError(t, err, "nested transaction requires isolation level serializable, but the enclosing transaction runs with read committed")
+
+		err = RunInReadTx(context.Background(), outer, "", func(inner instapgxpool.Pool) error {
+			t.Fatal("fn must not be called")
+			return nil
+		})
+		assert.EqualError(t, err, "nested transaction requires read only access, but the enclosing transaction is read write")
+		return nil
+	})
+	assert.NoError(t, err)
+
+	err = RunInReadTx(context.Background(), pool, pgx.RepeatableRead, func(outer instapgxpool.Pool) error {
+		return RunInReadTx(context.Background(), outer, pgx.ReadCommitted, func(inner instapgxpool.Pool) error {
+			return nil
+		})
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, pool.stub.committed)
+	assert.Equal(t, 0, pool.stub.rolledBack)
+}
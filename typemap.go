@@ -0,0 +1,77 @@
+package sqrl
+
+import (
+	"reflect"
+	"sync"
+)
+
+var typeEncoders = struct {
+	sync.RWMutex
+	encoders map[reflect.Type]func(interface{}) interface{}
+}{encoders: make(map[reflect.Type]func(interface{}) interface{})}
+
+// RegisterType registers encode to convert values of the custom type T to
+// the value bound for them, e.g. for domain IDs, enums or money amounts
+// which do not implement driver.Valuer. Encoded values are bound by Values,
+// Set, Eq and the other conditions, expression args and the args of SQL
+// strings, including the elements of lists and pointers to T in
+// conditions. Registering T again replaces its encoder.
+//
+// Ex:
+//     type UserID struct{ uuid.UUID }
+//
+//     sqrl.RegisterType(func(id UserID) interface{} { return id.String() })
+//     sqrl.Select("*").From("users").Where(sqrl.Eq{"id": userID})
+//     // id = ?, userID.String()
+func RegisterType[T interface{}](encode func(T) interface{}) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+
+	typeEncoders.Lock()
+	defer typeEncoders.Unlock()
+	typeEncoders.encoders[typ] = func(v interface{}) interface{} {
+		return encode(v.(T))
+	}
+}
+
+func typeEncoder(v interface{}) func(interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	typeEncoders.RLock()
+	defer typeEncoders.RUnlock()
+	return typeEncoders.encoders[reflect.TypeOf(v)]
+}
+
+// encodeArg returns the value bound for v: v converted by the encoder
+// registered with RegisterType for its type, or for the type it points to,
+// or derefArg(v).
+func encodeArg(v interface{}) interface{} {
+	if encode := typeEncoder(v); encode != nil {
+		return encode(v)
+	}
+	v = derefArg(v)
+	if encode := typeEncoder(v); encode != nil {
+		return encode(v)
+	}
+	return v
+}
+
+// encodeArgs converts the args of a verbatim SQL string with the encoders
+// registered with RegisterType. Other args are returned as is.
+func encodeArgs(args []interface{}) []interface{} {
+	var encoded []interface{}
+	for i, arg := range args {
+		encode := typeEncoder(arg)
+		if encode == nil {
+			continue
+		}
+		if encoded == nil {
+			encoded = append([]interface{}(nil), args...)
+		}
+		encoded[i] = encode(arg)
+	}
+	if encoded == nil {
+		return args
+	}
+	return encoded
+}
@@ -0,0 +1,59 @@
+package sqrl
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type typeMapUserID int
+
+type typeMapMoney struct {
+	cents int64
+}
+
+func init() {
+	RegisterType(func(id typeMapUserID) interface{} { return fmt.Sprintf("user-%d", id) })
+	RegisterType(func(m typeMapMoney) interface{} { return Expr("?::money", float64(m.cents)/100) })
+}
+
+func TestRegisterTypeConditions(t *testing.T) {
+	id := typeMapUserID(2)
+	sql, args, err := Select("*").From("users").
+		Where(Eq{"id": typeMapUserID(1)}).
+		Where(Eq{"parent_id": &id}).
+		Where(Eq{"friend_id": []typeMapUserID{3, 4}}).
+		Where("owner_id = ?", typeMapUserID(5)).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = ? AND parent_id = ? AND friend_id IN (?,?) AND owner_id = ?", sql)
+	assert.Equal(t, []interface{}{"user-1", "user-2", "user-3", "user-4", "user-5"}, args)
+
+	sql, args, err = Select("*").From("orders").Where(GtOrEq{"total": typeMapMoney{cents: 1050}}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM orders WHERE total >= ?::money", sql)
+	assert.Equal(t, []interface{}{10.5}, args)
+}
+
+func TestRegisterTypeValuesAndSet(t *testing.T) {
+	sql, args, err := Insert("orders").Columns("user_id", "total").Values(typeMapUserID(1), typeMapMoney{cents: 200}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO orders (user_id,total) VALUES (?,?::money)", sql)
+	assert.Equal(t, []interface{}{"user-1", 2.0}, args)
+
+	var nilID *typeMapUserID
+	sql, args, err = Update("orders").Set("user_id", nilID).Set("total", typeMapMoney{}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE orders SET user_id = ?, total = ?::money", sql)
+	assert.Equal(t, []interface{}{nil, 0.0}, args)
+
+	sql, args, err = Insert("orders").Columns("id", "user_id").Values(1, typeMapUserID(1)).
+		OnConflict("id").
+		DoUpdateSet("user_id", typeMapUserID(2)).
+		DoUpdateSetMap(map[string]interface{}{"total": typeMapMoney{cents: 300}}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO orders (id,user_id) VALUES (?,?) ON CONFLICT (id) DO UPDATE SET user_id = ?, total = ?::money", sql)
+	assert.Equal(t, []interface{}{1, "user-1", "user-2", 3.0}, args)
+}
@@ -10,6 +10,7 @@ func newUnionPart(pred *SelectBuilder) Sqlizer {
 }
 
 func (p unionPart) ToSql() (sql string, args []interface{}, err error) {
-	sql, args, err = p.expr.ToSql()
+	// ORDER BY is not allowed on the parts of a UNION.
+	sql, args, err = nested(p.expr).ToSql()
 	return
 }
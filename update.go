@@ -30,7 +30,9 @@ type UpdateBuilder struct {
 	fromParts  []Sqlizer
 	setClauses []setClause
 	whereParts []Sqlizer
+	currentOf  string
 	orderBys   []string
+	nullIfZero nullIfZeroColumns
 
 	limit       uint64
 	limitValid  bool
@@ -45,19 +47,34 @@ func NewUpdateBuilder(b StatementBuilderType) *UpdateBuilder {
 	return &UpdateBuilder{StatementBuilderType: b}
 }
 
-// ExecContext builds and Execs the query with the Runner set by RunWith using given context.
+// ExecContext builds and Execs the query using given context.
 func (b *UpdateBuilder) ExecContext(ctx context.Context, pool instapgxpool.Pool) (pgconn.CommandTag, error) {
-	return ExecWithContext(ctx, pool, b)
+	return b.execContext(ctx, pool, b)
+}
+
+// ExecAttached is like ExecContext, using the pool set with RunWithPool.
+func (b *UpdateBuilder) ExecAttached(ctx context.Context) (pgconn.CommandTag, error) {
+	return b.ExecContext(ctx, nil)
 }
 
 // QueryContext builds and runs the query using given context and Query command.
 func (b *UpdateBuilder) QueryContext(ctx context.Context, pool instapgxpool.Pool) (pgx.Rows, error) {
-	return QueryWithContext(ctx, pool, b)
+	return b.queryContext(ctx, pool, b)
+}
+
+// QueryAttached is like QueryContext, using the pool set with RunWithPool.
+func (b *UpdateBuilder) QueryAttached(ctx context.Context) (pgx.Rows, error) {
+	return b.QueryContext(ctx, nil)
 }
 
 // QueryRowContext builds and runs the query using given context.
 func (b *UpdateBuilder) QueryRowContext(ctx context.Context, pool instapgxpool.Pool) RowScanner {
-	return QueryRowWithContext(ctx, pool, b)
+	return b.queryRowContext(ctx, pool, b)
+}
+
+// QueryRowAttached is like QueryRowContext, using the pool set with RunWithPool.
+func (b *UpdateBuilder) QueryRowAttached(ctx context.Context) RowScanner {
+	return b.QueryRowContext(ctx, nil)
 }
 
 // Scan is a shortcut for QueryRow().Scan.
@@ -65,6 +82,47 @@ func (b *UpdateBuilder) Scan(ctx context.Context, pool instapgxpool.Pool, dest .
 	return b.QueryRowContext(ctx, pool).Scan(dest...)
 }
 
+// ScanContext is a shortcut for QueryRowAttached(ctx).Scan.
+func (b *UpdateBuilder) ScanContext(ctx context.Context, dest ...interface{}) error {
+	return b.QueryRowAttached(ctx).Scan(dest...)
+}
+
+// RunWithPool sets the pool used by ExecContext, QueryContext and
+// QueryRowContext when none is passed explicitly.
+func (b *UpdateBuilder) RunWithPool(pool instapgxpool.Pool) *UpdateBuilder {
+	b.runWithPool = pool
+	return b
+}
+
+// DryRun makes ExecContext, QueryContext and QueryRowContext return a
+// DryRunResult with the SQL and args instead of running the query.
+func (b *UpdateBuilder) DryRun() *UpdateBuilder {
+	b.dryRun = dryRunSQL
+	return b
+}
+
+// DryRunExplain is like DryRun, but also runs EXPLAIN for the query and
+// returns the plan with the DryRunResult.
+func (b *UpdateBuilder) DryRunExplain() *UpdateBuilder {
+	b.dryRun = dryRunExplain
+	return b
+}
+
+// SimpleProtocol makes ExecContext, QueryContext and QueryRowContext run the
+// query using the simple query protocol, with all args inlined as literals
+// (see ToSqlInlined).
+func (b *UpdateBuilder) SimpleProtocol() *UpdateBuilder {
+	b.simpleProtocol = true
+	return b
+}
+
+// Strict makes ToSql reject suspicious content in the strings interpolated
+// verbatim, see StatementBuilderType.Strict.
+func (b *UpdateBuilder) Strict() *UpdateBuilder {
+	b.strict = true
+	return b
+}
+
 // PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
 // query.
 func (b *UpdateBuilder) PlaceholderFormat(f PlaceholderFormat) *UpdateBuilder {
@@ -72,6 +130,25 @@ func (b *UpdateBuilder) PlaceholderFormat(f PlaceholderFormat) *UpdateBuilder {
 	return b
 }
 
+// MustSql builds the query like ToSql, but panics on errors. It is meant
+// for queries which are known to be valid, e.g. package level statements.
+func (b *UpdateBuilder) MustSql() (string, []interface{}) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// ToSqlInlined builds the query into a SQL string with all args inlined as
+// literals, e.g. for migration files or seed scripts. It fails for args
+// which can not be rendered as literals.
+func (b *UpdateBuilder) ToSqlInlined() (string, error) {
+	c := *b
+	c.placeholderFormat = Question
+	return toSqlInlined(&c)
+}
+
 // ToSql builds the query into a SQL string and bound args.
 func (b *UpdateBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 	if len(b.table) == 0 {
@@ -83,10 +160,24 @@ func (b *UpdateBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 		return
 	}
 
+	if b.strict {
+		if err = checkStrict(b); err != nil {
+			return
+		}
+	}
+	if err = b.schema.Validate(b); err != nil {
+		return
+	}
+	if err = b.whereConflicts.check(b.whereParts); err != nil {
+		return
+	}
+
 	sql := &bytes.Buffer{}
 
 	if len(b.prefixes) > 0 {
-		args, _ = b.prefixes.AppendToSql(sql, " ", args)
+		if args, err = b.prefixes.AppendToSql(sql, " ", args); err != nil {
+			return
+		}
 		sql.WriteString(" ")
 	}
 
@@ -97,7 +188,11 @@ func (b *UpdateBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 	setSqls := make([]string, len(b.setClauses))
 	for i, setClause := range b.setClauses {
 		var valSql string
-		switch typedVal := setClause.value.(type) {
+		val := b.nullIfZero.value(setClause.column, setClause.value)
+		if sb, ok := val.(*SelectBuilder); ok {
+			val = Subquery(sb)
+		}
+		switch typedVal := encodeArg(val).(type) {
 		case Sqlizer:
 			var valArgs []interface{}
 			valSql, valArgs, err = typedVal.ToSql()
@@ -121,9 +216,12 @@ func (b *UpdateBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 		}
 	}
 
-	if len(b.whereParts) > 0 {
-		sql.WriteString(" WHERE ")
-		args, err = appendToSql(b.whereParts, sql, " AND ", args)
+	if len(b.currentOf) > 0 {
+		if err = appendCurrentOf(sql, b.currentOf, b.whereParts); err != nil {
+			return
+		}
+	} else if len(b.whereParts) > 0 {
+		args, err = appendConditionsToSql([]Sqlizer{writeConditions(b.whereParts)}, sql, " WHERE ", args)
 		if err != nil {
 			return
 		}
@@ -154,7 +252,9 @@ func (b *UpdateBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 
 	if len(b.suffixes) > 0 {
 		sql.WriteString(" ")
-		args, _ = b.suffixes.AppendToSql(sql, " ", args)
+		if args, err = b.suffixes.AppendToSql(sql, " ", args); err != nil {
+			return
+		}
 	}
 
 	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
@@ -169,13 +269,22 @@ func (b *UpdateBuilder) Prefix(sql string, args ...interface{}) *UpdateBuilder {
 	return b
 }
 
+// PrefixExpr adds an expression built by a Sqlizer, e.g. a SelectBuilder
+// used as the body of a CTE, to the beginning of the query. Its args are
+// merged in order and its errors are returned by ToSql.
+func (b *UpdateBuilder) PrefixExpr(e Sqlizer) *UpdateBuilder {
+	b.prefixes = append(b.prefixes, Expr("?", e))
+	return b
+}
+
 // Table sets the table to be updateb.
 func (b *UpdateBuilder) Table(table string) *UpdateBuilder {
 	b.table = table
 	return b
 }
 
-// Set adds SET clauses to the query.
+// Set adds SET clauses to the query. A SelectBuilder value is added as a
+// subquery in parentheses, see Subquery.
 func (b *UpdateBuilder) Set(column string, value interface{}) *UpdateBuilder {
 	b.setClauses = append(b.setClauses, setClause{column: column, value: value})
 	return b
@@ -197,6 +306,22 @@ func (b *UpdateBuilder) SetMap(clauses map[string]interface{}) *UpdateBuilder {
 	return b
 }
 
+// WhereCurrentOf restricts the query to the row cursor is positioned on,
+// e.g. the Cursor of SelectBuilder.StreamCursor, to process the rows of a
+// cursor one by one. It can not be combined with Where.
+//
+// Ex:
+//     err := sqrl.Select("id").From("jobs").Suffix("FOR UPDATE").
+//         StreamCursor(ctx, pool, func(c *sqrl.Cursor) error {
+//             ...
+//             _, err := sqrl.Update("jobs").Set("done", true).WhereCurrentOf(c.Name).ExecContext(ctx, c.Pool)
+//             return err
+//         })
+func (b *UpdateBuilder) WhereCurrentOf(cursor string) *UpdateBuilder {
+	b.currentOf = cursor
+	return b
+}
+
 // Where adds WHERE expressions to the query.
 //
 // See SelectBuilder.Where for more information.
@@ -267,3 +392,10 @@ func (b *UpdateBuilder) Suffix(sql string, args ...interface{}) *UpdateBuilder {
 	b.suffixes = append(b.suffixes, Expr(sql, args...))
 	return b
 }
+
+// SuffixExpr adds an expression built by a Sqlizer to the end of the query.
+// Its args are merged in order and its errors are returned by ToSql.
+func (b *UpdateBuilder) SuffixExpr(e Sqlizer) *UpdateBuilder {
+	b.suffixes = append(b.suffixes, Expr("?", e))
+	return b
+}
@@ -67,6 +67,19 @@ func TestUpdateBuilderReturning(t *testing.T) {
 	assert.Equal(t, []interface{}{1, 42}, args)
 }
 
+func TestUpdateBuilderWhereCurrentOf(t *testing.T) {
+	sql, args, err := Update("a").Set("b", 1).WhereCurrentOf("c").Returning("d").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE a SET b = ? WHERE CURRENT OF c RETURNING d", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	_, _, err = Update("a").Set("b", 1).WhereCurrentOf("c").Where("d = ?", 2).ToSql()
+	assert.EqualError(t, err, "WHERE CURRENT OF can not be combined with other WHERE conditions")
+
+	_, _, err = Update("a").Set("b", 1).WhereCurrentOf("c; DROP TABLE a").ToSql()
+	assert.EqualError(t, err, `invalid cursor name "c; DROP TABLE a"`)
+}
+
 func TestUpdateBuilderZeroOffsetLimit(t *testing.T) {
 	qb := Update("a").
 		Set("b", true).
@@ -100,3 +113,28 @@ func TestUpdateBuilderPlaceholders(t *testing.T) {
 	sql, _, _ = b.PlaceholderFormat(Dollar).ToSql()
 	assert.Equal(t, "UPDATE test SET x = $1, y = $2", sql)
 }
+
+func TestUpdateBuilderSelectValue(t *testing.T) {
+	sql, args, err := Update("a").
+		Set("b", Select("max(c)").From("a").Where("d = ?", 2)).
+		Where("id = ?", 1).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE a SET b = (SELECT max(c) FROM a WHERE d = $1) WHERE id = $2", sql)
+	assert.Equal(t, []interface{}{2, 1}, args)
+}
+
+func TestUpdateBuilderToSqlInlined(t *testing.T) {
+	sql, err := Update("users").Set("name", "b").Where("id = ?", 1).PlaceholderFormat(Dollar).ToSqlInlined()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = 'b' WHERE id = 1", sql)
+}
+
+func TestUpdateBuilderPointerValues(t *testing.T) {
+	age := 42
+	var nick *string
+	sql, err := Update("users").Set("age", &age).Set("nick", nick).Where(Eq{"id": &age}).ToSqlInlined()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET age = 42, nick = NULL WHERE id = 42", sql)
+}
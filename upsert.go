@@ -0,0 +1,153 @@
+package sqrl
+
+import (
+	"context"
+	"fmt"
+	"github.com/clevabit/utils-go/instapgxpool"
+	"sort"
+)
+
+// maxUpsertChunk is the maximum number of rows UpsertAll inserts per
+// statement.
+const maxUpsertChunk = 1000
+
+// maxArgs is the limit Postgres puts on the number of bound parameters of a
+// statement.
+const maxArgs = 65535
+
+// UpsertAll inserts rows into table, updating updateCols of existing rows
+// whose keyCols conflict with an inserted row to the inserted values. All
+// rows must have the same columns. Without updateCols, conflicting rows are
+// skipped.
+//
+// Rows sharing the values of keyCols are upserted once, with the values of
+// the last of them, as Postgres can not update a row twice in one
+// statement. Rows are inserted with multi-row INSERT statements of at most
+// 1000 rows each, and fewer if needed to stay within the limits of Postgres
+// on bound parameters. The statements are not run atomically unless pool is
+// a transaction, e.g. the one passed to fn by RunInTx; if one fails, rows of
+// earlier statements stay upserted. UpsertAll returns the number of rows
+// inserted or updated.
+//
+// Ex:
+//     n, err := UpsertAll(ctx, pool, "users", []map[string]interface{}{
+//         {"email": "a@b.c", "name": "moe"},
+//         {"email": "d@e.f", "name": "larry"},
+//     }, []string{"email"}, []string{"name"})
+func UpsertAll(ctx context.Context, pool instapgxpool.Pool, table string, rows []map[string]interface{}, keyCols, updateCols []string) (int64, error) {
+	stmts, err := upsertStatements(table, rows, keyCols, updateCols)
+	if err != nil {
+		return 0, err
+	}
+
+	var affected int64
+	for _, stmt := range stmts {
+		tag, err := stmt.ExecContext(ctx, pool)
+		if err != nil {
+			return affected, err
+		}
+		affected += tag.RowsAffected()
+	}
+	return affected, nil
+}
+
+func upsertStatements(table string, rows []map[string]interface{}, keyCols, updateCols []string) ([]*InsertBuilder, error) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	if len(keyCols) == 0 && len(updateCols) > 0 {
+		return nil, fmt.Errorf("upserts updating columns must specify key columns")
+	}
+
+	columns := make([]string, 0, len(rows[0]))
+	for column := range rows[0] {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("upsert rows must have at least one column")
+	}
+
+	values := make([][]interface{}, len(rows))
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return nil, fmt.Errorf("upsert row %d has %d columns, expected %d", i, len(row), len(columns))
+		}
+		values[i] = make([]interface{}, len(columns))
+		for j, column := range columns {
+			value, ok := row[column]
+			if !ok {
+				return nil, fmt.Errorf("upsert row %d is missing column %q", i, column)
+			}
+			values[i][j] = value
+		}
+	}
+
+	values = dedupUpsertValues(values, columns, keyCols)
+
+	chunk := maxArgs / len(columns)
+	if chunk > maxUpsertChunk {
+		chunk = maxUpsertChunk
+	}
+
+	var stmts []*InsertBuilder
+	for len(values) > 0 {
+		n := chunk
+		if n > len(values) {
+			n = len(values)
+		}
+
+		b := StatementBuilder.PlaceholderFormat(Dollar).Insert(table).Columns(columns...)
+		for _, v := range values[:n] {
+			b.Values(v...)
+		}
+		values = values[n:]
+
+		conflict := b.OnConflict(keyCols...)
+		if len(updateCols) == 0 {
+			conflict.DoNothing()
+		}
+		for _, column := range updateCols {
+			conflict.DoUpdateSet(column, Expr("EXCLUDED."+column))
+		}
+		stmts = append(stmts, b)
+	}
+	return stmts, nil
+}
+
+// dedupUpsertValues removes all but the last of the values sharing the
+// values of keyCols, keeping the position of the first of them.
+func dedupUpsertValues(values [][]interface{}, columns, keyCols []string) [][]interface{} {
+	if len(keyCols) == 0 {
+		return values
+	}
+
+	keyIdx := make([]int, 0, len(keyCols))
+	for _, keyCol := range keyCols {
+		for j, column := range columns {
+			if column == keyCol {
+				keyIdx = append(keyIdx, j)
+			}
+		}
+	}
+	if len(keyIdx) != len(keyCols) {
+		return values
+	}
+
+	seen := make(map[string]int, len(values))
+	deduped := make([][]interface{}, 0, len(values))
+	for _, v := range values {
+		key := make([]interface{}, len(keyIdx))
+		for i, j := range keyIdx {
+			key[i] = v[j]
+		}
+		k := fmt.Sprintf("%#v", key)
+		if i, ok := seen[k]; ok {
+			deduped[i] = v
+			continue
+		}
+		seen[k] = len(deduped)
+		deduped = append(deduped, v)
+	}
+	return deduped
+}
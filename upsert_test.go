@@ -0,0 +1,102 @@
+package sqrl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpsertAll(t *testing.T) {
+	pool := newPoolStub()
+	pool.stub.tag = pgconn.CommandTag("INSERT 0 2")
+
+	n, err := UpsertAll(context.Background(), pool, "users", []map[string]interface{}{
+		{"email": "a@b.c", "name": "moe"},
+		{"email": "d@e.f", "name": "larry"},
+	}, []string{"email"}, []string{"name"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	assert.Equal(t, []string{"INSERT INTO users (email,name) VALUES ($1,$2),($3,$4) ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name"}, pool.stub.sqls)
+	assert.Equal(t, [][]interface{}{{"a@b.c", "moe", "d@e.f", "larry"}}, pool.stub.args)
+}
+
+func TestUpsertAllDoNothing(t *testing.T) {
+	stmts, err := upsertStatements("users", []map[string]interface{}{{"email": "a@b.c"}}, nil, nil)
+	assert.NoError(t, err)
+
+	sql, _, err := stmts[0].ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (email) VALUES ($1) ON CONFLICT DO NOTHING", sql)
+}
+
+func TestUpsertAllChunks(t *testing.T) {
+	rows := make([]map[string]interface{}, 2500)
+	for i := range rows {
+		rows[i] = map[string]interface{}{"id": i}
+	}
+	stmts, err := upsertStatements("t", rows, []string{"id"}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, stmts, 3)
+
+	wide := map[string]interface{}{}
+	for i := 0; i < 1000; i++ {
+		wide[fmt.Sprintf("c%d", i)] = i
+	}
+	wideRows := make([]map[string]interface{}, 100)
+	for i := range wideRows {
+		wideRows[i] = make(map[string]interface{}, len(wide))
+		for column, value := range wide {
+			wideRows[i][column] = value
+		}
+		wideRows[i]["c0"] = i
+	}
+	stmts, err = upsertStatements("t", wideRows, []string{"c0"}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, stmts, 2)
+
+	stmts, err = upsertStatements("t", rows[:0], []string{"id"}, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, stmts)
+}
+
+func TestUpsertAllDuplicateKeys(t *testing.T) {
+	rows := make([]map[string]interface{}, 1500)
+	for i := range rows {
+		rows[i] = map[string]interface{}{"email": fmt.Sprintf("%d@b.c", i), "name": "moe"}
+	}
+	rows[1200] = map[string]interface{}{"email": "1@b.c", "name": "larry"}
+
+	stmts, err := upsertStatements("users", rows, []string{"email"}, []string{"name"})
+	assert.NoError(t, err)
+	if assert.Len(t, stmts, 2) {
+		_, args, err := stmts[0].ToSql()
+		assert.NoError(t, err)
+		assert.Len(t, args, 2000)
+		assert.Equal(t, []interface{}{"1@b.c", "larry"}, args[2:4])
+
+		_, args, err = stmts[1].ToSql()
+		assert.NoError(t, err)
+		assert.Len(t, args, 998)
+	}
+
+	stmts, err = upsertStatements("users", rows[:2], nil, nil)
+	assert.NoError(t, err)
+	_, args, err := stmts[0].ToSql()
+	assert.NoError(t, err)
+	assert.Len(t, args, 4)
+}
+
+func TestUpsertAllErrors(t *testing.T) {
+	_, err := upsertStatements("users", []map[string]interface{}{{"email": "a@b.c"}}, nil, []string{"name"})
+	assert.EqualError(t, err, "upserts updating columns must specify key columns")
+
+	_, err = upsertStatements("users", []map[string]interface{}{{"email": "a@b.c"}, {"name": "moe"}}, []string{"email"}, nil)
+	assert.EqualError(t, err, `upsert row 1 is missing column "email"`)
+
+	_, err = upsertStatements("users", []map[string]interface{}{{"email": "a@b.c"}, {"email": "d@e.f", "name": "moe"}}, []string{"email"}, nil)
+	assert.EqualError(t, err, "upsert row 1 has 2 columns, expected 1")
+}
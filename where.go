@@ -1,6 +1,11 @@
 package sqrl
 
-import "fmt"
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
 
 type wherePart part
 
@@ -13,14 +18,119 @@ func (p wherePart) ToSql() (sql string, args []interface{}, err error) {
 	case nil:
 		// no-op
 	case Sqlizer:
-		return pred.ToSql()
+		return nested(pred).ToSql()
 	case map[string]interface{}:
 		return Eq(pred).ToSql()
 	case string:
 		sql = pred
-		args = p.args
+		args = encodeArgs(p.args)
 	default:
 		err = fmt.Errorf("expected string-keyed map or string, not %T", pred)
 	}
 	return
 }
+
+// appendConditionsToSql writes keyword followed by the parts joined with
+// AND. Nothing is written if all parts are empty, e.g. for Where(nil) or an
+// empty Eq.
+func appendConditionsToSql(parts []Sqlizer, w io.Writer, keyword string, args []interface{}) ([]interface{}, error) {
+	buf := &bytes.Buffer{}
+	args, err := appendToSql(parts, buf, " AND ", args)
+	if err != nil || buf.Len() == 0 {
+		return args, err
+	}
+
+	if _, err := io.WriteString(w, keyword); err != nil {
+		return nil, err
+	}
+	if _, err := buf.WriteTo(w); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+// writeConditions are the conditions of an UPDATE or DELETE statement,
+// joined with AND. Unlike in selects, conditions which all render empty,
+// e.g. an EqNotZero without remaining entries, yield FALSE: a missing
+// condition must not turn into a write on every row of the table.
+type writeConditions []Sqlizer
+
+func (c writeConditions) ToSql() (sql string, args []interface{}, err error) {
+	buf := &bytes.Buffer{}
+	if args, err = appendToSql(c, buf, " AND ", nil); err != nil {
+		return "", nil, err
+	}
+	if buf.Len() == 0 {
+		return "FALSE", nil, nil
+	}
+	return buf.String(), args, nil
+}
+
+// Group collects conditions for a nested group, joined with AND unless
+// created by Or. It is built with a callback so conditions can be added
+// dynamically, e.g. with WhereIf.
+type Group struct {
+	or    bool
+	parts []Sqlizer
+}
+
+// Where adds a condition to the group. pred is handled like in
+// SelectBuilder.Where.
+func (g *Group) Where(pred interface{}, args ...interface{}) *Group {
+	g.parts = append(g.parts, newWherePart(pred, args...))
+	return g
+}
+
+// WhereIf adds a condition to the group if cond is true.
+func (g *Group) WhereIf(cond bool, pred interface{}, args ...interface{}) *Group {
+	if cond {
+		g.Where(pred, args...)
+	}
+	return g
+}
+
+// And adds a nested group joined with AND.
+func (g *Group) And(fn func(g *Group)) *Group {
+	group := &Group{}
+	fn(group)
+	g.parts = append(g.parts, group)
+	return g
+}
+
+// Or adds a nested group joined with OR.
+func (g *Group) Or(fn func(g *Group)) *Group {
+	group := &Group{or: true}
+	fn(group)
+	g.parts = append(g.parts, group)
+	return g
+}
+
+// ToSql builds the query into a SQL string and bound args. A group of more
+// than one condition is wrapped in parentheses; an empty group yields an
+// empty string and is skipped by the WHERE and HAVING clauses.
+func (g *Group) ToSql() (sql string, args []interface{}, err error) {
+	sep := " AND "
+	if g.or {
+		sep = " OR "
+	}
+
+	var sqls []string
+	for _, part := range g.parts {
+		partSql, partArgs, err := part.ToSql()
+		if err != nil {
+			return "", nil, err
+		}
+		if len(partSql) > 0 {
+			sqls = append(sqls, partSql)
+			args = append(args, partArgs...)
+		}
+	}
+
+	switch len(sqls) {
+	case 0:
+		return "", nil, nil
+	case 1:
+		return sqls[0], args, nil
+	}
+	return "(" + strings.Join(sqls, sep) + ")", args, nil
+}
@@ -54,3 +54,89 @@ func TestWherePartMap(t *testing.T) {
 	test(m)
 	test(Eq(m))
 }
+
+func TestWherePredMatrix(t *testing.T) {
+	cases := []struct {
+		pred  interface{}
+		args  []interface{}
+		where string
+		want  []interface{}
+	}{
+		{nil, nil, "", nil},
+		{"", nil, "", nil},
+		{"x = ?", []interface{}{1}, " WHERE x = ?", []interface{}{1}},
+		{map[string]interface{}{"x": 1}, nil, " WHERE x = ?", []interface{}{1}},
+		{Eq{}, nil, "", nil},
+		{Eq{"x": 1}, nil, " WHERE x = ?", []interface{}{1}},
+		{Eq{"x": []int{1, 2}}, nil, " WHERE x IN (?,?)", []interface{}{1, 2}},
+		{NotEq{"x": nil}, nil, " WHERE x IS NOT NULL", nil},
+		{Gt{"x": 1}, nil, " WHERE x > ?", []interface{}{1}},
+		{And{}, nil, "", nil},
+		{And{Eq{"x": 1}, Lt{"y": 2}}, nil, " WHERE (x = ? AND y < ?)", []interface{}{1, 2}},
+		{Or{Eq{"x": 1}, Expr("y = ?", 2)}, nil, " WHERE (x = ? OR y = ?)", []interface{}{1, 2}},
+		{Expr("x = ANY(?)", []int{1}), nil, " WHERE x = ANY(?)", []interface{}{[]int{1}}},
+	}
+
+	for _, c := range cases {
+		builders := map[string]struct {
+			b      Sqlizer
+			prefix string
+			args   []interface{}
+		}{
+			"select": {Select("*").From("t").Where(c.pred, c.args...), "SELECT * FROM t", nil},
+			"update": {Update("t").Set("a", 0).Where(c.pred, c.args...), "UPDATE t SET a = ?", []interface{}{0}},
+			"delete": {Delete("t").Where(c.pred, c.args...), "DELETE FROM t", nil},
+		}
+		for name, b := range builders {
+			where := c.where
+			if where == "" && name != "select" {
+				where = " WHERE FALSE"
+			}
+			sql, args, err := b.b.ToSql()
+			if assert.NoError(t, err, "%s %#v", name, c.pred) {
+				assert.Equal(t, b.prefix+where, sql, "%s %#v", name, c.pred)
+				assert.Equal(t, append(b.args, c.want...), args, "%s %#v", name, c.pred)
+			}
+		}
+	}
+}
+
+func TestWhereEmptyPartsSkipped(t *testing.T) {
+	sql, args, err := Select("*").From("t").
+		Where(nil).Where("x = ?", 1).Where(Eq{}).Where("y = ?", 2).
+		GroupBy("x").Having(And{}).Having("count(*) > ?", 3).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE x = ? AND y = ? GROUP BY x HAVING count(*) > ?", sql)
+	assert.Equal(t, []interface{}{1, 2, 3}, args)
+
+	sql, _, err = Delete("t").Where(nil).Where("x = ?", 1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t WHERE x = ?", sql)
+}
+
+func TestWhereEmptyPartsInWrites(t *testing.T) {
+	sql, _, err := Update("t").Set("a", 1).Where(Eq{}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = ? WHERE FALSE", sql)
+
+	sql, _, err = Delete("t").Where(nil).Where(And{}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t WHERE FALSE", sql)
+
+	sql, _, err = Delete("t").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t", sql)
+}
+
+func TestWherePredErr(t *testing.T) {
+	for _, b := range []Sqlizer{
+		Select("*").From("t").Where(1),
+		Select("*").From("t").Having(1),
+		Update("t").Set("a", 1).Where(1),
+		Delete("t").Where(1),
+	} {
+		_, _, err := b.ToSql()
+		assert.Error(t, err)
+	}
+}